@@ -271,6 +271,7 @@ func (d *DefaultDispatcher) Dispatch(ctx context.Context, destination net.Destin
 			result, err := sniffer(ctx, cReader, sniffingRequest.MetadataOnly, destination.Network)
 			if err == nil {
 				content.Protocol = result.Protocol()
+				content.TLS = tlsInfo(result)
 			}
 			if err == nil && d.shouldOverride(ctx, result, sniffingRequest, destination) {
 				domain := result.Domain()
@@ -325,6 +326,7 @@ func (d *DefaultDispatcher) DispatchLink(ctx context.Context, destination net.De
 		result, err := sniffer(ctx, cReader, sniffingRequest.MetadataOnly, destination.Network)
 		if err == nil {
 			content.Protocol = result.Protocol()
+			content.TLS = tlsInfo(result)
 		}
 		if err == nil && d.shouldOverride(ctx, result, sniffingRequest, destination) {
 			domain := result.Domain()
@@ -437,6 +439,8 @@ func (d *DefaultDispatcher) routedDispatch(ctx context.Context, link *transport.
 					errors.LogInfo(ctx, "Hit route rule: [", route.GetRuleTag(), "] so taking detour [", outTag, "] for [", destination, "]")
 				}
 				handler = h
+				ob.FragmentProfile = route.GetFragmentProfile()
+				ob.NoiseProfile = route.GetNoiseProfile()
 			} else {
 				errors.LogWarning(ctx, "non existing outTag: ", outTag)
 			}
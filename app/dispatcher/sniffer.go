@@ -11,6 +11,7 @@ import (
 	"github.com/GFW-knocker/Xray-core/common/protocol/http"
 	"github.com/GFW-knocker/Xray-core/common/protocol/quic"
 	"github.com/GFW-knocker/Xray-core/common/protocol/tls"
+	"github.com/GFW-knocker/Xray-core/common/session"
 )
 
 type SniffResult interface {
@@ -18,6 +19,30 @@ type SniffResult interface {
 	Domain() string
 }
 
+// TLSSniffResult is a SniffResult carrying the metadata of a TLS client hello.
+type TLSSniffResult interface {
+	ALPN() []string
+	TLSVersion() uint16
+	ECH() bool
+}
+
+// tlsInfo returns the client hello metadata of result, if any.
+func tlsInfo(result SniffResult) *session.TLSInfo {
+	if c, ok := result.(*compositeResult); ok {
+		result = c.protocolResult
+	}
+	r, ok := result.(TLSSniffResult)
+	if !ok {
+		return nil
+	}
+	return &session.TLSInfo{
+		ALPN:    r.ALPN(),
+		Version: r.TLSVersion(),
+		ECH:     r.ECH(),
+		SNI:     result.Domain() != "",
+	}
+}
+
 type protocolSniffer func(context.Context, []byte) (SniffResult, error)
 
 type protocolSnifferWithMetadata struct {
@@ -23,3 +23,9 @@ func (w *SizeStatWriter) Close() error {
 func (w *SizeStatWriter) Interrupt() {
 	common.Interrupt(w.Writer)
 }
+
+// WrapInner wraps the writer w counts for with wrap, so that w stays the
+// outermost writer of the link, visible to splice.
+func (w *SizeStatWriter) WrapInner(wrap func(buf.Writer) buf.Writer) {
+	w.Writer = wrap(w.Writer)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	go_errors "errors"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/GFW-knocker/Xray-core/common"
@@ -12,16 +13,73 @@ import (
 	"github.com/GFW-knocker/Xray-core/common/signal/pubsub"
 	"github.com/GFW-knocker/Xray-core/common/task"
 	dns_feature "github.com/GFW-knocker/Xray-core/features/dns"
+	mdns "github.com/miekg/dns"
 	"golang.org/x/net/dns/dnsmessage"
 )
 
+// refreshTimeout is how long a refresh of an answer may take before the answer
+// is refreshed again.
+const refreshTimeout = time.Second * 10
+
+// failureRecheck is how long a stale answer is served without refreshing it
+// again after the refresh failed, the failure recheck timer of RFC 8767.
+const failureRecheck = time.Second * 30
+
+// defaultMaxStale is how long answers are served after they expire by default,
+// within the 1 to 3 days RFC 8767 suggests.
+const defaultMaxStale = time.Hour * 24
+
+// prefetchHits is the least cache hits of an answer for it to be prefetched.
+const prefetchHits = 2
+
+// CacheOptions configures how a CacheController serves expired answers.
+type CacheOptions struct {
+	// ServeStale serves expired answers while refreshing them, as in RFC 8767.
+	ServeStale bool
+	// MaxStale is how long answers are served after they expire. 0 for
+	// defaultMaxStale.
+	MaxStale time.Duration
+	// Prefetch refreshes answers hit repeatedly shortly before they expire.
+	Prefetch bool
+}
+
+// cachedServer is a Server which caches its answers in a CacheController.
+type cachedServer interface {
+	Server
+	getCacheController() *CacheController
+	sendQuery(ctx context.Context, noResponseErrCh chan<- error, domain string, option dns_feature.IPOption)
+}
+
+// prefetchState decides when a cached answer is prefetched.
+type prefetchState struct {
+	at   time.Time
+	hits atomic.Uint32
+}
+
+// reset tracks an answer expiring at expire, which is prefetched in the last
+// tenth of its TTL.
+func (p *prefetchState) reset(expire time.Time) {
+	p.at = expire.Add(-time.Until(expire) / 10)
+	p.hits.Store(0)
+}
+
+// hit counts a cache hit of the answer, and returns whether it is due for
+// prefetch.
+func (p *prefetchState) hit() bool {
+	return p.hits.Add(1) >= prefetchHits && time.Now().After(p.at)
+}
+
 type CacheController struct {
 	sync.RWMutex
 	ips          map[string]*record
+	records      map[recordKey]*RecordSet
 	pub          *pubsub.Service
 	cacheCleanup *task.Periodic
 	name         string
 	disableCache bool
+	options      CacheOptions
+	sendQuery    func(ctx context.Context, noResponseErrCh chan<- error, domain string, option dns_feature.IPOption)
+	refreshing   map[string]time.Time // answers which aren't refreshed again until the time
 }
 
 func NewCacheController(name string, disableCache bool) *CacheController {
@@ -29,7 +87,9 @@ func NewCacheController(name string, disableCache bool) *CacheController {
 		name:         name,
 		disableCache: disableCache,
 		ips:          make(map[string]*record),
+		records:      make(map[recordKey]*RecordSet),
 		pub:          pubsub.NewService(),
+		refreshing:   make(map[string]time.Time),
 	}
 
 	c.cacheCleanup = &task.Periodic{
@@ -39,21 +99,72 @@ func NewCacheController(name string, disableCache bool) *CacheController {
 	return c
 }
 
+// SetOptions sets how c serves expired answers. sendQuery is used to refresh
+// the answers of A and AAAA queries.
+func (c *CacheController) SetOptions(options CacheOptions, sendQuery func(ctx context.Context, noResponseErrCh chan<- error, domain string, option dns_feature.IPOption)) {
+	c.Lock()
+	defer c.Unlock()
+	c.options = options
+	c.sendQuery = sendQuery
+}
+
+// staleWindow returns how long answers are served after they expire.
+func (c *CacheController) staleWindow() time.Duration {
+	if !c.options.ServeStale {
+		return 0
+	}
+	if c.options.MaxStale <= 0 {
+		return defaultMaxStale
+	}
+	return c.options.MaxStale
+}
+
+// expired returns whether an answer expiring at expire can no longer be served.
+func (c *CacheController) expired(expire time.Time, now time.Time) bool {
+	return now.Sub(expire) > c.staleWindow()
+}
+
+// keepsAnswer returns whether an answer which can be served until expire, and
+// is one if rcode is success, is kept instead of an answer with newRCode. As
+// RFC 8767 section 5 says, failures of the server don't replace answers which
+// can still be served.
+func (c *CacheController) keepsAnswer(rcode int, expire time.Time, newRCode int, now time.Time) bool {
+	if c.disableCache || rcode != mdns.RcodeSuccess {
+		return false
+	}
+	if newRCode != mdns.RcodeServerFailure && newRCode != mdns.RcodeRefused {
+		return false
+	}
+	return !c.expired(expire, now)
+}
+
 // CacheCleanup clears expired items from cache
 func (c *CacheController) CacheCleanup() error {
 	now := time.Now()
 	c.Lock()
 	defer c.Unlock()
 
-	if len(c.ips) == 0 {
+	if len(c.ips) == 0 && len(c.records) == 0 {
 		return errors.New("nothing to do. stopping...")
 	}
 
+	for key, until := range c.refreshing {
+		if now.After(until) {
+			delete(c.refreshing, key)
+		}
+	}
+
+	for key, set := range c.records {
+		if c.expired(set.Expire, now) {
+			delete(c.records, key)
+		}
+	}
+
 	for domain, record := range c.ips {
-		if record.A != nil && record.A.Expire.Before(now) {
+		if record.A != nil && c.expired(record.A.Expire, now) {
 			record.A = nil
 		}
-		if record.AAAA != nil && record.AAAA.Expire.Before(now) {
+		if record.AAAA != nil && c.expired(record.AAAA.Expire, now) {
 			record.AAAA = nil
 		}
 
@@ -75,27 +186,46 @@ func (c *CacheController) CacheCleanup() error {
 func (c *CacheController) updateIP(req *dnsRequest, ipRec *IPRecord) {
 	elapsed := time.Since(req.start)
 
+	now := time.Now()
+
 	c.Lock()
 	rec, found := c.ips[req.domain]
 	if !found {
 		rec = &record{}
 	}
 
+	kept := false
 	switch req.reqType {
 	case dnsmessage.TypeA:
-		rec.A = ipRec
+		if kept = rec.A != nil && c.keepsAnswer(int(rec.A.RCode), rec.A.Expire, int(ipRec.RCode), now); kept {
+			c.refreshing[req.domain+"4"] = now.Add(failureRecheck)
+		} else {
+			rec.A = ipRec
+			rec.prefetchA.reset(ipRec.Expire)
+			delete(c.refreshing, req.domain+"4")
+		}
 	case dnsmessage.TypeAAAA:
-		rec.AAAA = ipRec
+		if kept = rec.AAAA != nil && c.keepsAnswer(int(rec.AAAA.RCode), rec.AAAA.Expire, int(ipRec.RCode), now); kept {
+			c.refreshing[req.domain+"6"] = now.Add(failureRecheck)
+		} else {
+			rec.AAAA = ipRec
+			rec.prefetchAAAA.reset(ipRec.Expire)
+			delete(c.refreshing, req.domain+"6")
+		}
 	}
 
-	errors.LogInfo(context.Background(), c.name, " got answer: ", req.domain, " ", req.reqType, " -> ", ipRec.IP, " ", elapsed)
+	if kept {
+		errors.LogInfo(context.Background(), c.name, " keeps the cached answer of ", req.domain, " ", req.reqType, " over ", ipRec.RCode, " ", elapsed)
+	} else {
+		errors.LogInfo(context.Background(), c.name, " got answer: ", req.domain, " ", req.reqType, " -> ", ipRec.IP, " ", elapsed)
+	}
 	c.ips[req.domain] = rec
 
 	switch req.reqType {
 	case dnsmessage.TypeA:
 		c.pub.Publish(req.domain+"4", nil)
 		if !c.disableCache {
-			_, _, err := rec.AAAA.getIPs()
+			_, _, err := rec.AAAA.getIPs(0)
 			if !go_errors.Is(err, errRecordNotFound) {
 				c.pub.Publish(req.domain+"6", nil)
 			}
@@ -103,7 +233,7 @@ func (c *CacheController) updateIP(req *dnsRequest, ipRec *IPRecord) {
 	case dnsmessage.TypeAAAA:
 		c.pub.Publish(req.domain+"6", nil)
 		if !c.disableCache {
-			_, _, err := rec.A.getIPs()
+			_, _, err := rec.A.getIPs(0)
 			if !go_errors.Is(err, errRecordNotFound) {
 				c.pub.Publish(req.domain+"4", nil)
 			}
@@ -114,9 +244,114 @@ func (c *CacheController) updateIP(req *dnsRequest, ipRec *IPRecord) {
 	common.Must(c.cacheCleanup.Start())
 }
 
+type recordKey struct {
+	domain string
+	qtype  uint16
+}
+
+// refreshKey returns the key of the records of qtype for domain in refreshing.
+func refreshKey(domain string, qtype uint16) string {
+	return domain + mdns.Type(qtype).String()
+}
+
+func (c *CacheController) updateRecords(domain string, qtype uint16, set *RecordSet) {
+	now := time.Now()
+	key := recordKey{domain, qtype}
+	c.Lock()
+	if old := c.records[key]; old != nil && c.keepsAnswer(old.RCode, old.Expire, set.RCode, now) {
+		c.refreshing[refreshKey(domain, qtype)] = now.Add(failureRecheck)
+		c.Unlock()
+		errors.LogInfo(context.Background(), c.name, " keeps the cached answer of ", domain, " ", mdns.Type(qtype), " over ", mdns.RcodeToString[set.RCode])
+		return
+	}
+	set.prefetch.reset(set.Expire)
+	c.records[key] = set
+	delete(c.refreshing, refreshKey(domain, qtype))
+	c.Unlock()
+	errors.LogInfo(context.Background(), c.name, " got answer: ", domain, " ", mdns.Type(qtype), " -> ", len(set.RR), " record(s)")
+	common.Must(c.cacheCleanup.Start())
+}
+
+func (c *CacheController) findRecordsForDomain(domain string, qtype uint16) ([]mdns.RR, uint32, error) {
+	c.RLock()
+	set := c.records[recordKey{domain, qtype}]
+	stale := c.staleWindow()
+	c.RUnlock()
+	return set.getRecords(stale)
+}
+
+// recordsNeedRefresh counts a cache hit of the records of qtype for domain, and
+// returns whether they are stale or due for prefetch.
+func (c *CacheController) recordsNeedRefresh(domain string, qtype uint16) bool {
+	c.RLock()
+	defer c.RUnlock()
+	set := c.records[recordKey{domain, qtype}]
+	if set == nil {
+		return false
+	}
+	return set.Expire.Before(time.Now()) || c.options.Prefetch && set.prefetch.hit()
+}
+
+// startRefresh returns whether the answer of key is not being refreshed, nor
+// held off after a failed refresh, and marks it refreshing if so. The mark is
+// cleared by the answer, or else expires after refreshTimeout.
+func (c *CacheController) startRefresh(key string) bool {
+	c.Lock()
+	defer c.Unlock()
+	now := time.Now()
+	if until, found := c.refreshing[key]; found && now.Before(until) {
+		return false
+	}
+	c.refreshing[key] = now.Add(refreshTimeout)
+	return true
+}
+
+// findCachedIPs is findIPsForDomain for answering queries from the cache. The
+// answers which are stale or due for prefetch are refreshed in background.
+func (c *CacheController) findCachedIPs(ctx context.Context, domain string, option dns_feature.IPOption) ([]net.IP, uint32, error) {
+	ips, ttl, err := c.findIPsForDomain(domain, option)
+	if go_errors.Is(err, errRecordNotFound) {
+		return ips, ttl, err
+	}
+	markCacheHit(ctx)
+
+	c.RLock()
+	sendQuery := c.sendQuery
+	refresh := dns_feature.IPOption{}
+	if rec := c.ips[domain]; rec != nil && sendQuery != nil {
+		now := time.Now()
+		needRefresh := func(ipRec *IPRecord, prefetch *prefetchState) bool {
+			if ipRec == nil {
+				return false
+			}
+			return ipRec.Expire.Before(now) || c.options.Prefetch && prefetch.hit()
+		}
+		refresh.IPv4Enable = option.IPv4Enable && needRefresh(rec.A, &rec.prefetchA)
+		refresh.IPv6Enable = option.IPv6Enable && needRefresh(rec.AAAA, &rec.prefetchAAAA)
+	}
+	c.RUnlock()
+
+	if refresh.IPv4Enable && !c.startRefresh(domain+"4") {
+		refresh.IPv4Enable = false
+	}
+	if refresh.IPv6Enable && !c.startRefresh(domain+"6") {
+		refresh.IPv6Enable = false
+	}
+	if refresh.IPv4Enable || refresh.IPv6Enable {
+		errors.LogDebug(ctx, c.name, " refreshing ", domain)
+		sendQuery(context.WithoutCancel(ctx), make(chan error, 2), domain, refresh)
+	}
+	return ips, ttl, err
+}
+
 func (c *CacheController) findIPsForDomain(domain string, option dns_feature.IPOption) ([]net.IP, uint32, error) {
 	c.RLock()
 	record, found := c.ips[domain]
+	var a, aaaa *IPRecord
+	if found {
+		a, aaaa = record.A, record.AAAA
+	}
+	stale := c.staleWindow()
 	c.RUnlock()
 
 	if !found {
@@ -130,7 +365,7 @@ func (c *CacheController) findIPsForDomain(domain string, option dns_feature.IPO
 	mergeReq := option.IPv4Enable && option.IPv6Enable
 
 	if option.IPv4Enable {
-		ips, ttl, err := record.A.getIPs()
+		ips, ttl, err := a.getIPs(stale)
 		if !mergeReq || go_errors.Is(err, errRecordNotFound) {
 			return ips, ttl, err
 		}
@@ -145,7 +380,7 @@ func (c *CacheController) findIPsForDomain(domain string, option dns_feature.IPO
 	}
 
 	if option.IPv6Enable {
-		ips, ttl, err := record.AAAA.getIPs()
+		ips, ttl, err := aaaa.getIPs(stale)
 		if !mergeReq || go_errors.Is(err, errRecordNotFound) {
 			return ips, ttl, err
 		}
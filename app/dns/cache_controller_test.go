@@ -0,0 +1,242 @@
+package dns
+
+import (
+	"context"
+	go_errors "errors"
+	"net/url"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GFW-knocker/Xray-core/common"
+	"github.com/GFW-knocker/Xray-core/common/net"
+	dns_feature "github.com/GFW-knocker/Xray-core/features/dns"
+	"github.com/google/go-cmp/cmp"
+	"github.com/miekg/dns"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// refreshRecorder records the refreshes of a CacheController.
+type refreshRecorder struct {
+	sync.Mutex
+	options []dns_feature.IPOption
+}
+
+func (r *refreshRecorder) sendQuery(_ context.Context, _ chan<- error, _ string, option dns_feature.IPOption) {
+	r.Lock()
+	defer r.Unlock()
+	r.options = append(r.options, option)
+}
+
+func (r *refreshRecorder) count() int {
+	r.Lock()
+	defer r.Unlock()
+	return len(r.options)
+}
+
+func newTestCacheController(options CacheOptions, expire time.Time) (*CacheController, *refreshRecorder) {
+	c := NewCacheController("test", false)
+	r := &refreshRecorder{}
+	c.SetOptions(options, r.sendQuery)
+	c.updateIP(&dnsRequest{
+		reqType: dnsmessage.TypeA,
+		domain:  "example.com.",
+		start:   time.Now(),
+	}, &IPRecord{
+		IP:     []net.IP{{1, 2, 3, 4}},
+		Expire: expire,
+		RCode:  dnsmessage.RCodeSuccess,
+	})
+	return c, r
+}
+
+func TestCacheControllerServeStale(t *testing.T) {
+	ipv4 := dns_feature.IPOption{IPv4Enable: true}
+	expire := time.Now().Add(-time.Minute)
+
+	c, r := newTestCacheController(CacheOptions{}, expire)
+	if _, _, err := c.findCachedIPs(context.Background(), "example.com.", ipv4); !go_errors.Is(err, errRecordNotFound) {
+		t.Error("expect expired answers not to be served, got ", err)
+	}
+
+	c, r = newTestCacheController(CacheOptions{ServeStale: true, MaxStale: time.Second * 30}, expire)
+	if _, _, err := c.findCachedIPs(context.Background(), "example.com.", ipv4); !go_errors.Is(err, errRecordNotFound) {
+		t.Error("expect answers expired for longer than MaxStale not to be served, got ", err)
+	}
+
+	c, r = newTestCacheController(CacheOptions{ServeStale: true}, time.Now().Add(-defaultMaxStale-time.Minute))
+	if _, _, err := c.findCachedIPs(context.Background(), "example.com.", ipv4); !go_errors.Is(err, errRecordNotFound) {
+		t.Error("expect answers expired for longer than the default window not to be served, got ", err)
+	}
+
+	c, r = newTestCacheController(CacheOptions{ServeStale: true}, expire)
+	for i := 0; i < 2; i++ {
+		ips, ttl, err := c.findCachedIPs(context.Background(), "example.com.", ipv4)
+		common.Must(err)
+		if r := cmp.Diff(ips, []net.IP{{1, 2, 3, 4}}); r != "" {
+			t.Error(r)
+		}
+		if ttl != staleTTL {
+			t.Error("unexpected TTL of stale answer: ", ttl)
+		}
+	}
+	if n := r.count(); n != 1 {
+		t.Error("expect the stale answer to be refreshed once, got ", n)
+	}
+	if r.options[0] != ipv4 {
+		t.Error("unexpected refresh: ", r.options[0])
+	}
+
+	c.CacheCleanup()
+	if _, _, err := c.findIPsForDomain("example.com.", ipv4); err != nil {
+		t.Error("expect the stale answer to be kept by cleanup, got ", err)
+	}
+}
+
+func TestCacheControllerServeStaleFailure(t *testing.T) {
+	ipv4 := dns_feature.IPOption{IPv4Enable: true}
+	c, r := newTestCacheController(CacheOptions{ServeStale: true}, time.Now().Add(-time.Minute))
+	_, _, err := c.findCachedIPs(context.Background(), "example.com.", ipv4)
+	common.Must(err)
+	c.updateIP(&dnsRequest{
+		reqType: dnsmessage.TypeA,
+		domain:  "example.com.",
+		start:   time.Now(),
+	}, &IPRecord{
+		Expire: time.Now().Add(time.Minute),
+		RCode:  dnsmessage.RCodeServerFailure,
+	})
+	ips, _, err := c.findCachedIPs(context.Background(), "example.com.", ipv4)
+	common.Must(err)
+	if r := cmp.Diff(ips, []net.IP{{1, 2, 3, 4}}); r != "" {
+		t.Error(r)
+	}
+	if n := r.count(); n != 1 {
+		t.Error("expect no refresh right after a failed one, got ", n)
+	}
+
+	c.updateIP(&dnsRequest{
+		reqType: dnsmessage.TypeA,
+		domain:  "example.com.",
+		start:   time.Now(),
+	}, &IPRecord{
+		Expire: time.Now().Add(time.Minute),
+		RCode:  dnsmessage.RCodeNameError,
+	})
+	if _, _, err := c.findIPsForDomain("example.com.", ipv4); dns_feature.RCodeFromError(err) != uint16(dnsmessage.RCodeNameError) {
+		t.Error("expect the stale answer to be replaced by NXDOMAIN, got ", err)
+	}
+
+	txt, err := dns.NewRR("example.com. 60 IN TXT \"hello\"")
+	common.Must(err)
+	c.updateRecords("example.com.", dns.TypeTXT, &RecordSet{
+		RR:     []dns.RR{txt},
+		Expire: time.Now().Add(-time.Minute),
+	})
+	c.updateRecords("example.com.", dns.TypeTXT, &RecordSet{
+		Expire: time.Now().Add(time.Minute),
+		RCode:  dns.RcodeRefused,
+	})
+	if rrs, _, err := c.findRecordsForDomain("example.com.", dns.TypeTXT); err != nil || len(rrs) != 1 {
+		t.Error("expect the stale records to be kept, got ", rrs, err)
+	}
+	if c.startRefresh(refreshKey("example.com.", dns.TypeTXT)) {
+		t.Error("expect no refresh right after a failed one")
+	}
+}
+
+func TestCacheControllerPrefetch(t *testing.T) {
+	hit := func(c *CacheController) {
+		_, _, err := c.findCachedIPs(context.Background(), "example.com.", dns_feature.IPOption{IPv4Enable: true})
+		common.Must(err)
+	}
+
+	c, r := newTestCacheController(CacheOptions{Prefetch: true}, time.Now().Add(time.Minute))
+	hit(c)
+	hit(c)
+	if n := r.count(); n != 0 {
+		t.Error("expect no prefetch early in the TTL, got ", n)
+	}
+
+	c, r = newTestCacheController(CacheOptions{Prefetch: true}, time.Now().Add(time.Minute))
+	c.ips["example.com."].prefetchA.at = time.Now()
+	hit(c)
+	if n := r.count(); n != 0 {
+		t.Error("expect no prefetch of unpopular answers, got ", n)
+	}
+	hit(c)
+	if n := r.count(); n != 1 {
+		t.Error("expect popular answers to be prefetched, got ", n)
+	}
+}
+
+func TestCacheControllerSnapshot(t *testing.T) {
+	c, _ := newTestCacheController(CacheOptions{}, time.Now().Add(time.Minute))
+	c.updateIP(&dnsRequest{
+		reqType: dnsmessage.TypeAAAA,
+		domain:  "expired.example.",
+		start:   time.Now(),
+	}, &IPRecord{
+		IP:     []net.IP{net.ParseIP("2001::1")},
+		Expire: time.Now().Add(-time.Minute),
+	})
+	txt, err := dns.NewRR("example.com. 60 IN TXT \"hello\"")
+	common.Must(err)
+	c.updateRecords("example.com.", dns.TypeTXT, &RecordSet{
+		RR:     []dns.RR{txt},
+		Expire: time.Now().Add(time.Minute),
+	})
+
+	entries := c.snapshot()
+	if len(entries) != 2 {
+		t.Fatal("expect expired answers not to be saved, got ", entries)
+	}
+
+	restored := NewCacheController("test", false)
+	restored.restore(entries)
+	ips, _, err := restored.findIPsForDomain("example.com.", dns_feature.IPOption{IPv4Enable: true})
+	common.Must(err)
+	if r := cmp.Diff(ips, []net.IP{{1, 2, 3, 4}}); r != "" {
+		t.Error(r)
+	}
+	rrs, _, err := restored.findRecordsForDomain("example.com.", dns.TypeTXT)
+	common.Must(err)
+	if len(rrs) != 1 || rrs[0].String() != txt.String() {
+		t.Error("unexpected records: ", rrs)
+	}
+}
+
+func TestDNSCacheFile(t *testing.T) {
+	newDNS := func() (*DNS, *CacheController) {
+		u, err := url.Parse("tcp+local://127.0.0.1:53")
+		common.Must(err)
+		server, err := NewTCPLocalNameServer(u, false, nil)
+		common.Must(err)
+		return &DNS{
+			clients:   []*Client{{server: server}},
+			cacheFile: filepath.Join(t.TempDir(), "dns.cache"),
+		}, server.cacheController
+	}
+
+	s, c := newDNS()
+	common.Must(s.loadCache())
+	c.updateIP(&dnsRequest{
+		reqType: dnsmessage.TypeA,
+		domain:  "example.com.",
+		start:   time.Now(),
+	}, &IPRecord{
+		IP:     []net.IP{{1, 2, 3, 4}},
+		Expire: time.Now().Add(time.Minute),
+	})
+	common.Must(s.saveCache())
+
+	restored, c := newDNS()
+	restored.cacheFile = s.cacheFile
+	common.Must(restored.loadCache())
+	ips, _, err := c.findIPsForDomain("example.com.", dns_feature.IPOption{IPv4Enable: true})
+	common.Must(err)
+	if r := cmp.Diff(ips, []net.IP{{1, 2, 3, 4}}); r != "" {
+		t.Error(r)
+	}
+}
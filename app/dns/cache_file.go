@@ -0,0 +1,203 @@
+package dns
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/GFW-knocker/Xray-core/common"
+	"github.com/GFW-knocker/Xray-core/common/errors"
+	"github.com/GFW-knocker/Xray-core/common/net"
+	"github.com/GFW-knocker/Xray-core/common/platform/filesystem"
+	mdns "github.com/miekg/dns"
+	"golang.org/x/net/dns/dnsmessage"
+	"google.golang.org/protobuf/proto"
+)
+
+// cacheSaveInterval is how often the DNS cache is saved to the cache file.
+const cacheSaveInterval = time.Minute * 10
+
+// snapshot returns the answers in c which can still be served.
+func (c *CacheController) snapshot() []*CacheEntry {
+	now := time.Now()
+	c.RLock()
+	defer c.RUnlock()
+
+	var entries []*CacheEntry
+	addIPs := func(domain string, qtype uint16, r *IPRecord) {
+		if r == nil || c.expired(r.Expire, now) {
+			return
+		}
+		entry := &CacheEntry{
+			Server: c.name,
+			Domain: domain,
+			Type:   uint32(qtype),
+			Rcode:  int32(r.RCode),
+			Expire: r.Expire.Unix(),
+		}
+		for _, ip := range r.IP {
+			entry.Ip = append(entry.Ip, ip)
+		}
+		entries = append(entries, entry)
+	}
+	for domain, rec := range c.ips {
+		addIPs(domain, mdns.TypeA, rec.A)
+		addIPs(domain, mdns.TypeAAAA, rec.AAAA)
+	}
+
+	for key, set := range c.records {
+		if c.expired(set.Expire, now) {
+			continue
+		}
+		entry := &CacheEntry{
+			Server: c.name,
+			Domain: key.domain,
+			Type:   uint32(key.qtype),
+			Rcode:  int32(set.RCode),
+			Expire: set.Expire.Unix(),
+		}
+		for _, rr := range set.RR {
+			b := make([]byte, mdns.Len(rr))
+			off, err := mdns.PackRR(rr, b, 0, nil, false)
+			if err != nil {
+				continue
+			}
+			entry.Record = append(entry.Record, b[:off])
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// restore puts the answers of entries, which can still be served, into c.
+func (c *CacheController) restore(entries []*CacheEntry) {
+	now := time.Now()
+	restored := 0
+	c.Lock()
+	for _, entry := range entries {
+		expire := time.Unix(entry.Expire, 0)
+		if c.expired(expire, now) {
+			continue
+		}
+		switch qtype := uint16(entry.Type); qtype {
+		case mdns.TypeA, mdns.TypeAAAA:
+			ipRec := &IPRecord{
+				RCode:  dnsmessage.RCode(entry.Rcode),
+				Expire: expire,
+			}
+			for _, ip := range entry.Ip {
+				ipRec.IP = append(ipRec.IP, net.IP(ip))
+			}
+			rec, found := c.ips[entry.Domain]
+			if !found {
+				rec = &record{}
+				c.ips[entry.Domain] = rec
+			}
+			if qtype == mdns.TypeA {
+				rec.A = ipRec
+				rec.prefetchA.reset(expire)
+			} else {
+				rec.AAAA = ipRec
+				rec.prefetchAAAA.reset(expire)
+			}
+		default:
+			set := &RecordSet{
+				RCode:  int(entry.Rcode),
+				Expire: expire,
+			}
+			for _, b := range entry.Record {
+				rr, _, err := mdns.UnpackRR(b, 0)
+				if err != nil {
+					continue
+				}
+				set.RR = append(set.RR, rr)
+			}
+			set.prefetch.reset(expire)
+			c.records[recordKey{entry.Domain, qtype}] = set
+		}
+		restored++
+	}
+	c.Unlock()
+
+	if restored > 0 {
+		errors.LogDebug(context.Background(), c.name, " restored ", restored, " cached answer(s)")
+		common.Must(c.cacheCleanup.Start())
+	}
+}
+
+// cacheControllers returns the caches of the clients of s, one for each name
+// server name.
+func (s *DNS) cacheControllers() []*CacheController {
+	var controllers []*CacheController
+	names := make(map[string]bool)
+	for _, client := range s.clients {
+		server, ok := client.server.(cachedServer)
+		if !ok {
+			continue
+		}
+		c := server.getCacheController()
+		if c.disableCache || names[c.name] {
+			continue
+		}
+		names[c.name] = true
+		controllers = append(controllers, c)
+	}
+	return controllers
+}
+
+// loadCache restores the caches of the clients of s from the cache file.
+func (s *DNS) loadCache() error {
+	b, err := filesystem.ReadFile(s.cacheFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	snapshot := new(CacheSnapshot)
+	if err := proto.Unmarshal(b, snapshot); err != nil {
+		return errors.New("failed to parse DNS cache file").Base(err)
+	}
+
+	entries := make(map[string][]*CacheEntry)
+	for _, entry := range snapshot.Entry {
+		entries[entry.Server] = append(entries[entry.Server], entry)
+	}
+	for _, client := range s.clients {
+		if server, ok := client.server.(cachedServer); ok {
+			if c := server.getCacheController(); !c.disableCache {
+				c.restore(entries[c.name])
+			}
+		}
+	}
+	return nil
+}
+
+// saveCache saves the caches of the clients of s to the cache file.
+func (s *DNS) saveCache() error {
+	snapshot := new(CacheSnapshot)
+	for _, c := range s.cacheControllers() {
+		snapshot.Entry = append(snapshot.Entry, c.snapshot()...)
+	}
+	b, err := proto.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first, so that the cache file is never left
+	// half written
+	f, err := os.CreateTemp(filepath.Dir(s.cacheFile), filepath.Base(s.cacheFile)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), s.cacheFile)
+}
@@ -0,0 +1,144 @@
+package command
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/GFW-knocker/Xray-core/app/dns"
+	"github.com/GFW-knocker/Xray-core/app/stats"
+	"github.com/GFW-knocker/Xray-core/common"
+	"github.com/GFW-knocker/Xray-core/common/errors"
+	"github.com/GFW-knocker/Xray-core/core"
+	feature_dns "github.com/GFW-knocker/Xray-core/features/dns"
+	feature_stats "github.com/GFW-knocker/Xray-core/features/stats"
+	"google.golang.org/grpc"
+)
+
+// defaultTopDomains is the number of most queried domains got by default.
+const defaultTopDomains = 10
+
+type service struct {
+	UnimplementedDNSServiceServer
+
+	dns          feature_dns.Client
+	statsManager feature_stats.Manager
+}
+
+// NewDNSServer creates a DNS service with the DNS client and stats manager.
+func NewDNSServer(client feature_dns.Client, statsManager feature_stats.Manager) DNSServiceServer {
+	return &service{
+		dns:          client,
+		statsManager: statsManager,
+	}
+}
+
+func (s *service) SubscribeQueryLog(request *SubscribeQueryLogRequest, stream DNSService_SubscribeQueryLogServer) error {
+	publisher, ok := s.dns.(dns.QueryLogPublisher)
+	if !ok {
+		return errors.New("DNS does not publish query logs")
+	}
+	subscriber, err := feature_stats.SubscribeRunnableChannel(publisher.QueryLogChannel())
+	if err != nil {
+		return err
+	}
+	defer feature_stats.UnsubscribeClosableChannel(publisher.QueryLogChannel(), subscriber)
+
+	for {
+		select {
+		case value, ok := <-subscriber:
+			if !ok {
+				return errors.New("upstream closed the subscriber channel")
+			}
+			entry, ok := value.(*dns.QueryLog)
+			if !ok {
+				return errors.New("upstream sent malformed query log")
+			}
+			if err := stream.Send(entry); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+func (s *service) GetQueryStats(ctx context.Context, request *GetQueryStatsRequest) (*GetQueryStatsResponse, error) {
+	counter, ok := s.dns.(dns.DomainCounter)
+	if !ok {
+		return nil, errors.New("DNS does not count queries")
+	}
+	manager, ok := s.statsManager.(*stats.Manager)
+	if !ok {
+		return nil, errors.New("GetQueryStats only works with its own stats.Manager")
+	}
+
+	top := int(request.TopDomains)
+	if top == 0 {
+		top = defaultTopDomains
+	}
+	response := &GetQueryStatsResponse{}
+	for _, c := range counter.TopDomains(top, request.Reset_) {
+		response.Domain = append(response.Domain, &DomainStat{
+			Domain:  c.Domain,
+			Queries: c.Count,
+		})
+	}
+
+	servers := make(map[string]*ServerStat)
+	manager.VisitCounters(func(name string, c feature_stats.Counter) bool {
+		parts := strings.Split(name, ">>>")
+		if len(parts) != 4 || parts[0] != "dns" || parts[1] != "server" {
+			return true
+		}
+		var value int64
+		if request.Reset_ {
+			value = c.Set(0)
+		} else {
+			value = c.Value()
+		}
+		stat, found := servers[parts[2]]
+		if !found {
+			stat = &ServerStat{Server: parts[2]}
+			servers[parts[2]] = stat
+		}
+		switch parts[3] {
+		case "query":
+			stat.Queries = value
+		case "failure":
+			stat.Failures = value
+		case "cache_hit":
+			stat.CacheHits = value
+		}
+		return true
+	})
+
+	for _, stat := range servers {
+		if stat.Queries > 0 {
+			stat.FailureRate = float64(stat.Failures) / float64(stat.Queries)
+		}
+		response.Server = append(response.Server, stat)
+	}
+	sort.Slice(response.Server, func(i, j int) bool {
+		return response.Server[i].Server < response.Server[j].Server
+	})
+	return response, nil
+}
+
+func (s *service) Register(server *grpc.Server) {
+	RegisterDNSServiceServer(server, s)
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, cfg interface{}) (interface{}, error) {
+		s := new(service)
+		err := core.RequireFeatures(ctx, func(client feature_dns.Client, sm feature_stats.Manager) {
+			s.dns = client
+			s.statsManager = sm
+		})
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	}))
+}
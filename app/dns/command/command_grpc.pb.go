@@ -0,0 +1,164 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.2
+// source: app/dns/command/command.proto
+
+package command
+
+import (
+	context "context"
+	dns "github.com/GFW-knocker/Xray-core/app/dns"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	DNSService_SubscribeQueryLog_FullMethodName = "/xray.app.dns.command.DNSService/SubscribeQueryLog"
+	DNSService_GetQueryStats_FullMethodName     = "/xray.app.dns.command.DNSService/GetQueryStats"
+)
+
+// DNSServiceClient is the client API for DNSService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DNSServiceClient interface {
+	SubscribeQueryLog(ctx context.Context, in *SubscribeQueryLogRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[dns.QueryLog], error)
+	GetQueryStats(ctx context.Context, in *GetQueryStatsRequest, opts ...grpc.CallOption) (*GetQueryStatsResponse, error)
+}
+
+type dNSServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDNSServiceClient(cc grpc.ClientConnInterface) DNSServiceClient {
+	return &dNSServiceClient{cc}
+}
+
+func (c *dNSServiceClient) SubscribeQueryLog(ctx context.Context, in *SubscribeQueryLogRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[dns.QueryLog], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DNSService_ServiceDesc.Streams[0], DNSService_SubscribeQueryLog_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeQueryLogRequest, dns.QueryLog]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DNSService_SubscribeQueryLogClient = grpc.ServerStreamingClient[dns.QueryLog]
+
+func (c *dNSServiceClient) GetQueryStats(ctx context.Context, in *GetQueryStatsRequest, opts ...grpc.CallOption) (*GetQueryStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetQueryStatsResponse)
+	err := c.cc.Invoke(ctx, DNSService_GetQueryStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DNSServiceServer is the server API for DNSService service.
+// All implementations must embed UnimplementedDNSServiceServer
+// for forward compatibility.
+type DNSServiceServer interface {
+	SubscribeQueryLog(*SubscribeQueryLogRequest, grpc.ServerStreamingServer[dns.QueryLog]) error
+	GetQueryStats(context.Context, *GetQueryStatsRequest) (*GetQueryStatsResponse, error)
+	mustEmbedUnimplementedDNSServiceServer()
+}
+
+// UnimplementedDNSServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedDNSServiceServer struct{}
+
+func (UnimplementedDNSServiceServer) SubscribeQueryLog(*SubscribeQueryLogRequest, grpc.ServerStreamingServer[dns.QueryLog]) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeQueryLog not implemented")
+}
+func (UnimplementedDNSServiceServer) GetQueryStats(context.Context, *GetQueryStatsRequest) (*GetQueryStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQueryStats not implemented")
+}
+func (UnimplementedDNSServiceServer) mustEmbedUnimplementedDNSServiceServer() {}
+func (UnimplementedDNSServiceServer) testEmbeddedByValue()                    {}
+
+// UnsafeDNSServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DNSServiceServer will
+// result in compilation errors.
+type UnsafeDNSServiceServer interface {
+	mustEmbedUnimplementedDNSServiceServer()
+}
+
+func RegisterDNSServiceServer(s grpc.ServiceRegistrar, srv DNSServiceServer) {
+	// If the following call pancis, it indicates UnimplementedDNSServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&DNSService_ServiceDesc, srv)
+}
+
+func _DNSService_SubscribeQueryLog_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeQueryLogRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DNSServiceServer).SubscribeQueryLog(m, &grpc.GenericServerStream[SubscribeQueryLogRequest, dns.QueryLog]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DNSService_SubscribeQueryLogServer = grpc.ServerStreamingServer[dns.QueryLog]
+
+func _DNSService_GetQueryStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetQueryStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).GetQueryStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_GetQueryStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).GetQueryStats(ctx, req.(*GetQueryStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DNSService_ServiceDesc is the grpc.ServiceDesc for DNSService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DNSService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "xray.app.dns.command.DNSService",
+	HandlerType: (*DNSServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetQueryStats",
+			Handler:    _DNSService_GetQueryStats_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeQueryLog",
+			Handler:       _DNSService_SubscribeQueryLog_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "app/dns/command/command.proto",
+}
@@ -0,0 +1,141 @@
+package command_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GFW-knocker/Xray-core/app/dispatcher"
+	"github.com/GFW-knocker/Xray-core/app/dns"
+	. "github.com/GFW-knocker/Xray-core/app/dns/command"
+	"github.com/GFW-knocker/Xray-core/app/policy"
+	"github.com/GFW-knocker/Xray-core/app/proxyman"
+	_ "github.com/GFW-knocker/Xray-core/app/proxyman/outbound"
+	"github.com/GFW-knocker/Xray-core/app/stats"
+	"github.com/GFW-knocker/Xray-core/common"
+	"github.com/GFW-knocker/Xray-core/common/net"
+	"github.com/GFW-knocker/Xray-core/common/serial"
+	"github.com/GFW-knocker/Xray-core/core"
+	feature_dns "github.com/GFW-knocker/Xray-core/features/dns"
+	feature_stats "github.com/GFW-knocker/Xray-core/features/stats"
+	"github.com/GFW-knocker/Xray-core/proxy/freedom"
+	"github.com/GFW-knocker/Xray-core/testing/servers/udp"
+	mdns "github.com/miekg/dns"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestDNSService(t *testing.T) {
+	upstreamPort := udp.PickPort()
+	upstream := mdns.Server{
+		Addr: "127.0.0.1:" + upstreamPort.String(),
+		Net:  "udp",
+		Handler: mdns.HandlerFunc(func(w mdns.ResponseWriter, r *mdns.Msg) {
+			ans := new(mdns.Msg)
+			ans.SetReply(r)
+			if q := r.Question[0]; q.Name == "example.com." && q.Qtype == mdns.TypeA {
+				rr, _ := mdns.NewRR("example.com. 300 IN A 1.2.3.4")
+				ans.Answer = append(ans.Answer, rr)
+			} else {
+				ans.Rcode = mdns.RcodeNameError
+			}
+			w.WriteMsg(ans)
+		}),
+	}
+	defer upstream.Shutdown()
+	go upstream.ListenAndServe()
+	time.Sleep(time.Second)
+
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dns.Config{
+				NameServer: []*dns.NameServer{
+					{
+						Address: &net.Endpoint{
+							Network: net.Network_UDP,
+							Address: net.NewIPOrDomain(net.LocalHostIP),
+							Port:    uint32(upstreamPort),
+						},
+					},
+				},
+				QueryStrategy: dns.QueryStrategy_USE_IP4,
+				QueryStats:    true,
+			}),
+			serial.ToTypedMessage(&stats.Config{}),
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&policy.Config{}),
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	})
+	common.Must(err)
+	common.Must(v.Start())
+	defer v.Close()
+
+	client := v.GetFeature(feature_dns.ClientType()).(feature_dns.Client)
+	statsManager := v.GetFeature(feature_stats.ManagerType()).(feature_stats.Manager)
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	RegisterDNSServiceServer(server, NewDNSServer(client, statsManager))
+	go server.Serve(lis)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet", grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+		return lis.Dial()
+	}), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	common.Must(err)
+	defer conn.Close()
+	dnsService := NewDNSServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := dnsService.SubscribeQueryLog(ctx, &SubscribeQueryLogRequest{})
+	common.Must(err)
+	channel := client.(dns.QueryLogPublisher).QueryLogChannel()
+	for len(channel.Subscribers()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	for _, domain := range []string{"example.com", "example.com", "notexist.example.com"} {
+		client.LookupIP(domain, feature_dns.IPOption{IPv4Enable: true})
+	}
+
+	expected := []struct {
+		domain   string
+		cacheHit bool
+		answer   string
+		rcode    int32
+	}{
+		{"example.com", false, "1.2.3.4", 0},
+		{"example.com", true, "1.2.3.4", 0},
+		{"notexist.example.com", false, "", mdns.RcodeNameError},
+	}
+	for _, e := range expected {
+		entry, err := stream.Recv()
+		common.Must(err)
+		if entry.Domain != e.domain || entry.Qtype != "A" || entry.CacheHit != e.cacheHit || entry.Rcode != e.rcode || entry.Error != "" {
+			t.Error("expect ", e, ", but got ", entry)
+		}
+		if e.answer != "" && (len(entry.Answer) != 1 || entry.Answer[0] != e.answer) {
+			t.Error("unexpected answer: ", entry.Answer)
+		}
+	}
+
+	resp, err := dnsService.GetQueryStats(ctx, &GetQueryStatsRequest{TopDomains: 1})
+	common.Must(err)
+	if len(resp.Domain) != 1 || resp.Domain[0].Domain != "example.com" || resp.Domain[0].Queries != 2 {
+		t.Error("unexpected domain stats: ", resp.Domain)
+	}
+	if len(resp.Server) != 1 {
+		t.Fatal("unexpected server stats: ", resp.Server)
+	}
+	if s := resp.Server[0]; s.Queries != 3 || s.CacheHits != 1 || s.Failures != 0 || s.FailureRate != 0 {
+		t.Error("unexpected server stats: ", s)
+	}
+}
@@ -8,13 +8,18 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/GFW-knocker/Xray-core/common"
 	"github.com/GFW-knocker/Xray-core/common/errors"
 	"github.com/GFW-knocker/Xray-core/common/net"
 	"github.com/GFW-knocker/Xray-core/common/session"
 	"github.com/GFW-knocker/Xray-core/common/strmatcher"
+	"github.com/GFW-knocker/Xray-core/common/task"
+	"github.com/GFW-knocker/Xray-core/core"
 	"github.com/GFW-knocker/Xray-core/features/dns"
+	feature_stats "github.com/GFW-knocker/Xray-core/features/stats"
+	mdns "github.com/miekg/dns"
 )
 
 // DNS is a DNS rely server.
@@ -29,6 +34,9 @@ type DNS struct {
 	domainMatcher          strmatcher.IndexMatcher
 	matcherInfos           []*DomainMatcherInfo
 	checkSystem            bool
+	cacheFile              string
+	cacheSave              *task.Periodic
+	queryLog               *queryRecorder
 }
 
 // DomainMatcherInfo contains information attached to index returned by Server.domainMatcher
@@ -100,6 +108,22 @@ func New(ctx context.Context, config *Config) (*DNS, error) {
 	matcherInfos := make([]*DomainMatcherInfo, domainRuleCount+1)
 	domainMatcher := &strmatcher.MatcherGroup{}
 
+	cacheOptions := CacheOptions{
+		ServeStale: config.ServeStale,
+		MaxStale:   time.Duration(config.ServeExpiredTtl) * time.Second,
+		Prefetch:   config.Prefetch,
+	}
+
+	queryLog := newQueryRecorder(ctx)
+	if config.QueryStats {
+		queryLog.domains = newTopDomains(topDomainsCapacity)
+		if err := core.RequireFeatures(ctx, func(m feature_stats.Manager) {
+			queryLog.statsManager = m
+		}); err != nil {
+			return nil, err
+		}
+	}
+
 	for _, ns := range config.NameServer {
 		clientIdx := len(clients)
 		updateDomain := func(domainRule strmatcher.Matcher, originalRuleIdx int, matcherInfos []*DomainMatcherInfo) error {
@@ -132,15 +156,21 @@ func New(ctx context.Context, config *Config) (*DNS, error) {
 		if err != nil {
 			return nil, errors.New("failed to create client").Base(err)
 		}
+		if server, ok := client.server.(cachedServer); ok {
+			server.getCacheController().SetOptions(cacheOptions, server.sendQuery)
+		}
+		client.queryLog = queryLog
 		clients = append(clients, client)
 	}
 
 	// If there is no DNS client in config, add a `localhost` DNS client
 	if len(clients) == 0 {
-		clients = append(clients, NewLocalDNSClient(ipOption))
+		client := NewLocalDNSClient(ipOption)
+		client.queryLog = queryLog
+		clients = append(clients, client)
 	}
 
-	return &DNS{
+	s := &DNS{
 		hosts:                  hosts,
 		ipOption:               &ipOption,
 		clients:                clients,
@@ -150,7 +180,21 @@ func New(ctx context.Context, config *Config) (*DNS, error) {
 		disableFallback:        config.DisableFallback,
 		disableFallbackIfMatch: config.DisableFallbackIfMatch,
 		checkSystem:            checkSystem,
-	}, nil
+		cacheFile:              config.CacheFile,
+		queryLog:               queryLog,
+	}
+	if len(s.cacheFile) > 0 {
+		s.cacheSave = &task.Periodic{
+			Interval: cacheSaveInterval,
+			Execute: func() error {
+				if err := s.saveCache(); err != nil {
+					errors.LogWarningInner(ctx, err, "failed to save DNS cache to ", s.cacheFile)
+				}
+				return nil
+			},
+		}
+	}
+	return s, nil
 }
 
 // Type implements common.HasType.
@@ -160,12 +204,35 @@ func (*DNS) Type() interface{} {
 
 // Start implements common.Runnable.
 func (s *DNS) Start() error {
-	return nil
+	if s.cacheSave == nil {
+		return nil
+	}
+	if err := s.loadCache(); err != nil {
+		errors.LogWarningInner(s.ctx, err, "failed to load DNS cache from ", s.cacheFile)
+	}
+	return s.cacheSave.Start()
 }
 
 // Close implements common.Closable.
 func (s *DNS) Close() error {
-	return nil
+	if s.cacheSave == nil {
+		return nil
+	}
+	s.cacheSave.Close()
+	return s.saveCache()
+}
+
+// QueryLogChannel implements QueryLogPublisher.
+func (s *DNS) QueryLogChannel() feature_stats.Channel {
+	return s.queryLog.channel
+}
+
+// TopDomains implements DomainCounter.
+func (s *DNS) TopDomains(n int, reset bool) []DomainCount {
+	if s.queryLog == nil || s.queryLog.domains == nil {
+		return nil
+	}
+	return s.queryLog.domains.top(n, reset)
 }
 
 // IsOwnLink implements proxy.dns.ownLinkVerifier
@@ -189,6 +256,7 @@ func (s *DNS) LookupIP(domain string, option dns.IPOption) ([]net.IP, uint32, er
 	if domain == "" {
 		return nil, 0, errors.New("empty domain name")
 	}
+	s.queryLog.countDomain(domain)
 
 	if s.checkSystem {
 		supportIPv4, supportIPv6 := checkSystemNetwork()
@@ -268,6 +336,60 @@ func (s *DNS) LookupIP(domain string, option dns.IPOption) ([]net.IP, uint32, er
 	return nil, 0, dns.ErrEmptyResponse
 }
 
+// LookupRecords implements dns.RecordClient.
+func (s *DNS) LookupRecords(domain string, qtype uint16) ([]mdns.RR, uint32, error) {
+	// Normalize the FQDN form query
+	domain = strings.TrimSuffix(domain, ".")
+	if domain == "" {
+		return nil, 0, errors.New("empty domain name")
+	}
+	s.queryLog.countDomain(domain)
+
+	// Static hosts only replace domains for records
+	if addrs, _ := s.hosts.Lookup(domain, *s.ipOption); len(addrs) == 1 && addrs[0].Family().IsDomain() {
+		errors.LogInfo(s.ctx, "domain replaced: ", domain, " -> ", addrs[0].Domain())
+		domain = addrs[0].Domain()
+	}
+
+	// Name servers lookup
+	var errs []error
+	for _, client := range s.sortClients(domain) {
+		if _, ok := client.server.(RecordServer); !ok {
+			errors.LogDebug(s.ctx, "skip ", mdns.Type(qtype), " lookup for domain ", domain, " at server ", client.Name())
+			continue
+		}
+
+		rrs, ttl, err := client.QueryRecords(s.ctx, domain, qtype)
+
+		if len(rrs) > 0 {
+			if ttl == 0 {
+				ttl = 1
+			}
+			return rrs, ttl, nil
+		}
+
+		errors.LogInfoInner(s.ctx, err, "failed to lookup ", mdns.Type(qtype), " for domain ", domain, " at server ", client.Name())
+		errs = append(errs, err)
+
+		if client.IsFinalQuery() {
+			break
+		}
+	}
+
+	if len(errs) > 0 {
+		allErrs := errors.Combine(errs...)
+		err0 := errs[0]
+		if errors.AllEqual(err0, allErrs) {
+			if go_errors.Is(err0, dns.ErrEmptyResponse) {
+				return nil, 0, dns.ErrEmptyResponse
+			}
+			return nil, 0, errors.New("returning nil for domain ", domain).Base(err0)
+		}
+		return nil, 0, errors.New("returning nil for domain ", domain).Base(allErrs)
+	}
+	return nil, 0, dns.ErrEmptyResponse
+}
+
 func (s *DNS) sortClients(domain string) []*Client {
 	clients := make([]*Client, 0, len(s.clients))
 	clientUsed := make([]bool, len(s.clients))
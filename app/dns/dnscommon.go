@@ -13,6 +13,7 @@ import (
 	"github.com/GFW-knocker/Xray-core/common/session"
 	"github.com/GFW-knocker/Xray-core/core"
 	dns_feature "github.com/GFW-knocker/Xray-core/features/dns"
+	mdns "github.com/miekg/dns"
 	"golang.org/x/net/dns/dnsmessage"
 )
 
@@ -27,6 +28,9 @@ func Fqdn(domain string) string {
 type record struct {
 	A    *IPRecord
 	AAAA *IPRecord
+
+	prefetchA    prefetchState
+	prefetchAAAA prefetchState
 }
 
 // IPRecord is a cacheable item for a resolved domain
@@ -38,16 +42,16 @@ type IPRecord struct {
 	RawHeader *dnsmessage.Header
 }
 
-func (r *IPRecord) getIPs() ([]net.IP, uint32, error) {
+// getIPs returns the IPs of r, also if it expired for less than stale.
+func (r *IPRecord) getIPs(stale time.Duration) ([]net.IP, uint32, error) {
 	if r == nil {
 		return nil, 0, errRecordNotFound
 	}
-	untilExpire := time.Until(r.Expire)
-	if untilExpire <= 0 {
+	ttl, ok := remainingTTL(r.Expire, stale)
+	if !ok {
 		return nil, 0, errRecordNotFound
 	}
 
-	ttl := uint32(untilExpire/time.Second) + uint32(1)
 	if r.RCode != dnsmessage.RCodeSuccess {
 		return nil, ttl, dns_feature.RCodeError(r.RCode)
 	}
@@ -58,8 +62,53 @@ func (r *IPRecord) getIPs() ([]net.IP, uint32, error) {
 	return r.IP, ttl, nil
 }
 
+// RecordSet is a cacheable answer of records of other types than A and AAAA.
+type RecordSet struct {
+	RR     []mdns.RR
+	Expire time.Time
+	RCode  int
+
+	prefetch prefetchState
+}
+
+// getRecords returns the records of r, also if it expired for less than stale.
+func (r *RecordSet) getRecords(stale time.Duration) ([]mdns.RR, uint32, error) {
+	if r == nil {
+		return nil, 0, errRecordNotFound
+	}
+	ttl, ok := remainingTTL(r.Expire, stale)
+	if !ok {
+		return nil, 0, errRecordNotFound
+	}
+
+	if r.RCode != mdns.RcodeSuccess {
+		return nil, ttl, dns_feature.RCodeError(r.RCode)
+	}
+	if len(r.RR) == 0 {
+		return nil, ttl, dns_feature.ErrEmptyResponse
+	}
+
+	return r.RR, ttl, nil
+}
+
 var errRecordNotFound = errors.New("record not found")
 
+// staleTTL is the TTL of expired answers, as recommended by RFC 8767.
+const staleTTL = 30
+
+// remainingTTL returns the TTL of an answer expiring at expire, and whether it
+// can be served. Answers expired for less than stale are served with staleTTL.
+func remainingTTL(expire time.Time, stale time.Duration) (uint32, bool) {
+	untilExpire := time.Until(expire)
+	if untilExpire > 0 {
+		return uint32(untilExpire/time.Second) + uint32(1), true
+	}
+	if -untilExpire < stale {
+		return staleTTL, true
+	}
+	return 0, false
+}
+
 type dnsRequest struct {
 	reqType dnsmessage.Type
 	domain  string
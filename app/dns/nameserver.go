@@ -14,6 +14,7 @@ import (
 	"github.com/GFW-knocker/Xray-core/core"
 	"github.com/GFW-knocker/Xray-core/features/dns"
 	"github.com/GFW-knocker/Xray-core/features/routing"
+	mdns "github.com/miekg/dns"
 )
 
 // Server is the interface for Name Server.
@@ -38,6 +39,7 @@ type Client struct {
 	finalQuery    bool
 	ipOption      *dns.IPOption
 	checkSystem   bool
+	queryLog      *queryRecorder
 }
 
 // NewServer creates a name server object according to the network destination url.
@@ -64,6 +66,10 @@ func NewServer(ctx context.Context, dest net.Destination, dispatcher routing.Dis
 			return NewTCPNameServer(u, dispatcher, disableCache, clientIP)
 		case strings.EqualFold(u.Scheme, "tcp+local"): // DNS-over-TCP Local mode
 			return NewTCPLocalNameServer(u, disableCache, clientIP)
+		case strings.EqualFold(u.Scheme, "tls"): // DNS-over-TLS Remote mode
+			return NewTLSNameServer(u, dispatcher, disableCache, clientIP)
+		case strings.EqualFold(u.Scheme, "tls+local"): // DNS-over-TLS Local mode
+			return NewTLSLocalNameServer(u, disableCache, clientIP)
 		case strings.EqualFold(u.String(), "fakedns"):
 			var fd dns.FakeDNSEngine
 			err = core.RequireFeatures(ctx, func(fdns dns.FakeDNSEngine) {
@@ -232,8 +238,17 @@ func (c *Client) QueryIP(ctx context.Context, domain string, option dns.IPOption
 
 	ctx, cancel := context.WithTimeout(ctx, c.timeoutMs)
 	ctx = session.ContextWithInbound(ctx, &session.Inbound{Tag: c.tag})
+	ctx, entry := c.queryLog.start(ctx, c.Name(), domain, ipQueryType(option))
+	start := time.Now()
 	ips, ttl, err := c.server.QueryIP(ctx, domain, option)
 	cancel()
+	if entry != nil {
+		answer := make([]string, 0, len(ips))
+		for _, ip := range ips {
+			answer = append(answer, ip.String())
+		}
+		c.queryLog.finish(entry, start, answer, err)
+	}
 
 	if err != nil {
 		return nil, 0, err
@@ -278,6 +293,36 @@ func (c *Client) QueryIP(ctx context.Context, domain string, option dns.IPOption
 	return ips, ttl, nil
 }
 
+// QueryRecords sends a query of qtype to the name server, if it queries
+// records of other types than A and AAAA.
+func (c *Client) QueryRecords(ctx context.Context, domain string, qtype uint16) ([]mdns.RR, uint32, error) {
+	server, ok := c.server.(RecordServer)
+	if !ok {
+		return nil, 0, errors.New(c.Name(), " does not query ", mdns.Type(qtype), " records")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeoutMs)
+	defer cancel()
+	ctx = session.ContextWithInbound(ctx, &session.Inbound{Tag: c.tag})
+	ctx, entry := c.queryLog.start(ctx, c.Name(), domain, mdns.Type(qtype).String())
+	start := time.Now()
+	rrs, ttl, err := server.QueryRecords(ctx, domain, qtype)
+	if entry != nil {
+		answer := make([]string, 0, len(rrs))
+		for _, rr := range rrs {
+			answer = append(answer, rr.String())
+		}
+		c.queryLog.finish(entry, start, answer, err)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(rrs) == 0 {
+		return nil, 0, dns.ErrEmptyResponse
+	}
+	return rrs, ttl, nil
+}
+
 func ResolveIpOptionOverride(queryStrategy QueryStrategy, ipOption dns.IPOption) dns.IPOption {
 	switch queryStrategy {
 	case QueryStrategy_USE_IP:
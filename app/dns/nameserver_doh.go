@@ -23,6 +23,7 @@ import (
 	dns_feature "github.com/GFW-knocker/Xray-core/features/dns"
 	"github.com/GFW-knocker/Xray-core/features/routing"
 	"github.com/GFW-knocker/Xray-core/transport/internet"
+	mdns "github.com/miekg/dns"
 	utls "github.com/refraction-networking/utls"
 	"golang.org/x/net/http2"
 )
@@ -117,6 +118,10 @@ func (s *DoHNameServer) Name() string {
 	return s.cacheController.name
 }
 
+func (s *DoHNameServer) getCacheController() *CacheController {
+	return s.cacheController
+}
+
 func (s *DoHNameServer) newReqID() uint16 {
 	return 0
 }
@@ -215,6 +220,26 @@ func (s *DoHNameServer) dohHTTPSContext(ctx context.Context, b []byte) ([]byte,
 	return io.ReadAll(resp.Body)
 }
 
+// QueryRecords implements RecordServer.
+func (s *DoHNameServer) QueryRecords(ctx context.Context, domain string, qtype uint16) ([]mdns.RR, uint32, error) {
+	if s.Name()+"." == "DOH//"+Fqdn(domain) {
+		return nil, 0, errors.New("tries to resolve itself!", s.Name())
+	}
+	q := &recordQuery{
+		cacheController: s.cacheController,
+		clientIP:        s.clientIP,
+		padding:         int(crypto.RandBetween(100, 300)),
+		newReqID:        s.newReqID,
+		exchange: func(ctx context.Context, query []byte) ([]byte, error) {
+			return s.dohHTTPSContext(session.ContextWithContent(ctx, &session.Content{
+				Protocol:       "https",
+				SkipDNSResolve: true,
+			}), query)
+		},
+	}
+	return q.query(ctx, domain, qtype)
+}
+
 // QueryIP implements Server.
 func (s *DoHNameServer) QueryIP(ctx context.Context, domain string, option dns_feature.IPOption) ([]net.IP, uint32, error) { // nolint: dupl
 	fqdn := Fqdn(domain)
@@ -224,7 +249,7 @@ func (s *DoHNameServer) QueryIP(ctx context.Context, domain string, option dns_f
 	if s.cacheController.disableCache {
 		errors.LogDebug(ctx, "DNS cache is disabled. Querying IP for ", domain, " at ", s.Name())
 	} else {
-		ips, ttl, err := s.cacheController.findIPsForDomain(fqdn, option)
+		ips, ttl, err := s.cacheController.findCachedIPs(ctx, fqdn, option)
 		if !go_errors.Is(err, errRecordNotFound) {
 			errors.LogDebugInner(ctx, err, s.Name(), " cache HIT ", domain, " -> ", ips)
 			log.Record(&log.DNSLog{Server: s.Name(), Domain: domain, Result: ips, Status: log.DNSCacheHit, Elapsed: 0, Error: err})
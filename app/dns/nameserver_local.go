@@ -9,6 +9,7 @@ import (
 	"github.com/GFW-knocker/Xray-core/common/net"
 	"github.com/GFW-knocker/Xray-core/features/dns"
 	"github.com/GFW-knocker/Xray-core/features/dns/localdns"
+	mdns "github.com/miekg/dns"
 )
 
 // LocalNameServer is an wrapper over local DNS feature.
@@ -30,6 +31,11 @@ func (s *LocalNameServer) QueryIP(ctx context.Context, domain string, option dns
 	return
 }
 
+// QueryRecords implements RecordServer.
+func (s *LocalNameServer) QueryRecords(ctx context.Context, domain string, qtype uint16) ([]mdns.RR, uint32, error) {
+	return s.client.LookupRecords(domain, qtype)
+}
+
 // Name implements Server.
 func (s *LocalNameServer) Name() string {
 	return "localhost"
@@ -17,6 +17,7 @@ import (
 	"github.com/GFW-knocker/Xray-core/common/session"
 	dns_feature "github.com/GFW-knocker/Xray-core/features/dns"
 	"github.com/GFW-knocker/Xray-core/transport/internet/tls"
+	mdns "github.com/miekg/dns"
 	"github.com/quic-go/quic-go"
 	"golang.org/x/net/http2"
 )
@@ -64,6 +65,10 @@ func (s *QUICNameServer) Name() string {
 	return s.cacheController.name
 }
 
+func (s *QUICNameServer) getCacheController() *CacheController {
+	return s.cacheController
+}
+
 func (s *QUICNameServer) newReqID() uint16 {
 	return 0
 }
@@ -106,62 +111,15 @@ func (s *QUICNameServer) sendQuery(ctx context.Context, noResponseErrCh chan<- e
 				noResponseErrCh <- err
 				return
 			}
-
-			dnsReqBuf := buf.New()
-			err = binary.Write(dnsReqBuf, binary.BigEndian, uint16(b.Len()))
-			if err != nil {
-				errors.LogErrorInner(ctx, err, "binary write failed")
-				noResponseErrCh <- err
-				return
-			}
-			_, err = dnsReqBuf.Write(b.Bytes())
-			if err != nil {
-				errors.LogErrorInner(ctx, err, "buffer write failed")
-				noResponseErrCh <- err
-				return
-			}
+			resp, err := s.exchange(dnsCtx, b.Bytes())
 			b.Release()
-
-			conn, err := s.openStream(dnsCtx)
-			if err != nil {
-				errors.LogErrorInner(ctx, err, "failed to open quic connection")
-				noResponseErrCh <- err
-				return
-			}
-
-			_, err = conn.Write(dnsReqBuf.Bytes())
-			if err != nil {
-				errors.LogErrorInner(ctx, err, "failed to send query")
-				noResponseErrCh <- err
-				return
-			}
-
-			_ = conn.Close()
-
-			respBuf := buf.New()
-			defer respBuf.Release()
-			n, err := respBuf.ReadFullFrom(conn, 2)
-			if err != nil && n == 0 {
-				errors.LogErrorInner(ctx, err, "failed to read response length")
-				noResponseErrCh <- err
-				return
-			}
-			var length int16
-			err = binary.Read(bytes.NewReader(respBuf.Bytes()), binary.BigEndian, &length)
 			if err != nil {
-				errors.LogErrorInner(ctx, err, "failed to parse response length")
-				noResponseErrCh <- err
-				return
-			}
-			respBuf.Clear()
-			n, err = respBuf.ReadFullFrom(conn, int32(length))
-			if err != nil && n == 0 {
-				errors.LogErrorInner(ctx, err, "failed to read response length")
+				errors.LogErrorInner(ctx, err, "failed to query nameserver")
 				noResponseErrCh <- err
 				return
 			}
 
-			rec, err := parseResponse(respBuf.Bytes())
+			rec, err := parseResponse(resp)
 			if err != nil {
 				errors.LogErrorInner(ctx, err, "failed to handle response")
 				noResponseErrCh <- err
@@ -172,6 +130,62 @@ func (s *QUICNameServer) sendQuery(ctx context.Context, noResponseErrCh chan<- e
 	}
 }
 
+// exchange sends query to the server in a new stream, and returns the
+// response.
+func (s *QUICNameServer) exchange(ctx context.Context, query []byte) ([]byte, error) {
+	dnsReqBuf := buf.New()
+	defer dnsReqBuf.Release()
+	err := binary.Write(dnsReqBuf, binary.BigEndian, uint16(len(query)))
+	if err != nil {
+		return nil, errors.New("binary write failed").Base(err)
+	}
+	_, err = dnsReqBuf.Write(query)
+	if err != nil {
+		return nil, errors.New("buffer write failed").Base(err)
+	}
+
+	conn, err := s.openStream(ctx)
+	if err != nil {
+		return nil, errors.New("failed to open quic connection").Base(err)
+	}
+
+	_, err = conn.Write(dnsReqBuf.Bytes())
+	if err != nil {
+		return nil, errors.New("failed to send query").Base(err)
+	}
+
+	_ = conn.Close()
+
+	respBuf := buf.New()
+	defer respBuf.Release()
+	n, err := respBuf.ReadFullFrom(conn, 2)
+	if err != nil && n == 0 {
+		return nil, errors.New("failed to read response length").Base(err)
+	}
+	var length uint16
+	err = binary.Read(bytes.NewReader(respBuf.Bytes()), binary.BigEndian, &length)
+	if err != nil {
+		return nil, errors.New("failed to parse response length").Base(err)
+	}
+	respBuf.Clear()
+	n, err = respBuf.ReadFullFrom(conn, int32(length))
+	if err != nil && n == 0 {
+		return nil, errors.New("failed to read response").Base(err)
+	}
+	return bytes.Clone(respBuf.Bytes()), nil
+}
+
+// QueryRecords implements RecordServer.
+func (s *QUICNameServer) QueryRecords(ctx context.Context, domain string, qtype uint16) ([]mdns.RR, uint32, error) {
+	q := &recordQuery{
+		cacheController: s.cacheController,
+		clientIP:        s.clientIP,
+		newReqID:        s.newReqID,
+		exchange:        s.exchange,
+	}
+	return q.query(ctx, domain, qtype)
+}
+
 // QueryIP is called from dns.Server->queryIPTimeout
 func (s *QUICNameServer) QueryIP(ctx context.Context, domain string, option dns_feature.IPOption) ([]net.IP, uint32, error) {
 	fqdn := Fqdn(domain)
@@ -181,7 +195,7 @@ func (s *QUICNameServer) QueryIP(ctx context.Context, domain string, option dns_
 	if s.cacheController.disableCache {
 		errors.LogDebug(ctx, "DNS cache is disabled. Querying IP for ", domain, " at ", s.Name())
 	} else {
-		ips, ttl, err := s.cacheController.findIPsForDomain(fqdn, option)
+		ips, ttl, err := s.cacheController.findCachedIPs(ctx, fqdn, option)
 		if !go_errors.Is(err, errRecordNotFound) {
 			errors.LogDebugInner(ctx, err, s.Name(), " cache HIT ", domain, " -> ", ips)
 			log.Record(&log.DNSLog{Server: s.Name(), Domain: domain, Result: ips, Status: log.DNSCacheHit, Elapsed: 0, Error: err})
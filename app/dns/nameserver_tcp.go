@@ -19,6 +19,8 @@ import (
 	dns_feature "github.com/GFW-knocker/Xray-core/features/dns"
 	"github.com/GFW-knocker/Xray-core/features/routing"
 	"github.com/GFW-knocker/Xray-core/transport/internet"
+	"github.com/GFW-knocker/Xray-core/transport/internet/tls"
+	mdns "github.com/miekg/dns"
 )
 
 // TCPNameServer implemented DNS over TCP (RFC7766).
@@ -28,6 +30,8 @@ type TCPNameServer struct {
 	reqID           uint32
 	dial            func(context.Context) (net.Conn, error)
 	clientIP        net.IP
+	// tlsConfig is not nil for DNS over TLS.
+	tlsConfig *tls.Config
 }
 
 // NewTCPNameServer creates DNS over TCP server object for remote resolving.
@@ -37,12 +41,30 @@ func NewTCPNameServer(
 	disableCache bool,
 	clientIP net.IP,
 ) (*TCPNameServer, error) {
-	s, err := baseTCPNameServer(url, "TCP", disableCache, clientIP)
+	s, err := baseTCPNameServer(url, "TCP", 53, disableCache, clientIP)
 	if err != nil {
 		return nil, err
 	}
 
-	s.dial = func(ctx context.Context) (net.Conn, error) {
+	s.dial = s.dispatchDialer(dispatcher)
+
+	return s, nil
+}
+
+// NewTCPLocalNameServer creates DNS over TCP client object for local resolving
+func NewTCPLocalNameServer(url *url.URL, disableCache bool, clientIP net.IP) (*TCPNameServer, error) {
+	s, err := baseTCPNameServer(url, "TCPL", 53, disableCache, clientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	s.dial = s.systemDialer()
+
+	return s, nil
+}
+
+func (s *TCPNameServer) dispatchDialer(dispatcher routing.Dispatcher) func(context.Context) (net.Conn, error) {
+	return func(ctx context.Context) (net.Conn, error) {
 		link, err := dispatcher.Dispatch(toDnsContext(ctx, s.destination.String()), *s.destination)
 		if err != nil {
 			return nil, err
@@ -53,26 +75,16 @@ func NewTCPNameServer(
 			cnc.ConnectionOutputMulti(link.Reader),
 		), nil
 	}
-
-	return s, nil
 }
 
-// NewTCPLocalNameServer creates DNS over TCP client object for local resolving
-func NewTCPLocalNameServer(url *url.URL, disableCache bool, clientIP net.IP) (*TCPNameServer, error) {
-	s, err := baseTCPNameServer(url, "TCPL", disableCache, clientIP)
-	if err != nil {
-		return nil, err
-	}
-
-	s.dial = func(ctx context.Context) (net.Conn, error) {
+func (s *TCPNameServer) systemDialer() func(context.Context) (net.Conn, error) {
+	return func(ctx context.Context) (net.Conn, error) {
 		return internet.DialSystem(ctx, *s.destination, nil)
 	}
-
-	return s, nil
 }
 
-func baseTCPNameServer(url *url.URL, prefix string, disableCache bool, clientIP net.IP) (*TCPNameServer, error) {
-	port := net.Port(53)
+func baseTCPNameServer(url *url.URL, prefix string, defaultPort net.Port, disableCache bool, clientIP net.IP) (*TCPNameServer, error) {
+	port := defaultPort
 	if url.Port() != "" {
 		var err error
 		if port, err = net.PortFromString(url.Port()); err != nil {
@@ -95,6 +107,10 @@ func (s *TCPNameServer) Name() string {
 	return s.cacheController.name
 }
 
+func (s *TCPNameServer) getCacheController() *CacheController {
+	return s.cacheController
+}
+
 func (s *TCPNameServer) newReqID() uint16 {
 	return uint16(atomic.AddUint32(&s.reqID, 1))
 }
@@ -134,63 +150,17 @@ func (s *TCPNameServer) sendQuery(ctx context.Context, noResponseErrCh chan<- er
 				noResponseErrCh <- err
 				return
 			}
-
-			conn, err := s.dial(dnsCtx)
-			if err != nil {
-				errors.LogErrorInner(ctx, err, "failed to dial namesever")
-				noResponseErrCh <- err
-				return
-			}
-			defer conn.Close()
-			dnsReqBuf := buf.New()
-			err = binary.Write(dnsReqBuf, binary.BigEndian, uint16(b.Len()))
-			if err != nil {
-				errors.LogErrorInner(ctx, err, "binary write failed")
-				noResponseErrCh <- err
-				return
-			}
-			_, err = dnsReqBuf.Write(b.Bytes())
-			if err != nil {
-				errors.LogErrorInner(ctx, err, "buffer write failed")
-				noResponseErrCh <- err
-				return
-			}
+			resp, err := s.exchange(dnsCtx, b.Bytes())
 			b.Release()
-
-			_, err = conn.Write(dnsReqBuf.Bytes())
-			if err != nil {
-				errors.LogErrorInner(ctx, err, "failed to send query")
-				noResponseErrCh <- err
-				return
-			}
-			dnsReqBuf.Release()
-
-			respBuf := buf.New()
-			defer respBuf.Release()
-			n, err := respBuf.ReadFullFrom(conn, 2)
-			if err != nil && n == 0 {
-				errors.LogErrorInner(ctx, err, "failed to read response length")
-				noResponseErrCh <- err
-				return
-			}
-			var length int16
-			err = binary.Read(bytes.NewReader(respBuf.Bytes()), binary.BigEndian, &length)
 			if err != nil {
-				errors.LogErrorInner(ctx, err, "failed to parse response length")
-				noResponseErrCh <- err
-				return
-			}
-			respBuf.Clear()
-			n, err = respBuf.ReadFullFrom(conn, int32(length))
-			if err != nil && n == 0 {
-				errors.LogErrorInner(ctx, err, "failed to read response length")
+				errors.LogErrorInner(ctx, err, "failed to query nameserver")
 				noResponseErrCh <- err
 				return
 			}
 
-			rec, err := parseResponse(respBuf.Bytes())
+			rec, err := parseResponse(resp)
 			if err != nil {
-				errors.LogErrorInner(ctx, err, "failed to parse DNS over TCP response")
+				errors.LogErrorInner(ctx, err, "failed to parse DNS over ", s.protocol(), " response")
 				noResponseErrCh <- err
 				return
 			}
@@ -200,6 +170,66 @@ func (s *TCPNameServer) sendQuery(ctx context.Context, noResponseErrCh chan<- er
 	}
 }
 
+// exchange sends query to the server in a new connection, and returns the
+// response.
+func (s *TCPNameServer) exchange(ctx context.Context, query []byte) ([]byte, error) {
+	conn, err := s.dial(ctx)
+	if err != nil {
+		return nil, errors.New("failed to dial nameserver").Base(err)
+	}
+	defer conn.Close()
+
+	dnsReqBuf := buf.New()
+	defer dnsReqBuf.Release()
+	err = binary.Write(dnsReqBuf, binary.BigEndian, uint16(len(query)))
+	if err != nil {
+		return nil, errors.New("binary write failed").Base(err)
+	}
+	_, err = dnsReqBuf.Write(query)
+	if err != nil {
+		return nil, errors.New("buffer write failed").Base(err)
+	}
+
+	_, err = conn.Write(dnsReqBuf.Bytes())
+	if err != nil {
+		return nil, errors.New("failed to send query").Base(err)
+	}
+
+	respBuf := buf.New()
+	defer respBuf.Release()
+	n, err := respBuf.ReadFullFrom(conn, 2)
+	if err != nil && n == 0 {
+		return nil, errors.New("failed to read response length").Base(err)
+	}
+	var length uint16
+	err = binary.Read(bytes.NewReader(respBuf.Bytes()), binary.BigEndian, &length)
+	if err != nil {
+		return nil, errors.New("failed to parse response length").Base(err)
+	}
+	respBuf.Clear()
+	n, err = respBuf.ReadFullFrom(conn, int32(length))
+	if err != nil && n == 0 {
+		return nil, errors.New("failed to read response").Base(err)
+	}
+	return bytes.Clone(respBuf.Bytes()), nil
+}
+
+// QueryRecords implements RecordServer.
+func (s *TCPNameServer) QueryRecords(ctx context.Context, domain string, qtype uint16) ([]mdns.RR, uint32, error) {
+	q := &recordQuery{
+		cacheController: s.cacheController,
+		clientIP:        s.clientIP,
+		newReqID:        s.newReqID,
+		exchange: func(ctx context.Context, query []byte) ([]byte, error) {
+			return s.exchange(session.ContextWithContent(ctx, &session.Content{
+				Protocol:       "dns",
+				SkipDNSResolve: true,
+			}), query)
+		},
+	}
+	return q.query(ctx, domain, qtype)
+}
+
 // QueryIP implements Server.
 func (s *TCPNameServer) QueryIP(ctx context.Context, domain string, option dns_feature.IPOption) ([]net.IP, uint32, error) {
 	fqdn := Fqdn(domain)
@@ -209,7 +239,7 @@ func (s *TCPNameServer) QueryIP(ctx context.Context, domain string, option dns_f
 	if s.cacheController.disableCache {
 		errors.LogDebug(ctx, "DNS cache is disabled. Querying IP for ", domain, " at ", s.Name())
 	} else {
-		ips, ttl, err := s.cacheController.findIPsForDomain(fqdn, option)
+		ips, ttl, err := s.cacheController.findCachedIPs(ctx, fqdn, option)
 		if !go_errors.Is(err, errRecordNotFound) {
 			errors.LogDebugInner(ctx, err, s.Name(), " cache HIT ", domain, " -> ", ips)
 			log.Record(&log.DNSLog{Server: s.Name(), Domain: domain, Result: ips, Status: log.DNSCacheHit, Elapsed: 0, Error: err})
@@ -0,0 +1,69 @@
+package dns
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/GFW-knocker/Xray-core/common/errors"
+	"github.com/GFW-knocker/Xray-core/common/net"
+	"github.com/GFW-knocker/Xray-core/features/routing"
+	"github.com/GFW-knocker/Xray-core/transport/internet/tls"
+)
+
+// NextProtoDoT is the ALPN of DNS over TLS.
+const NextProtoDoT = "dot"
+
+// NewTLSNameServer creates DNS over TLS server object for remote resolving (RFC7858).
+func NewTLSNameServer(
+	url *url.URL,
+	dispatcher routing.Dispatcher,
+	disableCache bool,
+	clientIP net.IP,
+) (*TCPNameServer, error) {
+	s, err := baseTCPNameServer(url, "TLS", 853, disableCache, clientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	s.tlsConfig = &tls.Config{}
+	s.dial = s.tlsDialer(s.dispatchDialer(dispatcher))
+
+	return s, nil
+}
+
+// NewTLSLocalNameServer creates DNS over TLS client object for local resolving
+func NewTLSLocalNameServer(url *url.URL, disableCache bool, clientIP net.IP) (*TCPNameServer, error) {
+	s, err := baseTCPNameServer(url, "TLSL", 853, disableCache, clientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	s.tlsConfig = &tls.Config{}
+	s.dial = s.tlsDialer(s.systemDialer())
+
+	return s, nil
+}
+
+// tlsDialer wraps the connections of dial in TLS.
+func (s *TCPNameServer) tlsDialer(dial func(context.Context) (net.Conn, error)) func(context.Context) (net.Conn, error) {
+	return func(ctx context.Context) (net.Conn, error) {
+		conn, err := dial(ctx)
+		if err != nil {
+			return nil, err
+		}
+		config := s.tlsConfig.GetTLSConfig(tls.WithDestination(*s.destination), tls.WithNextProto(NextProtoDoT))
+		tlsConn := tls.Client(conn, config).(*tls.Conn)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, errors.New("TLS handshake with ", s.destination, " failed").Base(err)
+		}
+		return tlsConn, nil
+	}
+}
+
+func (s *TCPNameServer) protocol() string {
+	if s.tlsConfig != nil {
+		return "TLS"
+	}
+	return "TCP"
+}
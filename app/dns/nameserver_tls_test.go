@@ -0,0 +1,136 @@
+package dns
+
+import (
+	"context"
+	gotls "crypto/tls"
+	"crypto/x509"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/GFW-knocker/Xray-core/app/dispatcher"
+	"github.com/GFW-knocker/Xray-core/app/policy"
+	"github.com/GFW-knocker/Xray-core/app/proxyman"
+	_ "github.com/GFW-knocker/Xray-core/app/proxyman/outbound"
+	"github.com/GFW-knocker/Xray-core/common"
+	"github.com/GFW-knocker/Xray-core/common/net"
+	"github.com/GFW-knocker/Xray-core/common/protocol/tls/cert"
+	"github.com/GFW-knocker/Xray-core/common/serial"
+	"github.com/GFW-knocker/Xray-core/core"
+	dns_feature "github.com/GFW-knocker/Xray-core/features/dns"
+	"github.com/GFW-knocker/Xray-core/features/routing"
+	"github.com/GFW-knocker/Xray-core/proxy/freedom"
+	_ "github.com/GFW-knocker/Xray-core/transport/internet/tcp"
+	"github.com/GFW-knocker/Xray-core/transport/internet/tls"
+	"github.com/google/go-cmp/cmp"
+	"github.com/miekg/dns"
+)
+
+const xrayKey core.XrayKey = 1
+
+// startDoTServer starts a DNS over TLS server answering A queries with
+// 1.2.3.4, and returns its address and the PEM of its CA.
+func startDoTServer(t *testing.T) (string, []byte) {
+	ca := cert.MustGenerate(nil, cert.Authority(true), cert.KeyUsage(x509.KeyUsageCertSign))
+	caPEM, _ := ca.ToPEM()
+	certPEM, keyPEM := cert.MustGenerate(ca, cert.DNSNames("dns.example")).ToPEM()
+	certificate, err := gotls.X509KeyPair(certPEM, keyPEM)
+	common.Must(err)
+
+	listener, err := gotls.Listen("tcp", "127.0.0.1:0", &gotls.Config{
+		Certificates: []gotls.Certificate{certificate},
+		NextProtos:   []string{NextProtoDoT},
+	})
+	common.Must(err)
+	server := &dns.Server{
+		Listener: listener,
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+			ans := new(dns.Msg)
+			ans.SetReply(r)
+			for _, q := range r.Question {
+				if q.Qtype == dns.TypeA {
+					rr, _ := dns.NewRR(q.Name + " IN A 1.2.3.4")
+					ans.Answer = append(ans.Answer, rr)
+				}
+			}
+			w.WriteMsg(ans)
+		}),
+	}
+	go server.ActivateAndServe()
+	t.Cleanup(func() {
+		server.Shutdown()
+	})
+	return listener.Addr().String(), caPEM
+}
+
+func TestTLSNameServer(t *testing.T) {
+	addr, caPEM := startDoTServer(t)
+
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&policy.Config{}),
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	})
+	common.Must(err)
+	common.Must(v.Start())
+	defer v.Close()
+	d := v.GetFeature(routing.DispatcherType()).(routing.Dispatcher)
+
+	servers := map[string]func(*url.URL) (*TCPNameServer, error){
+		"tls": func(u *url.URL) (*TCPNameServer, error) {
+			return NewTLSNameServer(u, d, true, nil)
+		},
+		"tls+local": func(u *url.URL) (*TCPNameServer, error) {
+			return NewTLSLocalNameServer(u, true, nil)
+		},
+	}
+	for scheme, newServer := range servers {
+		t.Run(scheme, func(t *testing.T) {
+			u, err := url.Parse(scheme + "://" + addr)
+			common.Must(err)
+			s, err := newServer(u)
+			common.Must(err)
+			s.tlsConfig.ServerName = "dns.example"
+
+			query := func() ([]net.IP, error) {
+				ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), xrayKey, v), time.Second*5)
+				defer cancel()
+				ips, _, err := s.QueryIP(ctx, "example.com", dns_feature.IPOption{
+					IPv4Enable: true,
+				})
+				return ips, err
+			}
+
+			if _, err := query(); err == nil {
+				t.Error("expect the untrusted certificate to be rejected")
+			}
+
+			s.tlsConfig.Certificate = []*tls.Certificate{{
+				Certificate: caPEM,
+				Usage:       tls.Certificate_AUTHORITY_VERIFY,
+			}}
+			ips, err := query()
+			common.Must(err)
+			if r := cmp.Diff(ips, []net.IP{{1, 2, 3, 4}}); r != "" {
+				t.Error(r)
+			}
+		})
+	}
+}
+
+func TestTLSNameServerDefaultPort(t *testing.T) {
+	u, err := url.Parse("tls+local://1.1.1.1")
+	common.Must(err)
+	s, err := NewTLSLocalNameServer(u, false, nil)
+	common.Must(err)
+	if s.Name() != "TLSL//1.1.1.1:853" {
+		t.Error("unexpected name: ", s.Name())
+	}
+}
@@ -1,7 +1,9 @@
 package dns
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
 	go_errors "errors"
 	"strings"
 	"sync"
@@ -9,6 +11,7 @@ import (
 	"time"
 
 	"github.com/GFW-knocker/Xray-core/common"
+	"github.com/GFW-knocker/Xray-core/common/buf"
 	"github.com/GFW-knocker/Xray-core/common/errors"
 	"github.com/GFW-knocker/Xray-core/common/log"
 	"github.com/GFW-knocker/Xray-core/common/net"
@@ -18,6 +21,7 @@ import (
 	dns_feature "github.com/GFW-knocker/Xray-core/features/dns"
 	"github.com/GFW-knocker/Xray-core/features/routing"
 	"github.com/GFW-knocker/Xray-core/transport/internet/udp"
+	mdns "github.com/miekg/dns"
 	"golang.org/x/net/dns/dnsmessage"
 )
 
@@ -31,11 +35,16 @@ type ClassicNameServer struct {
 	requestsCleanup *task.Periodic
 	reqID           uint32
 	clientIP        net.IP
+	// tcp queries the same server over TCP, for the records whose answer
+	// is truncated over UDP.
+	tcp *TCPNameServer
 }
 
 type udpDnsRequest struct {
 	dnsRequest
 	ctx context.Context
+	// response receives the raw response if not nil, for queries of records.
+	response chan []byte
 }
 
 // NewClassicNameServer creates udp server object for remote resolving.
@@ -56,6 +65,9 @@ func NewClassicNameServer(address net.Destination, dispatcher routing.Dispatcher
 		Execute:  s.RequestsCleanup,
 	}
 	s.udpServer = udp.NewDispatcher(dispatcher, s.HandleResponse)
+	tcpAddress := net.TCPDestination(address.Address, address.Port)
+	s.tcp = &TCPNameServer{destination: &tcpAddress}
+	s.tcp.dial = s.tcp.dispatchDialer(dispatcher)
 	errors.LogInfo(context.Background(), "DNS: created UDP client initialized for ", address.NetAddr())
 	return s
 }
@@ -65,6 +77,10 @@ func (s *ClassicNameServer) Name() string {
 	return s.cacheController.name
 }
 
+func (s *ClassicNameServer) getCacheController() *CacheController {
+	return s.cacheController
+}
+
 // RequestsCleanup clears expired items from cache
 func (s *ClassicNameServer) RequestsCleanup() error {
 	now := time.Now()
@@ -108,6 +124,10 @@ func (s *ClassicNameServer) HandleResponse(ctx context.Context, packet *udp_prot
 		errors.LogError(ctx, s.Name(), " cannot find the pending request")
 		return
 	}
+	if req.response != nil {
+		req.response <- bytes.Clone(packet.Payload.Bytes())
+		return
+	}
 
 	// if truncated, retry with EDNS0 option(udp payload size: 1350)
 	if ipRec.RawHeader.Truncated {
@@ -162,6 +182,50 @@ func (s *ClassicNameServer) sendQuery(ctx context.Context, _ chan<- error, domai
 	}
 }
 
+// exchange sends query, and waits for its response. Truncated responses are
+// retried over TCP.
+func (s *ClassicNameServer) exchange(ctx context.Context, query []byte) ([]byte, error) {
+	req := &udpDnsRequest{
+		dnsRequest: dnsRequest{
+			msg: &dnsmessage.Message{
+				Header: dnsmessage.Header{ID: binary.BigEndian.Uint16(query)},
+			},
+		},
+		ctx:      ctx,
+		response: make(chan []byte, 1),
+	}
+	s.addPendingRequest(req)
+	b := buf.New()
+	b.Write(query)
+	s.udpServer.Dispatch(toDnsContext(ctx, s.address.String()), *s.address, b)
+
+	select {
+	case resp := <-req.response:
+		// the answer does not fit in UDP, see RFC 7766 section 5
+		if len(resp) > 2 && resp[2]&0x02 != 0 {
+			errors.LogDebug(ctx, s.Name(), " got a truncated response, retrying over TCP")
+			return s.tcp.exchange(ctx, query)
+		}
+		return resp, nil
+	case <-ctx.Done():
+		s.Lock()
+		delete(s.requests, req.msg.ID)
+		s.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// QueryRecords implements RecordServer.
+func (s *ClassicNameServer) QueryRecords(ctx context.Context, domain string, qtype uint16) ([]mdns.RR, uint32, error) {
+	q := &recordQuery{
+		cacheController: s.cacheController,
+		clientIP:        s.clientIP,
+		newReqID:        s.newReqID,
+		exchange:        s.exchange,
+	}
+	return q.query(ctx, domain, qtype)
+}
+
 // QueryIP implements Server.
 func (s *ClassicNameServer) QueryIP(ctx context.Context, domain string, option dns_feature.IPOption) ([]net.IP, uint32, error) {
 	fqdn := Fqdn(domain)
@@ -171,7 +235,7 @@ func (s *ClassicNameServer) QueryIP(ctx context.Context, domain string, option d
 	if s.cacheController.disableCache {
 		errors.LogDebug(ctx, "DNS cache is disabled. Querying IP for ", domain, " at ", s.Name())
 	} else {
-		ips, ttl, err := s.cacheController.findIPsForDomain(fqdn, option)
+		ips, ttl, err := s.cacheController.findCachedIPs(ctx, fqdn, option)
 		if !go_errors.Is(err, errRecordNotFound) {
 			errors.LogDebugInner(ctx, err, s.Name(), " cache HIT ", domain, " -> ", ips)
 			log.Record(&log.DNSLog{Server: s.Name(), Domain: domain, Result: ips, Status: log.DNSCacheHit, Elapsed: 0, Error: err})
@@ -0,0 +1,132 @@
+package dns
+
+import (
+	"context"
+	go_errors "errors"
+	"strings"
+	"time"
+
+	"github.com/GFW-knocker/Xray-core/app/stats"
+	"github.com/GFW-knocker/Xray-core/features/dns"
+	feature_stats "github.com/GFW-knocker/Xray-core/features/stats"
+	mdns "github.com/miekg/dns"
+)
+
+// DomainCounter is a DNS client which counts the lookups of domains.
+type DomainCounter interface {
+	dns.Client
+	// TopDomains returns the n most looked up domains, most first, and resets
+	// the counts if reset.
+	TopDomains(n int, reset bool) []DomainCount
+}
+
+// QueryLogPublisher is a DNS client which publishes the record of every query
+// to its name servers, as a *QueryLog, to its QueryLogChannel.
+type QueryLogPublisher interface {
+	dns.Client
+	QueryLogChannel() feature_stats.Channel
+}
+
+// queryLogKey is the context key of the QueryLog of a query.
+type queryLogKey struct{}
+
+// markCacheHit marks the query of ctx as answered from the cache.
+func markCacheHit(ctx context.Context) {
+	if entry, ok := ctx.Value(queryLogKey{}).(*QueryLog); ok {
+		entry.CacheHit = true
+	}
+}
+
+// queryRecorder publishes the records of queries, and counts them in the
+// stats manager if any. The lookups of domains are counted in domains, since
+// a counter for every domain looked up would grow without bound.
+type queryRecorder struct {
+	ctx          context.Context
+	channel      *stats.Channel
+	statsManager feature_stats.Manager
+	domains      *topDomains
+}
+
+func newQueryRecorder(ctx context.Context) *queryRecorder {
+	return &queryRecorder{
+		ctx: ctx,
+		channel: stats.NewChannel(&stats.ChannelConfig{
+			SubscriberLimit: 16,
+			BufferSize:      64,
+			Blocking:        false,
+		}),
+	}
+}
+
+// start returns ctx carrying a new QueryLog of the query of qtype for domain
+// to server.
+func (r *queryRecorder) start(ctx context.Context, server string, domain string, qtype string) (context.Context, *QueryLog) {
+	if r == nil {
+		return ctx, nil
+	}
+	entry := &QueryLog{
+		Domain: strings.TrimSuffix(domain, "."),
+		Qtype:  qtype,
+		Server: server,
+	}
+	return context.WithValue(ctx, queryLogKey{}, entry), entry
+}
+
+// finish completes entry with the result of its query, and records it.
+func (r *queryRecorder) finish(entry *QueryLog, start time.Time, answer []string, err error) {
+	if r == nil || entry == nil {
+		return
+	}
+	now := time.Now()
+	entry.Time = now.UnixMilli()
+	entry.LatencyMs = now.Sub(start).Milliseconds()
+	entry.Answer = answer
+	if err != nil && !go_errors.Is(err, dns.ErrEmptyResponse) {
+		entry.Rcode = int32(dns.RCodeFromError(err))
+		if entry.Rcode == 0 {
+			entry.Error = err.Error()
+		}
+	}
+
+	if r.statsManager != nil {
+		prefix := "dns>>>server>>>" + entry.Server + ">>>"
+		r.count(prefix + "query")
+		if entry.CacheHit {
+			r.count(prefix + "cache_hit")
+		}
+		// names which do not exist are answers, not failures of the server
+		if entry.Error != "" || entry.Rcode != 0 && entry.Rcode != mdns.RcodeNameError {
+			r.count(prefix + "failure")
+		}
+	}
+
+	if len(r.channel.Subscribers()) > 0 {
+		r.channel.Publish(r.ctx, entry)
+	}
+}
+
+// countDomain counts a lookup of domain.
+func (r *queryRecorder) countDomain(domain string) {
+	if r == nil || r.domains == nil {
+		return
+	}
+	r.domains.add(domain)
+}
+
+func (r *queryRecorder) count(name string) {
+	if c, err := feature_stats.GetOrRegisterCounter(r.statsManager, name); err == nil {
+		c.Add(1)
+	}
+}
+
+// ipQueryType returns the query type of a query of IPs with option.
+func ipQueryType(option dns.IPOption) string {
+	switch {
+	case option.IPv4Enable && option.IPv6Enable:
+		return "A,AAAA"
+	case option.IPv6Enable:
+		return "AAAA"
+	default:
+		return "A"
+	}
+}
@@ -0,0 +1,150 @@
+package dns
+
+import (
+	"context"
+	go_errors "errors"
+	"time"
+
+	"github.com/GFW-knocker/Xray-core/common/errors"
+	"github.com/GFW-knocker/Xray-core/common/net"
+	dns_feature "github.com/GFW-knocker/Xray-core/features/dns"
+	mdns "github.com/miekg/dns"
+)
+
+// RecordServer is a Server which also queries records of other types than A
+// and AAAA.
+type RecordServer interface {
+	Server
+	// QueryRecords sends a query of qtype to its configured server.
+	QueryRecords(ctx context.Context, domain string, qtype uint16) ([]mdns.RR, uint32, error)
+}
+
+// exchangeFunc sends a packed DNS query, and returns the packed response.
+type exchangeFunc func(ctx context.Context, query []byte) ([]byte, error)
+
+// recordQuery is a query of records for a name server.
+type recordQuery struct {
+	cacheController *CacheController
+	clientIP        net.IP
+	padding         int
+	newReqID        func() uint16
+	exchange        exchangeFunc
+}
+
+// query returns the records of qtype for domain from the cache, or queries
+// them with exchange and caches the answer.
+func (q *recordQuery) query(ctx context.Context, domain string, qtype uint16) ([]mdns.RR, uint32, error) {
+	fqdn := Fqdn(domain)
+	c := q.cacheController
+	if c.disableCache {
+		errors.LogDebug(ctx, "DNS cache is disabled. Querying ", mdns.Type(qtype), " for ", domain, " at ", c.name)
+	} else {
+		rrs, ttl, err := c.findRecordsForDomain(fqdn, qtype)
+		if !go_errors.Is(err, errRecordNotFound) {
+			errors.LogDebugInner(ctx, err, c.name, " cache HIT ", domain, " ", mdns.Type(qtype))
+			markCacheHit(ctx)
+			if c.recordsNeedRefresh(fqdn, qtype) {
+				go q.refresh(context.WithoutCancel(ctx), domain, qtype)
+			}
+			return rrs, ttl, err
+		}
+	}
+	return q.fetch(ctx, domain, qtype)
+}
+
+// refresh fetches the records of qtype for domain again, unless they are
+// already being refreshed.
+func (q *recordQuery) refresh(ctx context.Context, domain string, qtype uint16) {
+	c := q.cacheController
+	if !c.startRefresh(refreshKey(Fqdn(domain), qtype)) {
+		return
+	}
+
+	errors.LogDebug(ctx, c.name, " refreshing ", mdns.Type(qtype), " for ", domain)
+	ctx, cancel := context.WithTimeout(ctx, refreshTimeout)
+	defer cancel()
+	if _, _, err := q.fetch(ctx, domain, qtype); err != nil && !go_errors.Is(err, errRecordNotFound) {
+		errors.LogInfoInner(ctx, err, c.name, " failed to refresh ", domain)
+	}
+}
+
+// fetch queries the records of qtype for domain with exchange, and caches the
+// answer.
+func (q *recordQuery) fetch(ctx context.Context, domain string, qtype uint16) ([]mdns.RR, uint32, error) {
+	fqdn := Fqdn(domain)
+	c := q.cacheController
+	errors.LogDebug(ctx, c.name, " querying ", mdns.Type(qtype), " for: ", domain)
+	msg := new(mdns.Msg)
+	msg.SetQuestion(fqdn, qtype)
+	msg.Id = q.newReqID()
+	msg.SetEdns0(1350, false)
+	edns := msg.IsEdns0()
+	if len(q.clientIP) > 0 {
+		subnet := &mdns.EDNS0_SUBNET{
+			Code:    mdns.EDNS0SUBNET,
+			Address: q.clientIP,
+		}
+		if ip4 := q.clientIP.To4(); ip4 != nil {
+			subnet.Family = 1
+			subnet.SourceNetmask = 24
+			subnet.Address = ip4
+		} else {
+			subnet.Family = 2
+			subnet.SourceNetmask = 96
+		}
+		edns.Option = append(edns.Option, subnet)
+	}
+	if q.padding > 0 {
+		edns.Option = append(edns.Option, &mdns.EDNS0_PADDING{Padding: make([]byte, q.padding)})
+	}
+	b, err := msg.Pack()
+	if err != nil {
+		return nil, 0, errors.New("failed to pack dns query for ", domain).Base(err)
+	}
+
+	start := time.Now()
+	resp, err := q.exchange(ctx, b)
+	if err != nil {
+		return nil, 0, errors.New("failed to query ", mdns.Type(qtype), " for ", domain, " at ", c.name).Base(err)
+	}
+	set, err := parseRecords(resp, msg.Id, qtype)
+	if err != nil {
+		return nil, 0, err
+	}
+	errors.LogDebug(ctx, c.name, " got ", mdns.Type(qtype), " answer for ", domain, " in ", time.Since(start))
+	c.updateRecords(fqdn, qtype, set)
+	return set.getRecords(0)
+}
+
+// parseRecords parses the answers of qtype from the response of query id.
+func parseRecords(payload []byte, id uint16, qtype uint16) (*RecordSet, error) {
+	resp := new(mdns.Msg)
+	if err := resp.Unpack(payload); err != nil {
+		return nil, errors.New("failed to parse DNS response").Base(err).AtWarning()
+	}
+	if resp.Id != id {
+		return nil, errors.New("unexpected DNS response ID ", resp.Id)
+	}
+
+	now := time.Now()
+	set := &RecordSet{
+		RCode:  resp.Rcode,
+		Expire: now.Add(time.Second * dns_feature.DefaultTTL),
+	}
+	for _, rr := range resp.Answer {
+		if rr.Header().Rrtype != qtype {
+			// CNAMEs to the answers
+			continue
+		}
+		ttl := rr.Header().Ttl
+		if ttl == 0 {
+			ttl = 1
+		}
+		expire := now.Add(time.Duration(ttl) * time.Second)
+		if set.Expire.After(expire) {
+			set.Expire = expire
+		}
+		set.RR = append(set.RR, rr)
+	}
+	return set, nil
+}
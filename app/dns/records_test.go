@@ -0,0 +1,160 @@
+package dns
+
+import (
+	"context"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/GFW-knocker/Xray-core/app/dispatcher"
+	"github.com/GFW-knocker/Xray-core/app/policy"
+	"github.com/GFW-knocker/Xray-core/app/proxyman"
+	"github.com/GFW-knocker/Xray-core/common"
+	"github.com/GFW-knocker/Xray-core/common/net"
+	"github.com/GFW-knocker/Xray-core/common/serial"
+	"github.com/GFW-knocker/Xray-core/core"
+	dns_feature "github.com/GFW-knocker/Xray-core/features/dns"
+	"github.com/GFW-knocker/Xray-core/features/routing"
+	"github.com/GFW-knocker/Xray-core/proxy/freedom"
+	"github.com/miekg/dns"
+)
+
+func TestTCPNameServerQueryRecords(t *testing.T) {
+	var queries atomic.Int32
+	server := &dns.Server{
+		Addr: "127.0.0.1:0",
+		Net:  "tcp",
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+			queries.Add(1)
+			ans := new(dns.Msg)
+			ans.SetReply(r)
+			q := r.Question[0]
+			for _, record := range []string{
+				q.Name + " 300 IN CNAME target.example.",
+				q.Name + " 300 IN TXT \"hello\"",
+				q.Name + " 60 IN TXT \"world\"",
+			} {
+				rr, _ := dns.NewRR(record)
+				ans.Answer = append(ans.Answer, rr)
+			}
+			w.WriteMsg(ans)
+		}),
+	}
+	started := make(chan struct{})
+	server.NotifyStartedFunc = func() { close(started) }
+	go server.ListenAndServe()
+	<-started
+	defer server.Shutdown()
+
+	u, err := url.Parse("tcp+local://" + server.Listener.Addr().String())
+	common.Must(err)
+	s, err := NewTCPLocalNameServer(u, false, nil)
+	common.Must(err)
+
+	for i := 0; i < 2; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		rrs, ttl, err := s.QueryRecords(ctx, "example.com", dns.TypeTXT)
+		cancel()
+		common.Must(err)
+		if len(rrs) != 2 {
+			t.Fatal("unexpected records: ", rrs)
+		}
+		if txt := rrs[0].(*dns.TXT).Txt; len(txt) != 1 || txt[0] != "hello" {
+			t.Error("unexpected TXT: ", txt)
+		}
+		if ttl == 0 || ttl > 60 {
+			t.Error("unexpected TTL: ", ttl)
+		}
+	}
+	if n := queries.Load(); n != 1 {
+		t.Error("expect the second query to hit the cache, got ", n, " queries")
+	}
+}
+
+func TestParseRecordsID(t *testing.T) {
+	msg := new(dns.Msg)
+	msg.SetQuestion("example.com.", dns.TypeSRV)
+	msg.Id = 1
+	b, err := msg.Pack()
+	common.Must(err)
+	if _, err := parseRecords(b, 2, dns.TypeSRV); err == nil {
+		t.Error("expect an error for the mismatched ID")
+	}
+	set, err := parseRecords(b, 1, dns.TypeSRV)
+	common.Must(err)
+	if _, _, err := set.getRecords(0); err != dns_feature.ErrEmptyResponse {
+		t.Error("expect an empty response, got ", err)
+	}
+}
+
+func TestUDPNameServerQueryRecordsTruncated(t *testing.T) {
+	answer := func(r *dns.Msg) *dns.Msg {
+		ans := new(dns.Msg)
+		ans.SetReply(r)
+		rr, _ := dns.NewRR(r.Question[0].Name + " 300 IN TXT \"hello\"")
+		ans.Answer = append(ans.Answer, rr)
+		return ans
+	}
+	var tcpQueries atomic.Int32
+	tcpServer := &dns.Server{
+		Addr: "127.0.0.1:0",
+		Net:  "tcp",
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+			tcpQueries.Add(1)
+			w.WriteMsg(answer(r))
+		}),
+	}
+	started := make(chan struct{})
+	tcpServer.NotifyStartedFunc = func() { close(started) }
+	go tcpServer.ListenAndServe()
+	<-started
+	defer tcpServer.Shutdown()
+
+	udpServer := &dns.Server{
+		Addr: tcpServer.Listener.Addr().String(),
+		Net:  "udp",
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+			ans := new(dns.Msg)
+			ans.SetReply(r)
+			ans.Truncated = true
+			w.WriteMsg(ans)
+		}),
+	}
+	started = make(chan struct{})
+	udpServer.NotifyStartedFunc = func() { close(started) }
+	go udpServer.ListenAndServe()
+	<-started
+	defer udpServer.Shutdown()
+
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&policy.Config{}),
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	})
+	common.Must(err)
+	common.Must(v.Start())
+	defer v.Close()
+
+	dest, err := net.ParseDestination("udp:" + udpServer.PacketConn.LocalAddr().String())
+	common.Must(err)
+	s := NewClassicNameServer(dest, v.GetFeature(routing.DispatcherType()).(routing.Dispatcher), false, nil)
+
+	ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), xrayKey, v), time.Second*5)
+	defer cancel()
+	rrs, _, err := s.QueryRecords(ctx, "example.com", dns.TypeTXT)
+	common.Must(err)
+	if len(rrs) != 1 || rrs[0].(*dns.TXT).Txt[0] != "hello" {
+		t.Error("unexpected records: ", rrs)
+	}
+	if n := tcpQueries.Load(); n != 1 {
+		t.Error("expect 1 query over TCP, got ", n)
+	}
+}
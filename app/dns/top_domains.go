@@ -0,0 +1,110 @@
+package dns
+
+import (
+	"container/heap"
+	"sort"
+	"sync"
+)
+
+// topDomainsCapacity is the number of domains counted for TopDomains.
+const topDomainsCapacity = 1024
+
+// DomainCount is the number of lookups of a domain.
+type DomainCount struct {
+	Domain string
+	Count  int64
+}
+
+// topDomains counts the most looked up domains in bounded space, with the
+// space-saving algorithm: once full, a new domain replaces the least counted
+// one and inherits its count, so counts may be overestimated by at most the
+// count of the least counted domain.
+type topDomains struct {
+	sync.Mutex
+	capacity int
+	counts   map[string]*domainCount
+	heap     domainHeap
+}
+
+type domainCount struct {
+	DomainCount
+	index int
+}
+
+func newTopDomains(capacity int) *topDomains {
+	return &topDomains{
+		capacity: capacity,
+		counts:   make(map[string]*domainCount, capacity),
+	}
+}
+
+func (t *topDomains) add(domain string) {
+	t.Lock()
+	defer t.Unlock()
+	if c, found := t.counts[domain]; found {
+		c.Count++
+		heap.Fix(&t.heap, c.index)
+		return
+	}
+	if len(t.heap) < t.capacity {
+		c := &domainCount{DomainCount: DomainCount{Domain: domain, Count: 1}}
+		t.counts[domain] = c
+		heap.Push(&t.heap, c)
+		return
+	}
+	c := t.heap[0]
+	delete(t.counts, c.Domain)
+	c.Domain = domain
+	c.Count++
+	t.counts[domain] = c
+	heap.Fix(&t.heap, 0)
+}
+
+func (t *topDomains) top(n int, reset bool) []DomainCount {
+	t.Lock()
+	counts := make([]DomainCount, 0, len(t.heap))
+	for _, c := range t.heap {
+		counts = append(counts, c.DomainCount)
+	}
+	if reset {
+		t.counts = make(map[string]*domainCount, t.capacity)
+		t.heap = nil
+	}
+	t.Unlock()
+
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Domain < counts[j].Domain
+	})
+	if len(counts) > n {
+		counts = counts[:n]
+	}
+	return counts
+}
+
+// domainHeap is a min-heap of domainCounts by count.
+type domainHeap []*domainCount
+
+func (h domainHeap) Len() int           { return len(h) }
+func (h domainHeap) Less(i, j int) bool { return h[i].Count < h[j].Count }
+
+func (h domainHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *domainHeap) Push(x interface{}) {
+	c := x.(*domainCount)
+	c.index = len(*h)
+	*h = append(*h, c)
+}
+
+func (h *domainHeap) Pop() interface{} {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
@@ -0,0 +1,36 @@
+package dns
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTopDomains(t *testing.T) {
+	d := newTopDomains(3)
+	for i := 0; i < 5; i++ {
+		d.add("a.example")
+	}
+	for i := 0; i < 3; i++ {
+		d.add("b.example")
+	}
+	d.add("c.example")
+	d.add("d.example")
+	top := d.top(3, false)
+	if r := cmp.Diff(top, []DomainCount{{"a.example", 5}, {"b.example", 3}, {"d.example", 2}}); r != "" {
+		t.Error(r)
+	}
+
+	for i := 0; i < 1000; i++ {
+		d.add(strconv.Itoa(i) + ".random.example")
+	}
+	if len(d.counts) != 3 || len(d.heap) != 3 {
+		t.Fatal("expect 3 domains to be counted, got ", len(d.counts))
+	}
+
+	d.top(2, true)
+	if top := d.top(2, false); len(top) != 0 {
+		t.Error("expect counts to be reset, got ", top)
+	}
+}
@@ -0,0 +1,82 @@
+package observatory
+
+import (
+	"sync"
+)
+
+// CircuitBreaker counts consecutive failures of real connections through
+// outbounds. An outbound is open, that is considered dead, once its failures
+// reach the threshold, until it is reset by a successful probe. Successful
+// connections reset the count of an outbound that is not open yet.
+type CircuitBreaker struct {
+	threshold uint32
+
+	access sync.Mutex
+	states map[string]*breakerState
+}
+
+type breakerState struct {
+	failures  uint32
+	open      bool
+	lastError error
+}
+
+// NewCircuitBreaker creates a CircuitBreaker. It returns nil if threshold is
+// 0, which disables it.
+func NewCircuitBreaker(threshold uint32) *CircuitBreaker {
+	if threshold == 0 {
+		return nil
+	}
+	return &CircuitBreaker{
+		threshold: threshold,
+		states:    make(map[string]*breakerState),
+	}
+}
+
+// Report records the outcome of a connection through the outbound of tag. It
+// returns true if this failure opens the breaker of the outbound.
+func (b *CircuitBreaker) Report(tag string, err error) bool {
+	b.access.Lock()
+	defer b.access.Unlock()
+
+	state, found := b.states[tag]
+	if err == nil {
+		// A success through an open outbound is not enough to close it, as
+		// it may be the only one left for a fallback.
+		if found && !state.open {
+			delete(b.states, tag)
+		}
+		return false
+	}
+	if !found {
+		state = &breakerState{}
+		b.states[tag] = state
+	}
+	state.failures++
+	state.lastError = err
+	if !state.open && state.failures >= b.threshold {
+		state.open = true
+		return true
+	}
+	return false
+}
+
+// Open returns whether the breaker of the outbound is open, and the last
+// failure if so.
+func (b *CircuitBreaker) Open(tag string) (bool, error) {
+	b.access.Lock()
+	defer b.access.Unlock()
+
+	if state, found := b.states[tag]; found && state.open {
+		return true, state.lastError
+	}
+	return false, nil
+}
+
+// Reset closes the breaker of the outbound, after a successful probe.
+func (b *CircuitBreaker) Reset(tag string) {
+	b.access.Lock()
+	defer b.access.Unlock()
+
+	delete(b.states, tag)
+}
@@ -0,0 +1,43 @@
+package observatory_test
+
+import (
+	"testing"
+
+	. "github.com/GFW-knocker/Xray-core/app/observatory"
+	"github.com/GFW-knocker/Xray-core/common/errors"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	if NewCircuitBreaker(0) != nil {
+		t.Error("expect no breaker without threshold")
+	}
+
+	b := NewCircuitBreaker(3)
+	failure := errors.New("connection refused")
+
+	b.Report("a", failure)
+	b.Report("a", failure)
+	b.Report("a", nil)
+	if b.Report("a", failure) || b.Report("a", failure) {
+		t.Error("expect success to reset the failures")
+	}
+	if !b.Report("a", failure) {
+		t.Error("expect the third consecutive failure to open the breaker")
+	}
+	if b.Report("a", failure) {
+		t.Error("expect an open breaker not to open again")
+	}
+
+	b.Report("a", nil)
+	if open, err := b.Open("a"); !open || err != failure {
+		t.Error("expect breaker to stay open until reset, got ", open, err)
+	}
+	if open, _ := b.Open("b"); open {
+		t.Error("expect breaker of other outbounds to be closed")
+	}
+
+	b.Reset("a")
+	if open, _ := b.Open("a"); open {
+		t.Error("expect breaker to be closed after reset")
+	}
+}
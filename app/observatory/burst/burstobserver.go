@@ -13,6 +13,7 @@ import (
 	"github.com/GFW-knocker/Xray-core/features/extension"
 	"github.com/GFW-knocker/Xray-core/features/outbound"
 	"github.com/GFW-knocker/Xray-core/features/routing"
+	"github.com/GFW-knocker/Xray-core/features/stats"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -22,10 +23,12 @@ type Observer struct {
 
 	statusLock sync.Mutex
 	hp         *HealthPing
+	breaker    *observatory.CircuitBreaker
 
 	finished *done.Instance
 
-	ohm outbound.Manager
+	ohm     outbound.Manager
+	channel stats.Channel
 }
 
 func (o *Observer) GetObservation(ctx context.Context) (proto.Message, error) {
@@ -37,27 +40,79 @@ func (o *Observer) createResult() []*observatory.OutboundStatus {
 	o.hp.access.Lock()
 	defer o.hp.access.Unlock()
 	for name, value := range o.hp.Results {
-		status := observatory.OutboundStatus{
-			Alive:           value.getStatistics().All != value.getStatistics().Fail,
-			Delay:           value.getStatistics().Average.Milliseconds(),
-			LastErrorReason: "",
-			OutboundTag:     name,
-			LastSeenTime:    0,
-			LastTryTime:     0,
-			HealthPing: &observatory.HealthPingMeasurementResult{
-				All:       int64(value.getStatistics().All),
-				Fail:      int64(value.getStatistics().Fail),
-				Deviation: int64(value.getStatistics().Deviation),
-				Average:   int64(value.getStatistics().Average),
-				Max:       int64(value.getStatistics().Max),
-				Min:       int64(value.getStatistics().Min),
-			},
-		}
-		result = append(result, &status)
+		result = append(result, o.createStatus(name, value))
 	}
 	return result
 }
 
+func (o *Observer) createStatus(name string, value *HealthPingRTTS) *observatory.OutboundStatus {
+	status := observatory.OutboundStatus{
+		Alive:           value.getStatistics().All != value.getStatistics().Fail,
+		Delay:           value.getStatistics().Average.Milliseconds(),
+		LastErrorReason: "",
+		OutboundTag:     name,
+		LastSeenTime:    0,
+		LastTryTime:     0,
+		HealthPing: &observatory.HealthPingMeasurementResult{
+			All:       int64(value.getStatistics().All),
+			Fail:      int64(value.getStatistics().Fail),
+			Deviation: int64(value.getStatistics().Deviation),
+			Average:   int64(value.getStatistics().Average),
+			Max:       int64(value.getStatistics().Max),
+			Min:       int64(value.getStatistics().Min),
+		},
+	}
+	if o.breaker != nil {
+		if open, err := o.breaker.Open(name); open {
+			status.Alive = false
+			status.LastErrorReason = "consecutive connections failed, the last one with: " + err.Error()
+		}
+	}
+	return &status
+}
+
+// GetHistory implements observatory.HistoryProvider.
+func (o *Observer) GetHistory(tag string) []*observatory.HealthPingSample {
+	o.hp.access.Lock()
+	defer o.hp.access.Unlock()
+	if value, found := o.hp.Results[tag]; found {
+		return value.Samples()
+	}
+	return nil
+}
+
+// StatusChannel implements observatory.StatusPublisher.
+func (o *Observer) StatusChannel() stats.Channel {
+	return o.channel
+}
+
+func (o *Observer) publishStatus(tag string) {
+	o.hp.access.Lock()
+	value, found := o.hp.Results[tag]
+	var status *observatory.OutboundStatus
+	if found {
+		status = o.createStatus(tag, value)
+	}
+	o.hp.access.Unlock()
+	if status != nil {
+		observatory.PublishStatus(o.ctx, o.channel, status)
+	}
+}
+
+// ReportOutcome implements extension.OutcomeObserver.
+func (o *Observer) ReportOutcome(tag string, err error) {
+	if o.breaker == nil {
+		return
+	}
+	o.hp.access.Lock()
+	_, observed := o.hp.Results[tag]
+	o.hp.access.Unlock()
+	if observed && o.breaker.Report(tag, err) {
+		errors.LogInfoInner(o.ctx, err, "the outbound ", tag, " is dead: ", o.config.FailureThreshold, " consecutive connections failed")
+		o.publishStatus(tag)
+	}
+}
+
 func (o *Observer) Type() interface{} {
 	return extension.ObservatoryType()
 }
@@ -97,13 +152,22 @@ func New(ctx context.Context, config *Config) (*Observer, error) {
 	if err != nil {
 		return nil, errors.New("Cannot get depended features").Base(err)
 	}
-	hp := NewHealthPing(ctx, dispatcher, config.PingConfig)
-	return &Observer{
-		config: config,
-		ctx:    ctx,
-		ohm:    outboundManager,
-		hp:     hp,
-	}, nil
+	hp, err := NewHealthPing(ctx, dispatcher, config.PingConfig)
+	if err != nil {
+		return nil, err
+	}
+	breaker := observatory.NewCircuitBreaker(config.FailureThreshold)
+	hp.breaker = breaker
+	o := &Observer{
+		config:  config,
+		ctx:     ctx,
+		ohm:     outboundManager,
+		hp:      hp,
+		breaker: breaker,
+		channel: observatory.NewStatusChannel(),
+	}
+	hp.notify = o.publishStatus
+	return o, nil
 }
 
 func init() {
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/GFW-knocker/Xray-core/app/observatory"
 	"github.com/GFW-knocker/Xray-core/common/dice"
 	"github.com/GFW-knocker/Xray-core/common/errors"
 	"github.com/GFW-knocker/Xray-core/features/routing"
@@ -32,10 +33,18 @@ type HealthPing struct {
 
 	Settings *HealthPingSettings
 	Results  map[string]*HealthPingRTTS
+
+	// prober probes the outbounds instead of HTTP requests to Destination
+	// if not nil.
+	prober *observatory.Prober
+	// breaker is reset by successful pings if not nil.
+	breaker *observatory.CircuitBreaker
+	// notify is called with the tag of each result put if not nil.
+	notify func(tag string)
 }
 
 // NewHealthPing creates a new HealthPing with settings
-func NewHealthPing(ctx context.Context, dispatcher routing.Dispatcher, config *HealthPingConfig) *HealthPing {
+func NewHealthPing(ctx context.Context, dispatcher routing.Dispatcher, config *HealthPingConfig) (*HealthPing, error) {
 	settings := &HealthPingSettings{}
 	if config != nil {
 
@@ -75,12 +84,17 @@ func NewHealthPing(ctx context.Context, dispatcher routing.Dispatcher, config *H
 		// a larger timeout could possibly makes checks run longer
 		settings.Timeout = time.Duration(5) * time.Second
 	}
+	prober, err := observatory.NewProber(config.GetProbe())
+	if err != nil {
+		return nil, err
+	}
 	return &HealthPing{
 		ctx:        ctx,
 		dispatcher: dispatcher,
 		Settings:   settings,
 		Results:    nil,
-	}
+		prober:     prober,
+	}, nil
 }
 
 // StartScheduler implements the HealthChecker
@@ -174,7 +188,7 @@ func (h *HealthPing) doCheck(tags []string, duration time.Duration, rounds int)
 			}
 			time.AfterFunc(delay, func() {
 				errors.LogDebug(h.ctx, "checking ", handler)
-				delay, err := client.MeasureDelay(h.Settings.HttpMethod)
+				delay, err := h.measureDelay(client, handler)
 				if err == nil {
 					ch <- &rtt{
 						handler: handler,
@@ -212,8 +226,30 @@ func (h *HealthPing) doCheck(tags []string, duration time.Duration, rounds int)
 	}
 }
 
+// measureDelay measures the delay of handler with the prober if set, or
+// else with client.
+func (h *HealthPing) measureDelay(client *pingClient, handler string) (time.Duration, error) {
+	if h.prober == nil {
+		return client.MeasureDelay(h.Settings.HttpMethod)
+	}
+	ctx, cancel := context.WithTimeout(h.ctx, h.Settings.Timeout)
+	defer cancel()
+	delay, err := h.prober.Probe(ctx, h.dispatcher, handler)
+	if err != nil {
+		return rttFailed, err
+	}
+	return delay, nil
+}
+
 // PutResult put a ping rtt to results
 func (h *HealthPing) PutResult(tag string, rtt time.Duration) {
+	h.putResult(tag, rtt)
+	if h.notify != nil {
+		h.notify(tag)
+	}
+}
+
+func (h *HealthPing) putResult(tag string, rtt time.Duration) {
 	h.access.Lock()
 	defer h.access.Unlock()
 	if h.Results == nil {
@@ -230,6 +266,9 @@ func (h *HealthPing) PutResult(tag string, rtt time.Duration) {
 		h.Results[tag] = r
 	}
 	r.Put(rtt)
+	if rtt != rttFailed && h.breaker != nil {
+		h.breaker.Reset(tag)
+	}
 }
 
 // Cleanup removes results of removed handlers,
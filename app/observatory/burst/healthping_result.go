@@ -3,6 +3,8 @@ package burst
 import (
 	"math"
 	"time"
+
+	"github.com/GFW-knocker/Xray-core/app/observatory"
 )
 
 // HealthPingStats is the statistics of HealthPingRTTS
@@ -69,6 +71,26 @@ func (h *HealthPingRTTS) Put(d time.Duration) {
 	h.rtts[h.idx].value = d
 }
 
+// Samples returns the rtts put to the HealthPingResult, oldest first
+func (h *HealthPingRTTS) Samples() []*observatory.HealthPingSample {
+	samples := make([]*observatory.HealthPingSample, 0, len(h.rtts))
+	for i := 1; i <= len(h.rtts); i++ {
+		rtt := h.rtts[h.calcIndex(i)]
+		if rtt.time.IsZero() {
+			continue
+		}
+		sample := &observatory.HealthPingSample{
+			Time:  rtt.time.Unix(),
+			Alive: rtt.value != rttFailed,
+		}
+		if sample.Alive {
+			sample.Delay = rtt.value.Milliseconds()
+		}
+		samples = append(samples, sample)
+	}
+	return samples
+}
+
 func (h *HealthPingRTTS) calcIndex(step int) int {
 	idx := h.idx
 	idx += step
@@ -104,3 +104,25 @@ func TestHealthPingResultsIgnoreOutdated(t *testing.T) {
 		t.Errorf("expected: %v, actual: %v", expected, actual)
 	}
 }
+
+func TestHealthPingResultSamples(t *testing.T) {
+	hr := burst.NewHealthPingResult(3, time.Hour)
+	if samples := hr.Samples(); len(samples) != 0 {
+		t.Errorf("expected no samples, actual: %v", samples)
+	}
+	for _, rtt := range []time.Duration{10, 20, 30} {
+		hr.Put(rtt * time.Millisecond)
+	}
+	// failed
+	hr.Put(time.Duration(math.MaxInt64))
+	samples := hr.Samples()
+	if len(samples) != 3 {
+		t.Fatalf("expected 3 samples, actual: %v", samples)
+	}
+	if samples[0].Delay != 20 || samples[1].Delay != 30 || !samples[1].Alive {
+		t.Errorf("expected the samples oldest first, actual: %v", samples)
+	}
+	if samples[2].Alive || samples[2].Delay != 0 {
+		t.Errorf("expected the last sample failed, actual: %v", samples[2])
+	}
+}
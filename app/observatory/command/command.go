@@ -5,9 +5,12 @@ import (
 
 	"github.com/GFW-knocker/Xray-core/app/observatory"
 	"github.com/GFW-knocker/Xray-core/common"
+	"github.com/GFW-knocker/Xray-core/common/errors"
 	core "github.com/GFW-knocker/Xray-core/core"
 	"github.com/GFW-knocker/Xray-core/features/extension"
+	"github.com/GFW-knocker/Xray-core/features/stats"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
 )
 
 type service struct {
@@ -17,6 +20,13 @@ type service struct {
 	observatory extension.Observatory
 }
 
+// NewObservatoryServer creates a statistics service with observatory.
+func NewObservatoryServer(observatory extension.Observatory) ObservatoryServiceServer {
+	return &service{
+		observatory: observatory,
+	}
+}
+
 func (s *service) GetOutboundStatus(ctx context.Context, request *GetOutboundStatusRequest) (*GetOutboundStatusResponse, error) {
 	resp, err := s.observatory.GetObservation(ctx)
 	if err != nil {
@@ -28,6 +38,61 @@ func (s *service) GetOutboundStatus(ctx context.Context, request *GetOutboundSta
 	}, nil
 }
 
+func (s *service) SubscribeOutboundStatus(request *SubscribeOutboundStatusRequest, stream ObservatoryService_SubscribeOutboundStatusServer) error {
+	publisher, ok := s.observatory.(observatory.StatusPublisher)
+	if !ok {
+		return errors.New("observatory does not publish outbound status")
+	}
+	subscriber, err := stats.SubscribeRunnableChannel(publisher.StatusChannel())
+	if err != nil {
+		return err
+	}
+	defer stats.UnsubscribeClosableChannel(publisher.StatusChannel(), subscriber)
+
+	filter := newStatusFilter(request)
+	send := func(status *observatory.OutboundStatus) error {
+		reason, changed := filter.update(status)
+		if !changed {
+			return nil
+		}
+		event := &OutboundStatusEvent{
+			Reason: reason,
+			Status: status,
+		}
+		if history, ok := s.observatory.(observatory.HistoryProvider); ok && reason == OutboundStatusEvent_INITIAL {
+			event.History = history.GetHistory(status.OutboundTag)
+		}
+		return stream.Send(event)
+	}
+
+	result, err := s.observatory.GetObservation(stream.Context())
+	if err != nil {
+		return err
+	}
+	for _, status := range result.(*observatory.ObservationResult).Status {
+		if err := send(proto.Clone(status).(*observatory.OutboundStatus)); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case value, ok := <-subscriber:
+			if !ok {
+				return errors.New("upstream closed the subscriber channel")
+			}
+			status, ok := value.(*observatory.OutboundStatus)
+			if !ok {
+				return errors.New("upstream sent malformed statistics")
+			}
+			if err := send(status); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
 func (s *service) Register(server *grpc.Server) {
 	RegisterObservatoryServiceServer(server, s)
 }
@@ -19,7 +19,8 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	ObservatoryService_GetOutboundStatus_FullMethodName = "/xray.core.app.observatory.command.ObservatoryService/GetOutboundStatus"
+	ObservatoryService_GetOutboundStatus_FullMethodName       = "/xray.core.app.observatory.command.ObservatoryService/GetOutboundStatus"
+	ObservatoryService_SubscribeOutboundStatus_FullMethodName = "/xray.core.app.observatory.command.ObservatoryService/SubscribeOutboundStatus"
 )
 
 // ObservatoryServiceClient is the client API for ObservatoryService service.
@@ -27,6 +28,7 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ObservatoryServiceClient interface {
 	GetOutboundStatus(ctx context.Context, in *GetOutboundStatusRequest, opts ...grpc.CallOption) (*GetOutboundStatusResponse, error)
+	SubscribeOutboundStatus(ctx context.Context, in *SubscribeOutboundStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OutboundStatusEvent], error)
 }
 
 type observatoryServiceClient struct {
@@ -47,11 +49,31 @@ func (c *observatoryServiceClient) GetOutboundStatus(ctx context.Context, in *Ge
 	return out, nil
 }
 
+func (c *observatoryServiceClient) SubscribeOutboundStatus(ctx context.Context, in *SubscribeOutboundStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OutboundStatusEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ObservatoryService_ServiceDesc.Streams[0], ObservatoryService_SubscribeOutboundStatus_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeOutboundStatusRequest, OutboundStatusEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ObservatoryService_SubscribeOutboundStatusClient = grpc.ServerStreamingClient[OutboundStatusEvent]
+
 // ObservatoryServiceServer is the server API for ObservatoryService service.
 // All implementations must embed UnimplementedObservatoryServiceServer
 // for forward compatibility.
 type ObservatoryServiceServer interface {
 	GetOutboundStatus(context.Context, *GetOutboundStatusRequest) (*GetOutboundStatusResponse, error)
+	SubscribeOutboundStatus(*SubscribeOutboundStatusRequest, grpc.ServerStreamingServer[OutboundStatusEvent]) error
 	mustEmbedUnimplementedObservatoryServiceServer()
 }
 
@@ -65,6 +87,9 @@ type UnimplementedObservatoryServiceServer struct{}
 func (UnimplementedObservatoryServiceServer) GetOutboundStatus(context.Context, *GetOutboundStatusRequest) (*GetOutboundStatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetOutboundStatus not implemented")
 }
+func (UnimplementedObservatoryServiceServer) SubscribeOutboundStatus(*SubscribeOutboundStatusRequest, grpc.ServerStreamingServer[OutboundStatusEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeOutboundStatus not implemented")
+}
 func (UnimplementedObservatoryServiceServer) mustEmbedUnimplementedObservatoryServiceServer() {}
 func (UnimplementedObservatoryServiceServer) testEmbeddedByValue()                            {}
 
@@ -104,6 +129,17 @@ func _ObservatoryService_GetOutboundStatus_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ObservatoryService_SubscribeOutboundStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeOutboundStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ObservatoryServiceServer).SubscribeOutboundStatus(m, &grpc.GenericServerStream[SubscribeOutboundStatusRequest, OutboundStatusEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ObservatoryService_SubscribeOutboundStatusServer = grpc.ServerStreamingServer[OutboundStatusEvent]
+
 // ObservatoryService_ServiceDesc is the grpc.ServiceDesc for ObservatoryService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -116,6 +152,12 @@ var ObservatoryService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _ObservatoryService_GetOutboundStatus_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeOutboundStatus",
+			Handler:       _ObservatoryService_SubscribeOutboundStatus_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "app/observatory/command/command.proto",
 }
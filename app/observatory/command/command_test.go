@@ -0,0 +1,120 @@
+package command_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GFW-knocker/Xray-core/app/observatory"
+	. "github.com/GFW-knocker/Xray-core/app/observatory/command"
+	"github.com/GFW-knocker/Xray-core/common"
+	"github.com/GFW-knocker/Xray-core/common/net"
+	"github.com/GFW-knocker/Xray-core/features/extension"
+	"github.com/GFW-knocker/Xray-core/features/stats"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+type testObservatory struct {
+	status  []*observatory.OutboundStatus
+	channel stats.Channel
+}
+
+func (o *testObservatory) Type() interface{} {
+	return extension.ObservatoryType()
+}
+
+func (o *testObservatory) Start() error {
+	return nil
+}
+
+func (o *testObservatory) Close() error {
+	return nil
+}
+
+func (o *testObservatory) GetObservation(ctx context.Context) (proto.Message, error) {
+	return &observatory.ObservationResult{Status: o.status}, nil
+}
+
+func (o *testObservatory) StatusChannel() stats.Channel {
+	return o.channel
+}
+
+func (o *testObservatory) GetHistory(tag string) []*observatory.HealthPingSample {
+	return []*observatory.HealthPingSample{
+		{Time: 1, Delay: 90, Alive: true},
+		{Time: 2, Alive: false},
+	}
+}
+
+func TestServiceSubscribeOutboundStatus(t *testing.T) {
+	o := &testObservatory{
+		status: []*observatory.OutboundStatus{
+			{OutboundTag: "a", Alive: true, Delay: 100},
+		},
+		channel: observatory.NewStatusChannel(),
+	}
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	RegisterObservatoryServiceServer(server, NewObservatoryServer(o))
+	go server.Serve(lis)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet", grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+		return lis.Dial()
+	}), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	common.Must(err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	stream, err := NewObservatoryServiceClient(conn).SubscribeOutboundStatus(ctx, &SubscribeOutboundStatusRequest{
+		DelayChangeMs: 5,
+	})
+	common.Must(err)
+
+	event, err := stream.Recv()
+	common.Must(err)
+	if event.Reason != OutboundStatusEvent_INITIAL || event.Status.OutboundTag != "a" || len(event.History) != 2 {
+		t.Fatal("unexpected initial event: ", event)
+	}
+
+	for len(o.channel.Subscribers()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	for _, status := range []*observatory.OutboundStatus{
+		{OutboundTag: "a", Alive: true, Delay: 110}, // less than 20%
+		{OutboundTag: "a", Alive: true, Delay: 130},
+		{OutboundTag: "a", Alive: false, Delay: 99999999},
+		{OutboundTag: "b", Alive: true, Delay: 4},
+		{OutboundTag: "b", Alive: true, Delay: 8}, // less than 5ms
+	} {
+		observatory.PublishStatus(context.Background(), o.channel, status)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	expected := []struct {
+		reason OutboundStatusEvent_Reason
+		tag    string
+		delay  int64
+	}{
+		{OutboundStatusEvent_DELAY_CHANGED, "a", 130},
+		{OutboundStatusEvent_ALIVE_CHANGED, "a", 99999999},
+		{OutboundStatusEvent_INITIAL, "b", 4},
+	}
+	for _, e := range expected {
+		event, err := stream.Recv()
+		common.Must(err)
+		if event.Reason != e.reason || event.Status.OutboundTag != e.tag || event.Status.Delay != e.delay {
+			t.Error("expect ", e, ", but got ", event)
+		}
+	}
+
+	cancel()
+	for len(o.channel.Subscribers()) != 0 {
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,55 @@
+package command
+
+import (
+	"github.com/GFW-knocker/Xray-core/app/observatory"
+)
+
+const defaultDelayChangePercent = 20
+
+// statusFilter drops the status updates of outbounds which do not differ
+// significantly from the last ones sent.
+type statusFilter struct {
+	delayChangePercent int64
+	delayChangeMs      int64
+
+	last map[string]*observatory.OutboundStatus
+}
+
+func newStatusFilter(request *SubscribeOutboundStatusRequest) *statusFilter {
+	f := &statusFilter{
+		delayChangePercent: int64(request.DelayChangePercent),
+		delayChangeMs:      request.DelayChangeMs,
+		last:               make(map[string]*observatory.OutboundStatus),
+	}
+	if f.delayChangePercent == 0 {
+		f.delayChangePercent = defaultDelayChangePercent
+	}
+	return f
+}
+
+// update returns the reason to send status, and false if it should not be
+// sent.
+func (f *statusFilter) update(status *observatory.OutboundStatus) (OutboundStatusEvent_Reason, bool) {
+	last, found := f.last[status.OutboundTag]
+	var reason OutboundStatusEvent_Reason
+	switch {
+	case !found:
+		reason = OutboundStatusEvent_INITIAL
+	case last.Alive != status.Alive:
+		reason = OutboundStatusEvent_ALIVE_CHANGED
+	case status.Alive && f.delayChanged(last.Delay, status.Delay):
+		reason = OutboundStatusEvent_DELAY_CHANGED
+	default:
+		return 0, false
+	}
+	f.last[status.OutboundTag] = status
+	return reason, true
+}
+
+func (f *statusFilter) delayChanged(last, delay int64) bool {
+	change := delay - last
+	if change < 0 {
+		change = -change
+	}
+	return change > 0 && change >= f.delayChangeMs && change*100 >= last*f.delayChangePercent
+}
@@ -6,9 +6,11 @@ import (
 	"net/http"
 	"net/url"
 	"sort"
+	"strconv"
 	"sync"
 	"time"
 
+	"github.com/GFW-knocker/Xray-core/app/stats"
 	"github.com/GFW-knocker/Xray-core/common"
 	"github.com/GFW-knocker/Xray-core/common/errors"
 	v2net "github.com/GFW-knocker/Xray-core/common/net"
@@ -19,6 +21,7 @@ import (
 	"github.com/GFW-knocker/Xray-core/features/extension"
 	"github.com/GFW-knocker/Xray-core/features/outbound"
 	"github.com/GFW-knocker/Xray-core/features/routing"
+	feature_stats "github.com/GFW-knocker/Xray-core/features/stats"
 	"github.com/GFW-knocker/Xray-core/transport/internet/tagged"
 	"google.golang.org/protobuf/proto"
 )
@@ -29,15 +32,49 @@ type Observer struct {
 
 	statusLock sync.Mutex
 	status     []*OutboundStatus
+	// observed is the set of outbounds of the current probe round.
+	observed map[string]bool
 
 	finished *done.Instance
 
 	ohm        outbound.Manager
 	dispatcher routing.Dispatcher
+	prober     *Prober
+	breaker    *CircuitBreaker
+	channel    *stats.Channel
 }
 
 func (o *Observer) GetObservation(ctx context.Context) (proto.Message, error) {
-	return &ObservationResult{Status: o.status}, nil
+	o.statusLock.Lock()
+	defer o.statusLock.Unlock()
+	status := make([]*OutboundStatus, 0, len(o.status))
+	for _, s := range o.status {
+		status = append(status, proto.Clone(s).(*OutboundStatus))
+	}
+	return &ObservationResult{Status: status}, nil
+}
+
+// ReportOutcome implements extension.OutcomeObserver.
+func (o *Observer) ReportOutcome(tag string, err error) {
+	if o.breaker == nil || !o.observes(tag) {
+		return
+	}
+	if o.breaker.Report(tag, err) {
+		errorMessage := "the outbound " + tag + " is dead: " + strconv.Itoa(int(o.config.FailureThreshold)) + " consecutive connections failed, the last one with: " + err.Error()
+		errors.LogInfo(o.ctx, errorMessage)
+		o.updateStatusForResult(tag, &ProbeResult{Alive: false, LastErrorReason: errorMessage})
+	}
+}
+
+func (o *Observer) observes(tag string) bool {
+	o.statusLock.Lock()
+	defer o.statusLock.Unlock()
+	return o.observed[tag]
+}
+
+// StatusChannel implements StatusPublisher.
+func (o *Observer) StatusChannel() feature_stats.Channel {
+	return o.channel
 }
 
 func (o *Observer) Type() interface{} {
@@ -114,10 +151,16 @@ func (o *Observer) updateStatus(outbounds []string) {
 	o.statusLock.Lock()
 	defer o.statusLock.Unlock()
 	// TODO should remove old inbound that is removed
-	_ = outbounds
+	o.observed = make(map[string]bool, len(outbounds))
+	for _, tag := range outbounds {
+		o.observed[tag] = true
+	}
 }
 
 func (o *Observer) probe(outbound string) ProbeResult {
+	if o.prober != nil {
+		return o.probeWithProber(outbound)
+	}
 	errorCollectorForRequest := newErrorCollector()
 
 	httpTransport := http.Transport{
@@ -182,6 +225,20 @@ func (o *Observer) probe(outbound string) ProbeResult {
 	return ProbeResult{Alive: true, Delay: GETTime.Milliseconds()}
 }
 
+func (o *Observer) probeWithProber(outbound string) ProbeResult {
+	errorCollectorForRequest := newErrorCollector()
+	ctx, cancel := context.WithTimeout(session.TrackedConnectionError(o.ctx, errorCollectorForRequest), time.Second*5)
+	defer cancel()
+	delay, err := o.prober.Probe(ctx, o.dispatcher, outbound)
+	if err != nil {
+		var errorMessage = "the outbound " + outbound + " is dead: " + o.config.Probe.Type.String() + " probe failed:" + err.Error() + "with outbound handler report underlying connection failed"
+		errors.LogInfoInner(o.ctx, errorCollectorForRequest.UnderlyingError(), errorMessage)
+		return ProbeResult{Alive: false, LastErrorReason: errorMessage}
+	}
+	errors.LogInfo(o.ctx, "the outbound ", outbound, " is alive:", delay.Seconds())
+	return ProbeResult{Alive: true, Delay: delay.Milliseconds()}
+}
+
 func (o *Observer) updateStatusForResult(outbound string, result *ProbeResult) {
 	o.statusLock.Lock()
 	defer o.statusLock.Unlock()
@@ -197,6 +254,9 @@ func (o *Observer) updateStatusForResult(outbound string, result *ProbeResult) {
 	status.OutboundTag = outbound
 	status.Alive = result.Alive
 	if result.Alive {
+		if o.breaker != nil {
+			o.breaker.Reset(outbound)
+		}
 		status.Delay = result.Delay
 		status.LastSeenTime = status.LastTryTime
 		status.LastErrorReason = ""
@@ -204,6 +264,7 @@ func (o *Observer) updateStatusForResult(outbound string, result *ProbeResult) {
 		status.LastErrorReason = result.LastErrorReason
 		status.Delay = 99999999
 	}
+	PublishStatus(o.ctx, o.channel, proto.Clone(status).(*OutboundStatus))
 }
 
 func (o *Observer) findStatusLocationLockHolderOnly(outbound string) int {
@@ -225,11 +286,18 @@ func New(ctx context.Context, config *Config) (*Observer, error) {
 	if err != nil {
 		return nil, errors.New("Cannot get depended features").Base(err)
 	}
+	prober, err := NewProber(config.Probe)
+	if err != nil {
+		return nil, err
+	}
 	return &Observer{
 		config:     config,
 		ctx:        ctx,
 		ohm:        outboundManager,
 		dispatcher: dispatcher,
+		prober:     prober,
+		breaker:    NewCircuitBreaker(config.FailureThreshold),
+		channel:    NewStatusChannel(),
 	}, nil
 }
 
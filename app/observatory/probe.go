@@ -0,0 +1,194 @@
+package observatory
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"io"
+	"time"
+
+	"github.com/GFW-knocker/Xray-core/common/dice"
+	"github.com/GFW-knocker/Xray-core/common/errors"
+	"github.com/GFW-knocker/Xray-core/common/net"
+	"github.com/GFW-knocker/Xray-core/features/routing"
+	"github.com/GFW-knocker/Xray-core/transport/internet/tagged"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	defaultProbeQuery          = "www.google.com."
+	defaultDNSProbeDestination = "1.1.1.1:53"
+)
+
+// Prober measures the delay of an outbound with a request other than HTTP.
+type Prober struct {
+	config      *ProbeConfig
+	destination net.Destination
+}
+
+// NewProber creates a Prober for config. It returns nil for HTTP probes, which
+// the observers make themselves.
+func NewProber(config *ProbeConfig) (*Prober, error) {
+	if config.GetType() == ProbeConfig_HTTP {
+		return nil, nil
+	}
+	network := "tcp:"
+	switch config.Type {
+	case ProbeConfig_DNS, ProbeConfig_UDP:
+		network = "udp:"
+	}
+	address := config.Destination
+	if address == "" && config.Type == ProbeConfig_DNS {
+		address = defaultDNSProbeDestination
+	}
+	destination, err := net.ParseDestination(network + address)
+	if err != nil {
+		return nil, errors.New("invalid probe destination ", address).Base(err)
+	}
+	switch config.Type {
+	case ProbeConfig_TCP:
+		// Without a payload, services where the client speaks first would
+		// never answer.
+		if len(config.Payload) == 0 {
+			return nil, errors.New("TCP probe requires a payload")
+		}
+	case ProbeConfig_UDP:
+		if len(config.Payload) == 0 {
+			return nil, errors.New("UDP probe requires a payload")
+		}
+	}
+	return &Prober{
+		config:      config,
+		destination: destination,
+	}, nil
+}
+
+// Probe probes destination through the outbound of tag. ctx must carry the
+// Xray instance, and should have a deadline.
+func (p *Prober) Probe(ctx context.Context, dispatcher routing.Dispatcher, tag string) (time.Duration, error) {
+	start := time.Now()
+	conn, err := tagged.Dialer(ctx, dispatcher, p.destination, tag)
+	if err != nil {
+		return 0, errors.New("cannot dial remote address ", p.destination).Base(err)
+	}
+	defer conn.Close()
+
+	// Connections through outbounds ignore deadlines, so close it to stop
+	// the probe.
+	stop := context.AfterFunc(ctx, func() {
+		conn.Close()
+	})
+	defer stop()
+
+	switch p.config.Type {
+	case ProbeConfig_TCP:
+		err = p.probeTCP(conn)
+	case ProbeConfig_TLS:
+		err = p.probeTLS(ctx, conn)
+	case ProbeConfig_DNS:
+		err = p.probeDNS(conn)
+	case ProbeConfig_UDP:
+		err = p.probeUDP(conn)
+	default:
+		err = errors.New("unknown probe type ", p.config.Type)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, errors.New("probe timed out").Base(err)
+		}
+		return 0, err
+	}
+	return time.Since(start), nil
+}
+
+func (p *Prober) probeTCP(conn net.Conn) error {
+	if _, err := conn.Write(p.config.Payload); err != nil {
+		return err
+	}
+	var b [1]byte
+	if _, err := io.ReadFull(conn, b[:]); err != nil {
+		return errors.New("no response from ", p.destination).Base(err)
+	}
+	return nil
+}
+
+func (p *Prober) probeTLS(ctx context.Context, conn net.Conn) error {
+	serverName := p.config.ServerName
+	if serverName == "" {
+		serverName = p.destination.Address.String()
+	}
+	tlsConn := tls.Client(conn, &tls.Config{
+		ServerName: serverName,
+	})
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return errors.New("TLS handshake with ", p.destination, " failed").Base(err)
+	}
+	return nil
+}
+
+func (p *Prober) probeDNS(conn net.Conn) error {
+	query := p.config.Query
+	if query == "" {
+		query = defaultProbeQuery
+	}
+	if query[len(query)-1] != '.' {
+		query += "."
+	}
+	name, err := dnsmessage.NewName(query)
+	if err != nil {
+		return errors.New("invalid probe query ", query).Base(err)
+	}
+	id := dice.RollUint16()
+	msg := dnsmessage.Message{
+		Header: dnsmessage.Header{
+			ID:               id,
+			RecursionDesired: true,
+		},
+		Questions: []dnsmessage.Question{{
+			Name:  name,
+			Type:  dnsmessage.TypeA,
+			Class: dnsmessage.ClassINET,
+		}},
+	}
+	b, err := msg.Pack()
+	if err != nil {
+		return err
+	}
+	if _, err := conn.Write(b); err != nil {
+		return err
+	}
+
+	buf := make([]byte, 1500)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return errors.New("no DNS response from ", p.destination).Base(err)
+		}
+		var parser dnsmessage.Parser
+		header, err := parser.Start(buf[:n])
+		if err != nil || header.ID != id || !header.Response {
+			// not the response to our query
+			continue
+		}
+		if header.RCode != dnsmessage.RCodeSuccess && header.RCode != dnsmessage.RCodeNameError {
+			return errors.New("DNS query of ", query, " failed: ", header.RCode)
+		}
+		return nil
+	}
+}
+
+func (p *Prober) probeUDP(conn net.Conn) error {
+	if _, err := conn.Write(p.config.Payload); err != nil {
+		return err
+	}
+	buf := make([]byte, 1500)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return errors.New("no echo from ", p.destination).Base(err)
+		}
+		if bytes.Equal(buf[:n], p.config.Payload) {
+			return nil
+		}
+	}
+}
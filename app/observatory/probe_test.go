@@ -0,0 +1,127 @@
+package observatory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GFW-knocker/Xray-core/app/dispatcher"
+	. "github.com/GFW-knocker/Xray-core/app/observatory"
+	"github.com/GFW-knocker/Xray-core/app/proxyman"
+	_ "github.com/GFW-knocker/Xray-core/app/proxyman/outbound"
+	"github.com/GFW-knocker/Xray-core/common"
+	"github.com/GFW-knocker/Xray-core/common/serial"
+	"github.com/GFW-knocker/Xray-core/core"
+	"github.com/GFW-knocker/Xray-core/features/extension"
+	"github.com/GFW-knocker/Xray-core/proxy/blackhole"
+	"github.com/GFW-knocker/Xray-core/proxy/freedom"
+	"github.com/GFW-knocker/Xray-core/testing/servers/tcp"
+	"github.com/GFW-knocker/Xray-core/testing/servers/udp"
+	_ "github.com/GFW-knocker/Xray-core/transport/internet/tagged/taggedimpl"
+	_ "github.com/GFW-knocker/Xray-core/transport/internet/tcp"
+)
+
+func observe(t *testing.T, probe *ProbeConfig) map[string]*OutboundStatus {
+	config := &core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&Config{
+				SubjectSelector:   []string{"direct", "block"},
+				Probe:             probe,
+				EnableConcurrency: true,
+			}),
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				Tag:           "direct",
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+			{
+				Tag:           "block",
+				ProxySettings: serial.ToTypedMessage(&blackhole.Config{}),
+			},
+		},
+	}
+	v, err := core.New(config)
+	common.Must(err)
+	common.Must(v.Start())
+	defer v.Close()
+
+	observatory := v.GetFeature(extension.ObservatoryType()).(extension.Observatory)
+	for range 100 {
+		result, err := observatory.GetObservation(context.Background())
+		common.Must(err)
+		status := result.(*ObservationResult).Status
+		if len(status) == 2 {
+			statusMap := make(map[string]*OutboundStatus)
+			for _, s := range status {
+				statusMap[s.OutboundTag] = s
+			}
+			return statusMap
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Fatal("outbounds are not probed")
+	return nil
+}
+
+func TestProbeTCP(t *testing.T) {
+	tcpServer := tcp.Server{
+		MsgProcessor: func(msg []byte) []byte { return msg },
+	}
+	dest, err := tcpServer.Start()
+	common.Must(err)
+	defer tcpServer.Close()
+
+	status := observe(t, &ProbeConfig{
+		Type:        ProbeConfig_TCP,
+		Destination: dest.NetAddr(),
+		Payload:     []byte("ping"),
+	})
+	if !status["direct"].Alive {
+		t.Error("expect direct to be alive: ", status["direct"].LastErrorReason)
+	}
+	if status["block"].Alive {
+		t.Error("expect block to be dead")
+	}
+}
+
+func TestProbeUDP(t *testing.T) {
+	udpServer := udp.Server{
+		MsgProcessor: func(msg []byte) []byte { return msg },
+	}
+	dest, err := udpServer.Start()
+	common.Must(err)
+	defer udpServer.Close()
+
+	status := observe(t, &ProbeConfig{
+		Type:        ProbeConfig_UDP,
+		Destination: dest.NetAddr(),
+		Payload:     []byte("ping"),
+	})
+	if !status["direct"].Alive {
+		t.Error("expect direct to be alive: ", status["direct"].LastErrorReason)
+	}
+	if status["block"].Alive {
+		t.Error("expect block to be dead")
+	}
+}
+
+func TestNewProber(t *testing.T) {
+	if p, err := NewProber(&ProbeConfig{Type: ProbeConfig_HTTP}); p != nil || err != nil {
+		t.Error("expect no prober for HTTP probes")
+	}
+	if _, err := NewProber(&ProbeConfig{Type: ProbeConfig_TCP, Destination: "127.0.0.1:80"}); err == nil {
+		t.Error("expect error for TCP probe without payload")
+	}
+	if _, err := NewProber(&ProbeConfig{Type: ProbeConfig_UDP, Destination: "127.0.0.1:7"}); err == nil {
+		t.Error("expect error for UDP probe without payload")
+	}
+	if _, err := NewProber(&ProbeConfig{Type: ProbeConfig_TLS}); err == nil {
+		t.Error("expect error for probe without destination")
+	}
+	if _, err := NewProber(&ProbeConfig{Type: ProbeConfig_DNS}); err != nil {
+		t.Error("expect default destination for DNS probes: ", err)
+	}
+}
@@ -0,0 +1,42 @@
+package observatory
+
+import (
+	"context"
+
+	"github.com/GFW-knocker/Xray-core/app/stats"
+	"github.com/GFW-knocker/Xray-core/features/extension"
+	feature_stats "github.com/GFW-knocker/Xray-core/features/stats"
+)
+
+// StatusPublisher is an Observatory which publishes every update of the
+// status of an outbound, as an *OutboundStatus, to its StatusChannel.
+type StatusPublisher interface {
+	extension.Observatory
+	StatusChannel() feature_stats.Channel
+}
+
+// HistoryProvider is an Observatory which keeps the recent samples of the
+// outbounds it observes.
+type HistoryProvider interface {
+	extension.Observatory
+	// GetHistory returns the samples of the outbound of tag, oldest first.
+	GetHistory(tag string) []*HealthPingSample
+}
+
+// NewStatusChannel creates the channel of a StatusPublisher. It is started
+// and closed by its subscribers.
+func NewStatusChannel() *stats.Channel {
+	return stats.NewChannel(&stats.ChannelConfig{
+		SubscriberLimit: 16,
+		BufferSize:      64,
+		Blocking:        false,
+	})
+}
+
+// PublishStatus publishes status to channel if it has any subscriber.
+func PublishStatus(ctx context.Context, channel feature_stats.Channel, status *OutboundStatus) {
+	if len(channel.Subscribers()) == 0 {
+		return
+	}
+	channel.Publish(ctx, status)
+}
@@ -85,10 +85,12 @@ func (p *Policy) ToCorePolicy() policy.Session {
 func (p *SystemPolicy) ToCorePolicy() policy.System {
 	return policy.System{
 		Stats: policy.SystemStats{
-			InboundUplink:    p.Stats.InboundUplink,
-			InboundDownlink:  p.Stats.InboundDownlink,
-			OutboundUplink:   p.Stats.OutboundUplink,
-			OutboundDownlink: p.Stats.OutboundDownlink,
+			InboundUplink:       p.Stats.InboundUplink,
+			InboundDownlink:     p.Stats.InboundDownlink,
+			OutboundUplink:      p.Stats.OutboundUplink,
+			OutboundDownlink:    p.Stats.OutboundDownlink,
+			RuleHits:            p.Stats.RuleHits,
+			OutboundConnections: p.Stats.OutboundConnections,
 		},
 	}
 }
@@ -92,6 +92,7 @@ func (w *tcpWorker) callback(conn stat.Connection) {
 	ctx = session.ContextWithInbound(ctx, &session.Inbound{
 		Source:  net.DestinationFromAddr(conn.RemoteAddr()),
 		Gateway: net.TCPDestination(w.address, w.port),
+		Local:   net.DestinationFromAddr(conn.LocalAddr()),
 		Tag:     w.tag,
 		Conn:    conn,
 	})
@@ -321,9 +322,17 @@ func (w *udpWorker) callback(b *buf.Buffer, source net.Destination, originalDest
 				outbounds[0].Target = originalDest
 			}
 			ctx = session.ContextWithOutbounds(ctx, outbounds)
+			// The address a packet was sent to is only known with its original
+			// destination, so without it localIP rules don't match packets on
+			// wildcard listeners.
+			local := net.UDPDestination(w.address, w.port)
+			if originalDest.IsValid() {
+				local = originalDest
+			}
 			ctx = session.ContextWithInbound(ctx, &session.Inbound{
 				Source:  source,
 				Gateway: net.UDPDestination(w.address, w.port),
+				Local:   local,
 				Tag:     w.tag,
 			})
 			content := new(session.Content)
@@ -8,6 +8,7 @@ import (
 	"math/big"
 	gonet "net"
 	"os"
+	"sync/atomic"
 
 	"github.com/GFW-knocker/Xray-core/app/proxyman"
 	"github.com/GFW-knocker/Xray-core/common"
@@ -19,6 +20,7 @@ import (
 	"github.com/GFW-knocker/Xray-core/common/serial"
 	"github.com/GFW-knocker/Xray-core/common/session"
 	"github.com/GFW-knocker/Xray-core/core"
+	"github.com/GFW-knocker/Xray-core/features/extension"
 	"github.com/GFW-knocker/Xray-core/features/outbound"
 	"github.com/GFW-knocker/Xray-core/features/policy"
 	"github.com/GFW-knocker/Xray-core/features/stats"
@@ -56,6 +58,19 @@ func getStatCounter(v *core.Instance, tag string) (stats.Counter, stats.Counter)
 	return uplinkCounter, downlinkCounter
 }
 
+func getConnectionCounter(v *core.Instance, tag string) stats.Counter {
+	policy := v.GetFeature(policy.ManagerType()).(policy.Manager)
+	if len(tag) > 0 && policy.ForSystem().Stats.OutboundConnections {
+		statsManager := v.GetFeature(stats.ManagerType()).(stats.Manager)
+		name := "outbound>>>" + tag + ">>>connections"
+		c, _ := stats.GetOrRegisterCounter(statsManager, name)
+		if c != nil {
+			return c
+		}
+	}
+	return nil
+}
+
 // Handler implements outbound.Handler.
 type Handler struct {
 	tag             string
@@ -69,6 +84,11 @@ type Handler struct {
 	udp443          string
 	uplinkCounter   stats.Counter
 	downlinkCounter stats.Counter
+	// connectionCounter counts active connections, except those carried by
+	// mux, which outlive Dispatch.
+	connectionCounter stats.Counter
+	// observer learns the outcome of connections not carried by mux.
+	observer extension.OutcomeObserver
 }
 
 // NewHandler creates a new Handler based on the given configuration.
@@ -76,10 +96,18 @@ func NewHandler(ctx context.Context, config *core.OutboundHandlerConfig) (outbou
 	v := core.MustFromContext(ctx)
 	uplinkCounter, downlinkCounter := getStatCounter(v, config.Tag)
 	h := &Handler{
-		tag:             config.Tag,
-		outboundManager: v.GetFeature(outbound.ManagerType()).(outbound.Manager),
-		uplinkCounter:   uplinkCounter,
-		downlinkCounter: downlinkCounter,
+		tag:               config.Tag,
+		outboundManager:   v.GetFeature(outbound.ManagerType()).(outbound.Manager),
+		uplinkCounter:     uplinkCounter,
+		downlinkCounter:   downlinkCounter,
+		connectionCounter: getConnectionCounter(v, config.Tag),
+	}
+	if len(config.Tag) > 0 {
+		core.OptionalFeatures(ctx, func(o extension.Observatory) {
+			if observer, ok := o.(extension.OutcomeObserver); ok {
+				h.observer = observer
+			}
+		})
 	}
 
 	if config.SenderSettings != nil {
@@ -212,7 +240,26 @@ func (h *Handler) Dispatch(ctx context.Context, link *transport.Link) {
 		}
 	}
 out:
+	if h.connectionCounter != nil {
+		h.connectionCounter.Add(1)
+		defer h.connectionCounter.Add(-1)
+	}
+	var response *responseWriter
+	if h.observer != nil {
+		if w, ok := link.Writer.(innerWrapper); ok {
+			w.WrapInner(func(inner buf.Writer) buf.Writer {
+				response = &responseWriter{Writer: inner}
+				return response
+			})
+		} else {
+			response = &responseWriter{Writer: link.Writer}
+			link.Writer = response
+		}
+	}
 	err := h.proxy.Process(ctx, link, h)
+	if response != nil {
+		h.reportOutcome(ctx, ob, response.responded.Load(), err)
+	}
 	if err != nil {
 		if goerrors.Is(err, io.EOF) || goerrors.Is(err, io.ErrClosedPipe) || goerrors.Is(err, context.Canceled) {
 			err = nil
@@ -230,6 +277,56 @@ out:
 	common.Interrupt(link.Reader)
 }
 
+// reportOutcome tells the observer whether the connection through the
+// outbound worked. A response is enough for success, while connections
+// cancelled or closed early by the client say nothing about the outbound.
+func (h *Handler) reportOutcome(ctx context.Context, ob *session.Outbound, responded bool, err error) {
+	switch {
+	case responded:
+		h.observer.ReportOutcome(h.tag, nil)
+	case goerrors.Is(err, context.Canceled) || goerrors.Is(err, io.EOF) || goerrors.Is(err, io.ErrClosedPipe):
+	case err != nil:
+		h.observer.ReportOutcome(h.tag, err)
+	case ob.Target.Network == net.Network_TCP && !spliced(ctx, ob):
+		h.observer.ReportOutcome(h.tag, errors.New("connection to ", ob.Target, " closed without response"))
+	}
+}
+
+// spliced returns whether the response may have been spliced to the inbound
+// connection, bypassing the link.
+func spliced(ctx context.Context, ob *session.Outbound) bool {
+	inbound := session.InboundFromContext(ctx)
+	return inbound != nil && inbound.CanSpliceCopy == 1 && ob.CanSpliceCopy == 1
+}
+
+// innerWrapper is a writer of a link which must stay the outermost one, such
+// as the stats writer of the dispatcher, but lets the writer it wraps be
+// wrapped in turn.
+type innerWrapper interface {
+	WrapInner(wrap func(buf.Writer) buf.Writer)
+}
+
+// responseWriter records whether any response passed through it.
+type responseWriter struct {
+	buf.Writer
+	responded atomic.Bool
+}
+
+func (w *responseWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	if !mb.IsEmpty() {
+		w.responded.Store(true)
+	}
+	return w.Writer.WriteMultiBuffer(mb)
+}
+
+func (w *responseWriter) Close() error {
+	return common.Close(w.Writer)
+}
+
+func (w *responseWriter) Interrupt() {
+	common.Interrupt(w.Writer)
+}
+
 // Address implements internet.Dialer.
 func (h *Handler) Address() net.Address {
 	if h.senderSettings == nil || h.senderSettings.Via == nil {
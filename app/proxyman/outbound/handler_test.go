@@ -12,13 +12,21 @@ import (
 	"github.com/GFW-knocker/Xray-core/app/proxyman"
 	. "github.com/GFW-knocker/Xray-core/app/proxyman/outbound"
 	"github.com/GFW-knocker/Xray-core/app/stats"
+	"github.com/GFW-knocker/Xray-core/common"
+	"github.com/GFW-knocker/Xray-core/common/buf"
 	"github.com/GFW-knocker/Xray-core/common/net"
 	"github.com/GFW-knocker/Xray-core/common/serial"
 	"github.com/GFW-knocker/Xray-core/common/session"
 	core "github.com/GFW-knocker/Xray-core/core"
+	"github.com/GFW-knocker/Xray-core/features/extension"
 	"github.com/GFW-knocker/Xray-core/features/outbound"
 	"github.com/GFW-knocker/Xray-core/proxy/freedom"
+	"github.com/GFW-knocker/Xray-core/testing/servers/tcp"
+	"github.com/GFW-knocker/Xray-core/transport"
 	"github.com/GFW-knocker/Xray-core/transport/internet/stat"
+	_ "github.com/GFW-knocker/Xray-core/transport/internet/tcp"
+	"github.com/GFW-knocker/Xray-core/transport/pipe"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestInterfaces(t *testing.T) {
@@ -87,6 +95,76 @@ func TestOutboundWithStatCounter(t *testing.T) {
 	}
 }
 
+type outcomeRecorder struct {
+	sync.Mutex
+	outcomes []error
+}
+
+func (r *outcomeRecorder) Type() interface{} {
+	return extension.ObservatoryType()
+}
+
+func (r *outcomeRecorder) Start() error { return nil }
+
+func (r *outcomeRecorder) Close() error { return nil }
+
+func (r *outcomeRecorder) GetObservation(ctx context.Context) (proto.Message, error) {
+	return nil, nil
+}
+
+func (r *outcomeRecorder) ReportOutcome(tag string, err error) {
+	r.Lock()
+	defer r.Unlock()
+	r.outcomes = append(r.outcomes, err)
+}
+
+func TestOutboundReportsOutcome(t *testing.T) {
+	tcpServer := tcp.Server{
+		MsgProcessor: func(msg []byte) []byte { return msg },
+	}
+	dest, err := tcpServer.Start()
+	common.Must(err)
+	defer tcpServer.Close()
+
+	v, _ := core.New(&core.Config{})
+	v.AddFeature((outbound.Manager)(new(Manager)))
+	recorder := new(outcomeRecorder)
+	common.Must(v.AddFeature(recorder))
+	ctx := context.WithValue(context.Background(), xrayKey, v)
+	h, err := NewHandler(ctx, &core.OutboundHandlerConfig{
+		Tag:           "tag",
+		ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+	})
+	common.Must(err)
+
+	dispatch := func(target net.Destination) {
+		uplinkReader, uplinkWriter := pipe.New()
+		downlinkReader, downlinkWriter := pipe.New()
+		ctx := session.ContextWithOutbounds(ctx, []*session.Outbound{{Target: target}})
+		common.Must(uplinkWriter.WriteMultiBuffer(buf.MultiBuffer{buf.FromBytes([]byte("hello"))}))
+		go func() {
+			downlinkReader.ReadMultiBuffer()
+			uplinkWriter.Close()
+		}()
+		h.Dispatch(ctx, &transport.Link{Reader: uplinkReader, Writer: downlinkWriter})
+	}
+
+	dispatch(dest)
+	dispatch(net.TCPDestination(net.LocalHostIP, tcp.PickPort()))
+
+	recorder.Lock()
+	defer recorder.Unlock()
+	if len(recorder.outcomes) != 2 {
+		t.Fatal("expect 2 outcomes, but got ", recorder.outcomes)
+	}
+	if recorder.outcomes[0] != nil {
+		t.Error("expect success, but got ", recorder.outcomes[0])
+	}
+	if recorder.outcomes[1] == nil {
+		t.Error("expect failure to connect to a closed port")
+	}
+}
+
 func TestTagsCache(t *testing.T) {
 
 	test_duration := 10 * time.Second
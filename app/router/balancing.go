@@ -10,6 +10,7 @@ import (
 	"github.com/GFW-knocker/Xray-core/core"
 	"github.com/GFW-knocker/Xray-core/features/extension"
 	"github.com/GFW-knocker/Xray-core/features/outbound"
+	"github.com/GFW-knocker/Xray-core/features/routing"
 )
 
 type BalancingStrategy interface {
@@ -20,6 +21,44 @@ type BalancingPrincipleTarget interface {
 	GetPrincipleTarget([]string) []string
 }
 
+// BalancingContextStrategy is implemented by strategies that pick outbounds
+// depending on the connection being routed.
+type BalancingContextStrategy interface {
+	PickOutboundForContext(routing.Context, []string) string
+}
+
+// filterAlive removes the candidates the observatory reports as dead. It
+// returns candidates as is if there is no observatory or no report.
+func filterAlive(ctx context.Context, o extension.Observatory, candidates []string) []string {
+	if o == nil {
+		return candidates
+	}
+	observeReport, err := o.GetObservation(ctx)
+	if err != nil {
+		return candidates
+	}
+	result, ok := observeReport.(*observatory.ObservationResult)
+	if !ok {
+		return candidates
+	}
+	statusMap := make(map[string]*observatory.OutboundStatus)
+	for _, outboundStatus := range result.Status {
+		statusMap[outboundStatus.OutboundTag] = outboundStatus
+	}
+	aliveTags := make([]string, 0)
+	for _, candidate := range candidates {
+		if outboundStatus, found := statusMap[candidate]; found {
+			if outboundStatus.Alive {
+				aliveTags = append(aliveTags, candidate)
+			}
+		} else {
+			// unfound candidate is considered alive
+			aliveTags = append(aliveTags, candidate)
+		}
+	}
+	return aliveTags
+}
+
 type RoundRobinStrategy struct {
 	FallbackTag string
 
@@ -44,30 +83,7 @@ func (s *RoundRobinStrategy) GetPrincipleTarget(strings []string) []string {
 }
 
 func (s *RoundRobinStrategy) PickOutbound(tags []string) string {
-	if s.observatory != nil {
-		observeReport, err := s.observatory.GetObservation(s.ctx)
-		if err == nil {
-			aliveTags := make([]string, 0)
-			if result, ok := observeReport.(*observatory.ObservationResult); ok {
-				status := result.Status
-				statusMap := make(map[string]*observatory.OutboundStatus)
-				for _, outboundStatus := range status {
-					statusMap[outboundStatus.OutboundTag] = outboundStatus
-				}
-				for _, candidate := range tags {
-					if outboundStatus, found := statusMap[candidate]; found {
-						if outboundStatus.Alive {
-							aliveTags = append(aliveTags, candidate)
-						}
-					} else {
-						// unfound candidate is considered alive
-						aliveTags = append(aliveTags, candidate)
-					}
-				}
-				tags = aliveTags
-			}
-		}
-	}
+	tags = filterAlive(s.ctx, s.observatory, tags)
 
 	n := len(tags)
 	if n == 0 {
@@ -91,8 +107,9 @@ type Balancer struct {
 	override override
 }
 
-// PickOutbound picks the tag of a outbound
-func (b *Balancer) PickOutbound() (string, error) {
+// PickOutbound picks the tag of a outbound for the connection of ctx, which
+// may be nil.
+func (b *Balancer) PickOutbound(ctx routing.Context) (string, error) {
 	candidates, err := b.SelectOutbounds()
 	if err != nil {
 		if b.fallbackTag != "" {
@@ -104,6 +121,8 @@ func (b *Balancer) PickOutbound() (string, error) {
 	var tag string
 	if o := b.override.Get(); o != "" {
 		tag = o
+	} else if s, ok := b.strategy.(BalancingContextStrategy); ok && ctx != nil {
+		tag = s.PickOutboundForContext(ctx, candidates)
 	} else {
 		tag = b.strategy.PickOutbound(candidates)
 	}
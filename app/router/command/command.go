@@ -10,6 +10,7 @@ import (
 	"github.com/GFW-knocker/Xray-core/features/routing"
 	"github.com/GFW-knocker/Xray-core/features/stats"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
 )
 
 // routingServer is an implementation of RoutingService.
@@ -79,6 +80,9 @@ func (s *routingServer) TestRoute(ctx context.Context, request *TestRouteRequest
 	if request.RoutingContext == nil {
 		return nil, errors.New("Invalid routing request.")
 	}
+	if request.Trace {
+		return s.traceRoute(request)
+	}
 	route, err := s.router.PickRoute(AsRoutingContext(request.RoutingContext))
 	if err != nil {
 		return nil, err
@@ -90,6 +94,33 @@ func (s *routingServer) TestRoute(ctx context.Context, request *TestRouteRequest
 	return AsProtobufMessage(request.FieldSelectors)(route), nil
 }
 
+func (s *routingServer) traceRoute(request *TestRouteRequest) (*RoutingContext, error) {
+	tracer, ok := s.router.(routing.RouteTracer)
+	if !ok {
+		return nil, errors.New("unsupported router implementation")
+	}
+	route, traces, err := tracer.TraceRoute(AsRoutingContext(request.RoutingContext))
+	var result *RoutingContext
+	if err != nil {
+		if len(traces) == 0 {
+			return nil, err
+		}
+		result = proto.Clone(request.RoutingContext).(*RoutingContext)
+	} else {
+		result = AsProtobufMessage(request.FieldSelectors)(route)
+	}
+	for _, trace := range traces {
+		result.Trace = append(result.Trace, &RuleTrace{
+			Index:            int32(trace.Index),
+			RuleTag:          trace.RuleTag,
+			Matched:          trace.Matched,
+			FailedConditions: trace.FailedConditions,
+			Retry:            trace.Retry,
+		})
+	}
+	return result, nil
+}
+
 func (s *routingServer) SubscribeRoutingStats(request *SubscribeRoutingStatsRequest, stream RoutingService_SubscribeRoutingStatsServer) error {
 	if s.routingStats == nil {
 		return errors.New("Routing statistics not enabled.")
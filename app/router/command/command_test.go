@@ -412,12 +412,54 @@ func TestServiceTestRoute(t *testing.T) {
 			return nil
 		}
 
+		// Test TestRoute with trace
+		testTrace := func() error {
+			route, err := client.TestRoute(context.Background(), &TestRouteRequest{
+				RoutingContext: &RoutingContext{TargetPort: 8080},
+				FieldSelectors: []string{"outbound"},
+				Trace:          true,
+			})
+			if err != nil {
+				return err
+			}
+			expected := &RoutingContext{
+				OutboundTag: "out",
+				Trace: []*RuleTrace{
+					{Index: 0, FailedConditions: []string{"inboundTag"}},
+					{Index: 1, FailedConditions: []string{"protocol"}},
+					{Index: 2, Matched: true},
+				},
+			}
+			if r := cmp.Diff(route, expected, cmpopts.IgnoreUnexported(RoutingContext{}, RuleTrace{})); r != "" {
+				t.Error(r)
+			}
+
+			// No rule matches, but the trace is still returned.
+			route, err = client.TestRoute(context.Background(), &TestRouteRequest{
+				RoutingContext: &RoutingContext{TargetPort: 53},
+				Trace:          true,
+			})
+			if err != nil {
+				return err
+			}
+			if route.OutboundTag != "" || len(route.Trace) != 8 {
+				t.Error("unexpected trace without match: ", route)
+			}
+			if failed := route.Trace[7].FailedConditions; len(failed) != 1 || failed[0] != "network" {
+				t.Error("unexpected failed conditions: ", failed)
+			}
+			return nil
+		}
+
 		if err := testSimple(); err != nil {
 			errCh <- err
 		}
 		if err := testOptions(); err != nil {
 			errCh <- err
 		}
+		if err := testTrace(); err != nil {
+			errCh <- err
+		}
 		errCh <- nil // Client passed all tests successfully
 	}()
 
@@ -20,6 +20,14 @@ func (c routingContext) GetSourcePort() net.Port {
 	return net.Port(c.RoutingContext.GetSourcePort())
 }
 
+func (c routingContext) GetLocalIPs() []net.IP {
+	return mapBytesToIPs(c.RoutingContext.GetLocalIPs())
+}
+
+func (c routingContext) GetLocalPort() net.Port {
+	return net.Port(c.RoutingContext.GetLocalPort())
+}
+
 func (c routingContext) GetTargetIPs() []net.IP {
 	return mapBytesToIPs(c.RoutingContext.GetTargetIPs())
 }
@@ -28,10 +36,22 @@ func (c routingContext) GetTargetPort() net.Port {
 	return net.Port(c.RoutingContext.GetTargetPort())
 }
 
+func (c routingContext) GetTLSVersion() uint16 {
+	return uint16(c.RoutingContext.GetTLSVersion())
+}
+
 func (c routingContext) GetRuleTag() string {
 	return ""
 }
 
+func (c routingContext) GetFragmentProfile() string {
+	return ""
+}
+
+func (c routingContext) GetNoiseProfile() string {
+	return ""
+}
+
 // GetSkipDNSResolve is a mock implementation here to match the interface,
 // SkipDNSResolve is set from dns module, no use if coming from a protobuf object?
 // TODO: please confirm @Vigilans
@@ -54,12 +74,18 @@ var fieldMap = map[string]func(*RoutingContext, routing.Route){
 	"network":        func(s *RoutingContext, r routing.Route) { s.Network = r.GetNetwork() },
 	"ip_source":      func(s *RoutingContext, r routing.Route) { s.SourceIPs = mapIPsToBytes(r.GetSourceIPs()) },
 	"ip_target":      func(s *RoutingContext, r routing.Route) { s.TargetIPs = mapIPsToBytes(r.GetTargetIPs()) },
+	"ip_local":       func(s *RoutingContext, r routing.Route) { s.LocalIPs = mapIPsToBytes(r.GetLocalIPs()) },
 	"port_source":    func(s *RoutingContext, r routing.Route) { s.SourcePort = uint32(r.GetSourcePort()) },
 	"port_target":    func(s *RoutingContext, r routing.Route) { s.TargetPort = uint32(r.GetTargetPort()) },
+	"port_local":     func(s *RoutingContext, r routing.Route) { s.LocalPort = uint32(r.GetLocalPort()) },
 	"domain":         func(s *RoutingContext, r routing.Route) { s.TargetDomain = r.GetTargetDomain() },
 	"protocol":       func(s *RoutingContext, r routing.Route) { s.Protocol = r.GetProtocol() },
 	"user":           func(s *RoutingContext, r routing.Route) { s.User = r.GetUser() },
 	"attributes":     func(s *RoutingContext, r routing.Route) { s.Attributes = r.GetAttributes() },
+	"tls_alpn":       func(s *RoutingContext, r routing.Route) { s.ALPN = r.GetALPN() },
+	"tls_version":    func(s *RoutingContext, r routing.Route) { s.TLSVersion = uint32(r.GetTLSVersion()) },
+	"tls_ech":        func(s *RoutingContext, r routing.Route) { s.ECH = r.GetECH() },
+	"tls_sni":        func(s *RoutingContext, r routing.Route) { s.SNI = r.GetSNI() },
 	"outbound_group": func(s *RoutingContext, r routing.Route) { s.OutboundGroupTags = r.GetOutboundGroupTags() },
 	"outbound":       func(s *RoutingContext, r routing.Route) { s.OutboundTag = r.GetOutboundTag() },
 }
@@ -3,6 +3,7 @@ package router
 import (
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/GFW-knocker/Xray-core/common/errors"
 	"github.com/GFW-knocker/Xray-core/common/net"
@@ -114,6 +115,7 @@ func (m *DomainMatcher) Apply(ctx routing.Context) bool {
 type MultiGeoIPMatcher struct {
 	matchers []*GeoIPMatcher
 	onSource bool
+	onLocal  bool
 }
 
 func NewMultiGeoIPMatcher(geoips []*GeoIP, onSource bool) (*MultiGeoIPMatcher, error) {
@@ -134,10 +136,23 @@ func NewMultiGeoIPMatcher(geoips []*GeoIP, onSource bool) (*MultiGeoIPMatcher, e
 	return matcher, nil
 }
 
+// NewLocalGeoIPMatcher creates a MultiGeoIPMatcher that matches the local IP
+// the connection was accepted on.
+func NewLocalGeoIPMatcher(geoips []*GeoIP) (*MultiGeoIPMatcher, error) {
+	matcher, err := NewMultiGeoIPMatcher(geoips, false)
+	if err != nil {
+		return nil, err
+	}
+	matcher.onLocal = true
+	return matcher, nil
+}
+
 // Apply implements Condition.
 func (m *MultiGeoIPMatcher) Apply(ctx routing.Context) bool {
 	var ips []net.IP
-	if m.onSource {
+	if m.onLocal {
+		ips = ctx.GetLocalIPs()
+	} else if m.onSource {
 		ips = ctx.GetSourceIPs()
 	} else {
 		ips = ctx.GetTargetIPs()
@@ -155,6 +170,7 @@ func (m *MultiGeoIPMatcher) Apply(ctx routing.Context) bool {
 type PortMatcher struct {
 	port     net.MemoryPortList
 	onSource bool
+	onLocal  bool
 }
 
 // NewPortMatcher create a new port matcher that can match source or destination port
@@ -165,8 +181,20 @@ func NewPortMatcher(list *net.PortList, onSource bool) *PortMatcher {
 	}
 }
 
+// NewLocalPortMatcher create a new port matcher that matches the local port
+// the connection was accepted on
+func NewLocalPortMatcher(list *net.PortList) *PortMatcher {
+	return &PortMatcher{
+		port:    net.PortListFromProto(list),
+		onLocal: true,
+	}
+}
+
 // Apply implements Condition.
 func (v *PortMatcher) Apply(ctx routing.Context) bool {
+	if v.onLocal {
+		return v.port.Contains(ctx.GetLocalPort())
+	}
 	if v.onSource {
 		return v.port.Contains(ctx.GetSourcePort())
 	} else {
@@ -298,6 +326,92 @@ func (m *ProtocolMatcher) Apply(ctx routing.Context) bool {
 	return false
 }
 
+// ALPNMatcher matches any of the application protocols offered in the sniffed
+// client hello.
+type ALPNMatcher struct {
+	alpn map[string]bool
+}
+
+func NewALPNMatcher(alpn []string) *ALPNMatcher {
+	m := &ALPNMatcher{
+		alpn: make(map[string]bool, len(alpn)),
+	}
+	for _, p := range alpn {
+		m.alpn[p] = true
+	}
+	return m
+}
+
+// Apply implements Condition.
+func (m *ALPNMatcher) Apply(ctx routing.Context) bool {
+	for _, p := range ctx.GetALPN() {
+		if m.alpn[p] {
+			return true
+		}
+	}
+	return false
+}
+
+// TLSVersionMatcher matches the highest TLS version offered in the sniffed
+// client hello.
+type TLSVersionMatcher struct {
+	versions []uint32
+}
+
+func NewTLSVersionMatcher(versions []uint32) *TLSVersionMatcher {
+	return &TLSVersionMatcher{
+		versions: versions,
+	}
+}
+
+// Apply implements Condition.
+func (m *TLSVersionMatcher) Apply(ctx routing.Context) bool {
+	version := ctx.GetTLSVersion()
+	if version == 0 {
+		return false
+	}
+	for _, v := range m.versions {
+		if uint32(version) == v {
+			return true
+		}
+	}
+	return false
+}
+
+// ECHMatcher matches whether the sniffed client hello carries an encrypted
+// client hello. Connections without a sniffed client hello never match.
+type ECHMatcher struct {
+	present bool
+}
+
+func NewECHMatcher(present bool) *ECHMatcher {
+	return &ECHMatcher{
+		present: present,
+	}
+}
+
+// Apply implements Condition.
+func (m *ECHMatcher) Apply(ctx routing.Context) bool {
+	return ctx.GetTLSVersion() != 0 && ctx.GetECH() == m.present
+}
+
+// SNIMatcher matches whether the sniffed client hello carries a server name.
+// Connections without a sniffed client hello never match.
+type SNIMatcher struct {
+	present bool
+}
+
+func NewSNIMatcher(present bool) *SNIMatcher {
+	return &SNIMatcher{
+		present: present,
+	}
+}
+
+// Apply implements Condition.
+func (m *SNIMatcher) Apply(ctx routing.Context) bool {
+	return ctx.GetTLSVersion() != 0 && ctx.GetSNI() == m.present
+}
+
 type AttributeMatcher struct {
 	configuredKeys map[string]*regexp.Regexp
 }
@@ -325,3 +439,85 @@ func (m *AttributeMatcher) Apply(ctx routing.Context) bool {
 	}
 	return m.Match(attributes)
 }
+
+// ScheduleMatcher matches when the current time is in one of the windows of
+// a Schedule.
+type ScheduleMatcher struct {
+	location *time.Location
+	windows  []*scheduleWindow
+	now      func() time.Time
+}
+
+type scheduleWindow struct {
+	weekdays   [7]bool
+	start, end int
+}
+
+// NewScheduleMatcher creates a ScheduleMatcher that reads the current time
+// from now, which defaults to time.Now.
+func NewScheduleMatcher(schedule *Schedule, now func() time.Time) (*ScheduleMatcher, error) {
+	location := time.Local
+	if schedule.Timezone != "" {
+		var err error
+		if location, err = time.LoadLocation(schedule.Timezone); err != nil {
+			return nil, errors.New("invalid timezone ", schedule.Timezone).Base(err)
+		}
+	}
+	if len(schedule.Window) == 0 {
+		return nil, errors.New("empty schedule")
+	}
+	if now == nil {
+		now = time.Now
+	}
+
+	m := &ScheduleMatcher{
+		location: location,
+		now:      now,
+	}
+	for _, w := range schedule.Window {
+		if w.Start >= 24*60 || w.End > 24*60 {
+			return nil, errors.New("invalid schedule window ", w.Start, "-", w.End)
+		}
+		window := &scheduleWindow{
+			start: int(w.Start),
+			end:   int(w.End),
+		}
+		if len(w.Weekday) == 0 {
+			window.weekdays = [7]bool{true, true, true, true, true, true, true}
+		}
+		for _, day := range w.Weekday {
+			if day > 6 {
+				return nil, errors.New("invalid weekday ", day)
+			}
+			window.weekdays[day] = true
+		}
+		m.windows = append(m.windows, window)
+	}
+	return m, nil
+}
+
+// Match returns true if t is in one of the windows.
+func (m *ScheduleMatcher) Match(t time.Time) bool {
+	t = t.In(m.location)
+	day := t.Weekday()
+	yesterday := (day + 6) % 7
+	minute := t.Hour()*60 + t.Minute()
+	for _, w := range m.windows {
+		if w.start < w.end {
+			if w.weekdays[day] && minute >= w.start && minute < w.end {
+				return true
+			}
+			continue
+		}
+		// The window spans midnight, so it may have started yesterday.
+		if (w.weekdays[day] && minute >= w.start) || (w.weekdays[yesterday] && minute < w.end) {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply implements Condition.
+func (m *ScheduleMatcher) Apply(ctx routing.Context) bool {
+	return m.Match(m.now())
+}
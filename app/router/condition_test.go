@@ -3,6 +3,7 @@ package router_test
 import (
 	"strconv"
 	"testing"
+	"time"
 
 	. "github.com/GFW-knocker/Xray-core/app/router"
 	"github.com/GFW-knocker/Xray-core/common"
@@ -187,6 +188,80 @@ func TestRoutingRule(t *testing.T) {
 				},
 			},
 		},
+		{
+			rule: &RoutingRule{
+				Alpn: []string{"h2"},
+			},
+			test: []ruleTest{
+				{
+					input:  withContent(&session.Content{Protocol: "tls", TLS: &session.TLSInfo{ALPN: []string{"h2", "http/1.1"}}}),
+					output: true,
+				},
+				{
+					input:  withContent(&session.Content{Protocol: "tls", TLS: &session.TLSInfo{ALPN: []string{"http/1.1"}}}),
+					output: false,
+				},
+				{
+					input:  withContent(&session.Content{Protocol: "http"}),
+					output: false,
+				},
+			},
+		},
+		{
+			rule: &RoutingRule{
+				Protocol:   []string{"quic"},
+				TlsVersion: []uint32{0x0304},
+				Ech:        RoutingRule_ECHPresent,
+			},
+			test: []ruleTest{
+				{
+					input:  withContent(&session.Content{Protocol: "quic", TLS: &session.TLSInfo{Version: 0x0304, ECH: true}}),
+					output: true,
+				},
+				{
+					input:  withContent(&session.Content{Protocol: "quic", TLS: &session.TLSInfo{Version: 0x0304}}),
+					output: false,
+				},
+				{
+					input:  withContent(&session.Content{Protocol: "quic", TLS: &session.TLSInfo{Version: 0x0303, ECH: true}}),
+					output: false,
+				},
+			},
+		},
+		{
+			rule: &RoutingRule{
+				Sni: RoutingRule_SNIAbsent,
+			},
+			test: []ruleTest{
+				{
+					input:  withContent(&session.Content{Protocol: "tls", TLS: &session.TLSInfo{Version: 0x0304}}),
+					output: true,
+				},
+				{
+					input:  withContent(&session.Content{Protocol: "tls", TLS: &session.TLSInfo{Version: 0x0304, SNI: true}}),
+					output: false,
+				},
+				{
+					input:  withContent(&session.Content{Protocol: "http"}),
+					output: false,
+				},
+			},
+		},
+		{
+			rule: &RoutingRule{
+				Ech: RoutingRule_ECHAbsent,
+			},
+			test: []ruleTest{
+				{
+					input:  withContent(&session.Content{Protocol: "tls", TLS: &session.TLSInfo{Version: 0x0304}}),
+					output: true,
+				},
+				{
+					input:  withContent(&session.Content{Protocol: "http"}),
+					output: false,
+				},
+			},
+		},
 		{
 			rule: &RoutingRule{
 				InboundTag: []string{"test", "test1"},
@@ -258,6 +333,47 @@ func TestRoutingRule(t *testing.T) {
 				},
 			},
 		},
+		{
+			rule: &RoutingRule{
+				LocalGeoip: []*GeoIP{
+					{
+						Cidr: []*CIDR{
+							{
+								Ip:     []byte{10, 0, 0, 0},
+								Prefix: 8,
+							},
+						},
+					},
+				},
+				LocalPortList: &net.PortList{
+					Range: []*net.PortRange{
+						{From: 8000, To: 8099},
+					},
+				},
+			},
+			test: []ruleTest{
+				{
+					input:  withInbound(&session.Inbound{Local: net.TCPDestination(net.ParseAddress("10.0.0.1"), 8080)}),
+					output: true,
+				},
+				{
+					input:  withInbound(&session.Inbound{Local: net.TCPDestination(net.ParseAddress("10.0.0.1"), 8100)}),
+					output: false,
+				},
+				{
+					input:  withInbound(&session.Inbound{Local: net.TCPDestination(net.ParseAddress("192.168.0.1"), 8080)}),
+					output: false,
+				},
+				{
+					input:  withInbound(&session.Inbound{Local: net.TCPDestination(net.AnyIP, 8080)}),
+					output: false,
+				},
+				{
+					input:  withInbound(&session.Inbound{Source: net.TCPDestination(net.ParseAddress("10.0.0.1"), 8080)}),
+					output: false,
+				},
+			},
+		},
 		{
 			rule: &RoutingRule{
 				Protocol: []string{"http"},
@@ -300,6 +416,44 @@ func TestRoutingRule(t *testing.T) {
 	}
 }
 
+func TestScheduleMatcher(t *testing.T) {
+	var now time.Time
+	matcher, err := NewScheduleMatcher(&Schedule{
+		Timezone: "UTC",
+		Window: []*ScheduleWindow{
+			// Mon-Fri 09:00-17:00
+			{Weekday: []uint32{1, 2, 3, 4, 5}, Start: 9 * 60, End: 17 * 60},
+			// Sat 22:00-02:00
+			{Weekday: []uint32{6}, Start: 22 * 60, End: 2 * 60},
+		},
+	}, func() time.Time { return now })
+	common.Must(err)
+
+	cases := []struct {
+		time   string
+		output bool
+	}{
+		{"2026-10-12T09:00:00Z", true},  // Monday
+		{"2026-10-12T16:59:59Z", true},  // Monday
+		{"2026-10-12T17:00:00Z", false}, // Monday
+		{"2026-10-12T08:59:00Z", false}, // Monday
+		{"2026-10-12T12:00:00+03:00", true},
+		{"2026-10-12T20:00:00+03:00", false},
+		{"2026-10-17T12:00:00Z", false}, // Saturday
+		{"2026-10-17T23:30:00Z", true},  // Saturday
+		{"2026-10-18T01:59:00Z", true},  // Sunday
+		{"2026-10-18T02:00:00Z", false}, // Sunday
+		{"2026-10-18T23:30:00Z", false}, // Sunday
+	}
+	for _, test := range cases {
+		now, err = time.Parse(time.RFC3339, test.time)
+		common.Must(err)
+		if actual := matcher.Apply(withBackground()); actual != test.output {
+			t.Error("at ", test.time, " expect ", test.output, " but got ", actual)
+		}
+	}
+}
+
 func loadGeoSite(country string) ([]*Domain, error) {
 	path, err := getAssetPath("geosite.dat")
 	if err != nil {
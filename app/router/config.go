@@ -8,18 +8,23 @@ import (
 	"github.com/GFW-knocker/Xray-core/common/errors"
 	"github.com/GFW-knocker/Xray-core/features/outbound"
 	"github.com/GFW-knocker/Xray-core/features/routing"
+	"github.com/GFW-knocker/Xray-core/features/stats"
 )
 
 type Rule struct {
-	Tag       string
-	RuleTag   string
-	Balancer  *Balancer
-	Condition Condition
+	Tag             string
+	RuleTag         string
+	FragmentProfile string
+	NoiseProfile    string
+	Balancer        *Balancer
+	Condition       Condition
+
+	hits stats.Counter
 }
 
-func (r *Rule) GetTag() (string, error) {
+func (r *Rule) GetTag(ctx routing.Context) (string, error) {
 	if r.Balancer != nil {
-		return r.Balancer.PickOutbound()
+		return r.Balancer.PickOutbound(ctx)
 	}
 	return r.Tag, nil
 }
@@ -30,6 +35,12 @@ func (r *Rule) Apply(ctx routing.Context) bool {
 }
 
 func (rr *RoutingRule) BuildCondition() (Condition, error) {
+	return rr.buildCondition(nil)
+}
+
+// buildCondition builds the condition of the rule, looking up the rule sets
+// it references in ruleSets.
+func (rr *RoutingRule) buildCondition(ruleSets map[string]*RuleSetProvider) (Condition, error) {
 	conds := NewConditionChan()
 
 	if len(rr.Domain) > 0 {
@@ -88,10 +99,44 @@ func (rr *RoutingRule) BuildCondition() (Condition, error) {
 		conds.Add(cond)
 	}
 
+	if len(rr.LocalGeoip) > 0 {
+		cond, err := NewLocalGeoIPMatcher(rr.LocalGeoip)
+		if err != nil {
+			return nil, err
+		}
+		conds.Add(cond)
+	}
+
+	if rr.LocalPortList != nil {
+		conds.Add(NewLocalPortMatcher(rr.LocalPortList))
+	}
+
 	if len(rr.Protocol) > 0 {
 		conds.Add(NewProtocolMatcher(rr.Protocol))
 	}
 
+	if len(rr.Alpn) > 0 {
+		conds.Add(NewALPNMatcher(rr.Alpn))
+	}
+
+	if len(rr.TlsVersion) > 0 {
+		conds.Add(NewTLSVersionMatcher(rr.TlsVersion))
+	}
+
+	switch rr.Ech {
+	case RoutingRule_ECHPresent:
+		conds.Add(NewECHMatcher(true))
+	case RoutingRule_ECHAbsent:
+		conds.Add(NewECHMatcher(false))
+	}
+
+	switch rr.Sni {
+	case RoutingRule_SNIPresent:
+		conds.Add(NewSNIMatcher(true))
+	case RoutingRule_SNIAbsent:
+		conds.Add(NewSNIMatcher(false))
+	}
+
 	if len(rr.Attributes) > 0 {
 		configuredKeys := make(map[string]*regexp.Regexp)
 		for key, value := range rr.Attributes {
@@ -100,6 +145,26 @@ func (rr *RoutingRule) BuildCondition() (Condition, error) {
 		conds.Add(&AttributeMatcher{configuredKeys})
 	}
 
+	if rr.Schedule != nil {
+		cond, err := NewScheduleMatcher(rr.Schedule, nil)
+		if err != nil {
+			return nil, errors.New("failed to build schedule condition").Base(err)
+		}
+		conds.Add(cond)
+	}
+
+	if len(rr.RuleSet) > 0 {
+		providers := make([]*RuleSetProvider, 0, len(rr.RuleSet))
+		for _, tag := range rr.RuleSet {
+			provider, found := ruleSets[tag]
+			if !found {
+				return nil, errors.New("rule set ", tag, " not found")
+			}
+			providers = append(providers, provider)
+		}
+		conds.Add(NewRuleSetMatcher(providers))
+	}
+
 	if conds.Len() == 0 {
 		return nil, errors.New("this rule has no effective fields").AtWarning()
 	}
@@ -140,6 +205,36 @@ func (br *BalancingRule) Build(ohm outbound.Manager, dispatcher routing.Dispatch
 			fallbackTag: br.FallbackTag,
 			strategy:    leastLoadStrategy,
 		}, nil
+	case "leastbandwidth":
+		i, err := br.StrategySettings.GetInstance()
+		if err != nil {
+			return nil, err
+		}
+		s, ok := i.(*StrategyLeastBandwidthConfig)
+		if !ok {
+			return nil, errors.New("not a StrategyLeastBandwidthConfig").AtError()
+		}
+		return &Balancer{
+			selectors:   br.OutboundSelector,
+			ohm:         ohm,
+			fallbackTag: br.FallbackTag,
+			strategy:    NewLeastBandwidthStrategy(s, br.FallbackTag),
+		}, nil
+	case "consistenthash":
+		i, err := br.StrategySettings.GetInstance()
+		if err != nil {
+			return nil, err
+		}
+		s, ok := i.(*StrategyConsistentHashConfig)
+		if !ok {
+			return nil, errors.New("not a StrategyConsistentHashConfig").AtError()
+		}
+		return &Balancer{
+			selectors:   br.OutboundSelector,
+			ohm:         ohm,
+			fallbackTag: br.FallbackTag,
+			strategy:    NewConsistentHashStrategy(s, br.FallbackTag),
+		}, nil
 	case "random":
 		fallthrough
 	case "":
@@ -10,8 +10,10 @@ import (
 	"github.com/GFW-knocker/Xray-core/core"
 	"github.com/GFW-knocker/Xray-core/features/dns"
 	"github.com/GFW-knocker/Xray-core/features/outbound"
+	"github.com/GFW-knocker/Xray-core/features/policy"
 	"github.com/GFW-knocker/Xray-core/features/routing"
 	routing_dns "github.com/GFW-knocker/Xray-core/features/routing/dns"
+	"github.com/GFW-knocker/Xray-core/features/stats"
 )
 
 // Router is an implementation of routing.Router.
@@ -19,7 +21,10 @@ type Router struct {
 	domainStrategy Config_DomainStrategy
 	rules          []*Rule
 	balancers      map[string]*Balancer
+	ruleSets       map[string]*RuleSetProvider
 	dns            dns.Client
+	// stats is set if hits of tagged rules are counted.
+	stats stats.Manager
 
 	ctx        context.Context
 	ohm        outbound.Manager
@@ -33,6 +38,8 @@ type Route struct {
 	outboundGroupTags []string
 	outboundTag       string
 	ruleTag           string
+	fragmentProfile   string
+	noiseProfile      string
 }
 
 // Init initializes the Router.
@@ -53,16 +60,24 @@ func (r *Router) Init(ctx context.Context, config *Config, d dns.Client, ohm out
 		r.balancers[rule.Tag] = balancer
 	}
 
+	r.ruleSets = make(map[string]*RuleSetProvider, len(config.RuleSet))
+	if err := r.addRuleSets(config.RuleSet); err != nil {
+		return err
+	}
+
 	r.rules = make([]*Rule, 0, len(config.Rule))
 	for _, rule := range config.Rule {
-		cond, err := rule.BuildCondition()
+		cond, err := rule.buildCondition(r.ruleSets)
 		if err != nil {
 			return err
 		}
 		rr := &Rule{
-			Condition: cond,
-			Tag:       rule.GetTag(),
-			RuleTag:   rule.GetRuleTag(),
+			Condition:       cond,
+			Tag:             rule.GetTag(),
+			RuleTag:         rule.GetRuleTag(),
+			FragmentProfile: rule.GetFragmentProfile(),
+			NoiseProfile:    rule.GetNoiseProfile(),
+			hits:            r.ruleHitsCounter(rule.GetRuleTag()),
 		}
 		btag := rule.GetBalancingTag()
 		if len(btag) > 0 {
@@ -78,17 +93,55 @@ func (r *Router) Init(ctx context.Context, config *Config, d dns.Client, ohm out
 	return nil
 }
 
+// ruleHitsCounter returns the hits counter of the rule with the given tag, or
+// nil if rule hits are not counted.
+func (r *Router) ruleHitsCounter(tag string) stats.Counter {
+	if r.stats == nil || len(tag) == 0 {
+		return nil
+	}
+	c, err := stats.GetOrRegisterCounter(r.stats, "rule>>>"+tag+">>>hits")
+	if err != nil {
+		errors.LogWarningInner(r.ctx, err, "failed to register hits counter of rule ", tag)
+		return nil
+	}
+	return c
+}
+
 // PickRoute implements routing.Router.
 func (r *Router) PickRoute(ctx routing.Context) (routing.Route, error) {
-	rule, ctx, err := r.pickRouteInternal(ctx)
+	rule, ctx, err := r.pickRouteInternal(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
-	tag, err := rule.GetTag()
+	if rule.hits != nil {
+		rule.hits.Add(1)
+	}
+	return r.newRoute(ctx, rule)
+}
+
+// TraceRoute implements routing.RouteTracer.
+func (r *Router) TraceRoute(ctx routing.Context) (routing.Route, []*routing.RuleTrace, error) {
+	var traces []*routing.RuleTrace
+	rule, ctx, err := r.pickRouteInternal(ctx, &traces)
+	if err != nil {
+		return nil, traces, err
+	}
+	route, err := r.newRoute(ctx, rule)
+	return route, traces, err
+}
+
+func (r *Router) newRoute(ctx routing.Context, rule *Rule) (routing.Route, error) {
+	tag, err := rule.GetTag(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return &Route{Context: ctx, outboundTag: tag, ruleTag: rule.RuleTag}, nil
+	return &Route{
+		Context:         ctx,
+		outboundTag:     tag,
+		ruleTag:         rule.RuleTag,
+		fragmentProfile: rule.FragmentProfile,
+		noiseProfile:    rule.NoiseProfile,
+	}, nil
 }
 
 // AddRule implements routing.Router.
@@ -111,6 +164,8 @@ func (r *Router) ReloadRules(config *Config, shouldAppend bool) error {
 	if !shouldAppend {
 		r.balancers = make(map[string]*Balancer, len(config.BalancingRule))
 		r.rules = make([]*Rule, 0, len(config.Rule))
+		r.closeRuleSets()
+		r.ruleSets = make(map[string]*RuleSetProvider, len(config.RuleSet))
 	}
 	for _, rule := range config.BalancingRule {
 		_, found := r.balancers[rule.Tag]
@@ -125,18 +180,25 @@ func (r *Router) ReloadRules(config *Config, shouldAppend bool) error {
 		r.balancers[rule.Tag] = balancer
 	}
 
+	if err := r.addRuleSets(config.RuleSet); err != nil {
+		return err
+	}
+
 	for _, rule := range config.Rule {
 		if r.RuleExists(rule.GetRuleTag()) {
 			return errors.New("duplicate ruleTag ", rule.GetRuleTag())
 		}
-		cond, err := rule.BuildCondition()
+		cond, err := rule.buildCondition(r.ruleSets)
 		if err != nil {
 			return err
 		}
 		rr := &Rule{
-			Condition: cond,
-			Tag:       rule.GetTag(),
-			RuleTag:   rule.GetRuleTag(),
+			Condition:       cond,
+			Tag:             rule.GetTag(),
+			RuleTag:         rule.GetRuleTag(),
+			FragmentProfile: rule.GetFragmentProfile(),
+			NoiseProfile:    rule.GetNoiseProfile(),
+			hits:            r.ruleHitsCounter(rule.GetRuleTag()),
 		}
 		btag := rule.GetBalancingTag()
 		if len(btag) > 0 {
@@ -152,6 +214,26 @@ func (r *Router) ReloadRules(config *Config, shouldAppend bool) error {
 	return nil
 }
 
+func (r *Router) addRuleSets(ruleSets []*RuleSet) error {
+	for _, ruleSet := range ruleSets {
+		if _, found := r.ruleSets[ruleSet.Tag]; found {
+			return errors.New("duplicate rule set tag ", ruleSet.Tag)
+		}
+		provider, err := NewRuleSetProvider(ruleSet)
+		if err != nil {
+			return err
+		}
+		r.ruleSets[ruleSet.Tag] = provider
+	}
+	return nil
+}
+
+func (r *Router) closeRuleSets() {
+	for _, provider := range r.ruleSets {
+		provider.Close()
+	}
+}
+
 func (r *Router) RuleExists(tag string) bool {
 	if tag != "" {
 		for _, rule := range r.rules {
@@ -181,7 +263,10 @@ func (r *Router) RemoveRule(tag string) error {
 	return errors.New("empty tag name!")
 
 }
-func (r *Router) pickRouteInternal(ctx routing.Context) (*Rule, routing.Context, error) {
+
+// pickRouteInternal returns the first matching rule. If traces is not nil,
+// every rule tried is evaluated in full and recorded in it.
+func (r *Router) pickRouteInternal(ctx routing.Context, traces *[]*routing.RuleTrace) (*Rule, routing.Context, error) {
 	// SkipDNSResolve is set from DNS module.
 	// the DOH remote server maybe a domain name,
 	// this prevents cycle resolving dead loop
@@ -191,8 +276,18 @@ func (r *Router) pickRouteInternal(ctx routing.Context) (*Rule, routing.Context,
 		ctx = routing_dns.ContextWithDNSClient(ctx, r.dns)
 	}
 
-	for _, rule := range r.rules {
-		if rule.Apply(ctx) {
+	apply := func(i int, rule *Rule, retry bool) bool {
+		if traces == nil {
+			return rule.Apply(ctx)
+		}
+		trace := traceRule(i, rule, ctx)
+		trace.Retry = retry
+		*traces = append(*traces, trace)
+		return trace.Matched
+	}
+
+	for i, rule := range r.rules {
+		if apply(i, rule, false) {
 			return rule, ctx, nil
 		}
 	}
@@ -204,8 +299,8 @@ func (r *Router) pickRouteInternal(ctx routing.Context) (*Rule, routing.Context,
 	ctx = routing_dns.ContextWithDNSClient(ctx, r.dns)
 
 	// Try applying rules again if we have IPs.
-	for _, rule := range r.rules {
-		if rule.Apply(ctx) {
+	for i, rule := range r.rules {
+		if apply(i, rule, true) {
 			return rule, ctx, nil
 		}
 	}
@@ -220,6 +315,10 @@ func (r *Router) Start() error {
 
 // Close implements common.Closable.
 func (r *Router) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.closeRuleSets()
 	return nil
 }
 
@@ -242,10 +341,23 @@ func (r *Route) GetRuleTag() string {
 	return r.ruleTag
 }
 
+// GetFragmentProfile implements routing.Route.
+func (r *Route) GetFragmentProfile() string {
+	return r.fragmentProfile
+}
+
+// GetNoiseProfile implements routing.Route.
+func (r *Route) GetNoiseProfile() string {
+	return r.noiseProfile
+}
+
 func init() {
 	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
 		r := new(Router)
-		if err := core.RequireFeatures(ctx, func(d dns.Client, ohm outbound.Manager, dispatcher routing.Dispatcher) error {
+		if err := core.RequireFeatures(ctx, func(d dns.Client, ohm outbound.Manager, dispatcher routing.Dispatcher, pm policy.Manager, sm stats.Manager) error {
+			if pm.ForSystem().Stats.RuleHits {
+				r.stats = sm
+			}
 			return r.Init(ctx, config.(*Config), d, ohm, dispatcher)
 		}); err != nil {
 			return nil, err
@@ -5,13 +5,22 @@ import (
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/GFW-knocker/Xray-core/app/dispatcher"
+	"github.com/GFW-knocker/Xray-core/app/policy"
+	"github.com/GFW-knocker/Xray-core/app/proxyman"
+	_ "github.com/GFW-knocker/Xray-core/app/proxyman/outbound"
 	. "github.com/GFW-knocker/Xray-core/app/router"
+	"github.com/GFW-knocker/Xray-core/app/stats"
 	"github.com/GFW-knocker/Xray-core/common"
 	"github.com/GFW-knocker/Xray-core/common/net"
+	"github.com/GFW-knocker/Xray-core/common/serial"
 	"github.com/GFW-knocker/Xray-core/common/session"
+	"github.com/GFW-knocker/Xray-core/core"
 	"github.com/GFW-knocker/Xray-core/features/dns"
 	"github.com/GFW-knocker/Xray-core/features/outbound"
+	"github.com/GFW-knocker/Xray-core/features/routing"
 	routing_session "github.com/GFW-knocker/Xray-core/features/routing/session"
+	feature_stats "github.com/GFW-knocker/Xray-core/features/stats"
 	"github.com/GFW-knocker/Xray-core/testing/mocks"
 )
 
@@ -55,6 +64,128 @@ func TestSimpleRouter(t *testing.T) {
 	}
 }
 
+func TestRouteProfiles(t *testing.T) {
+	config := &Config{
+		Rule: []*RoutingRule{
+			{
+				TargetTag: &RoutingRule_Tag{
+					Tag: "direct",
+				},
+				Domain: []*Domain{
+					{
+						Type:  Domain_Domain,
+						Value: "example.com",
+					},
+				},
+				FragmentProfile: "sni",
+				NoiseProfile:    "quic",
+			},
+			{
+				TargetTag: &RoutingRule_Tag{
+					Tag: "direct",
+				},
+				Networks: []net.Network{net.Network_TCP},
+			},
+		},
+	}
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	mockDNS := mocks.NewDNSClient(mockCtl)
+	mockOhm := mocks.NewOutboundManager(mockCtl)
+	mockHs := mocks.NewOutboundHandlerSelector(mockCtl)
+
+	r := new(Router)
+	common.Must(r.Init(context.TODO(), config, mockDNS, &mockOutboundManager{
+		Manager:         mockOhm,
+		HandlerSelector: mockHs,
+	}, nil))
+
+	for domain, profiles := range map[string][2]string{
+		"www.example.com": {"sni", "quic"},
+		"www.example.org": {"", ""},
+	} {
+		ctx := session.ContextWithOutbounds(context.Background(), []*session.Outbound{{
+			Target: net.TCPDestination(net.DomainAddress(domain), 443),
+		}})
+		route, err := r.PickRoute(routing_session.AsRoutingContext(ctx))
+		common.Must(err)
+		if profile := route.GetFragmentProfile(); profile != profiles[0] {
+			t.Error("unexpected fragment profile of ", domain, ": ", profile)
+		}
+		if profile := route.GetNoiseProfile(); profile != profiles[1] {
+			t.Error("unexpected noise profile of ", domain, ": ", profile)
+		}
+	}
+}
+
+func TestRuleHits(t *testing.T) {
+	config := &core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&stats.Config{}),
+			serial.ToTypedMessage(&policy.Config{
+				System: &policy.SystemPolicy{
+					Stats: &policy.SystemPolicy_Stats{
+						RuleHits: true,
+					},
+				},
+			}),
+			serial.ToTypedMessage(&Config{
+				Rule: []*RoutingRule{
+					{
+						RuleTag: "web",
+						TargetTag: &RoutingRule_Tag{
+							Tag: "direct",
+						},
+						PortList: &net.PortList{
+							Range: []*net.PortRange{{From: 443, To: 443}},
+						},
+					},
+					{
+						TargetTag: &RoutingRule_Tag{
+							Tag: "direct",
+						},
+						Networks: []net.Network{net.Network_TCP},
+					},
+				},
+			}),
+		},
+	}
+
+	v, err := core.New(config)
+	common.Must(err)
+	r := v.GetFeature(routing.RouterType()).(routing.Router)
+
+	for _, port := range []net.Port{443, 443, 80} {
+		ctx := session.ContextWithOutbounds(context.Background(), []*session.Outbound{{
+			Target: net.TCPDestination(net.DomainAddress("example.com"), port),
+		}})
+		_, err := r.PickRoute(routing_session.AsRoutingContext(ctx))
+		common.Must(err)
+	}
+
+	// Tracing does not count.
+	ctx := session.ContextWithOutbounds(context.Background(), []*session.Outbound{{
+		Target: net.TCPDestination(net.DomainAddress("example.com"), 443),
+	}})
+	_, traces, err := r.(routing.RouteTracer).TraceRoute(routing_session.AsRoutingContext(ctx))
+	common.Must(err)
+	if len(traces) != 1 || !traces[0].Matched || traces[0].RuleTag != "web" {
+		t.Error("unexpected traces: ", traces)
+	}
+
+	counter := v.GetFeature(feature_stats.ManagerType()).(feature_stats.Manager).GetCounter("rule>>>web>>>hits")
+	if counter == nil {
+		t.Fatal("hits counter is not registered")
+	}
+	if hits := counter.Value(); hits != 2 {
+		t.Error("expect 2 hits but got ", hits)
+	}
+}
+
 func TestSimpleBalancer(t *testing.T) {
 	config := &Config{
 		Rule: []*RoutingRule{
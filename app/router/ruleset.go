@@ -0,0 +1,387 @@
+package router
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/binary"
+	"io"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/GFW-knocker/Xray-core/common/errors"
+	"github.com/GFW-knocker/Xray-core/common/platform"
+	"github.com/GFW-knocker/Xray-core/common/platform/filesystem"
+	"github.com/GFW-knocker/Xray-core/common/task"
+	"github.com/GFW-knocker/Xray-core/features/routing"
+	"google.golang.org/protobuf/proto"
+)
+
+const defaultRuleSetReloadInterval = 60 * time.Second
+
+// ruleSetMatcher is the compiled content of a rule set at some point in time.
+type ruleSetMatcher struct {
+	domain *DomainMatcher
+	ip     *GeoIPMatcher
+}
+
+// RuleSetProvider holds the content of a RuleSet, and reloads it when its
+// file changes. Rules referencing the rule set see the new content on their
+// next match, connections already routed are left alone.
+type RuleSetProvider struct {
+	config  *RuleSet
+	path    string
+	matcher atomic.Pointer[ruleSetMatcher]
+
+	access  sync.Mutex
+	modTime time.Time
+	size    int64
+	watcher *task.Periodic
+}
+
+// NewRuleSetProvider loads the rule set and starts watching its file.
+func NewRuleSetProvider(config *RuleSet) (*RuleSetProvider, error) {
+	path := config.Path
+	if !filepath.IsAbs(path) {
+		path = platform.GetAssetLocation(path)
+	}
+	p := &RuleSetProvider{
+		config: config,
+		path:   path,
+	}
+	if err := p.reload(); err != nil {
+		return nil, errors.New("failed to load rule set ", config.Tag).Base(err)
+	}
+
+	interval := defaultRuleSetReloadInterval
+	if config.ReloadInterval > 0 {
+		interval = time.Duration(config.ReloadInterval) * time.Second
+	}
+	p.watcher = &task.Periodic{
+		Interval: interval,
+		Execute: func() error {
+			if err := p.reload(); err != nil {
+				errors.LogWarningInner(context.Background(), err, "failed to reload rule set ", config.Tag, ", keeping the previous content")
+			}
+			return nil
+		},
+	}
+	if err := p.watcher.Start(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// reload rebuilds the matcher if the file has changed since the last load.
+func (p *RuleSetProvider) reload() error {
+	p.access.Lock()
+	defer p.access.Unlock()
+
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return err
+	}
+	if p.matcher.Load() != nil && info.ModTime().Equal(p.modTime) && info.Size() == p.size {
+		return nil
+	}
+
+	data, err := filesystem.ReadFile(p.path)
+	if err != nil {
+		return err
+	}
+	domains, cidrs, err := parseRuleSet(p.config, data)
+	if err != nil {
+		return err
+	}
+	matcher := new(ruleSetMatcher)
+	if len(domains) > 0 {
+		if matcher.domain, err = NewMphMatcherGroup(domains); err != nil {
+			return errors.New("failed to build domain matcher").Base(err)
+		}
+	}
+	if len(cidrs) > 0 {
+		// Not shared through GlobalGeoIPContainer, which caches by country
+		// code and would keep the stale list after a reload.
+		matcher.ip = new(GeoIPMatcher)
+		if err := matcher.ip.Init(cidrs); err != nil {
+			return errors.New("failed to build IP matcher").Base(err)
+		}
+	}
+
+	p.matcher.Store(matcher)
+	p.modTime = info.ModTime()
+	p.size = info.Size()
+	errors.LogInfo(context.Background(), "rule set ", p.config.Tag, " loaded with ", len(domains), " domain(s) and ", len(cidrs), " CIDR(s)")
+	return nil
+}
+
+// Close stops watching the file.
+func (p *RuleSetProvider) Close() error {
+	return p.watcher.Close()
+}
+
+func (p *RuleSetProvider) applyDomain(domain string) bool {
+	m := p.matcher.Load()
+	return m.domain != nil && m.domain.ApplyDomain(domain)
+}
+
+func (p *RuleSetProvider) hasIP() bool {
+	return p.matcher.Load().ip != nil
+}
+
+func (p *RuleSetProvider) applyIPs(ctx routing.Context) bool {
+	m := p.matcher.Load()
+	if m.ip == nil {
+		return false
+	}
+	for _, ip := range ctx.GetTargetIPs() {
+		if m.ip.Match(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// RuleSetMatcher matches the target domain or IP against rule sets.
+type RuleSetMatcher struct {
+	providers []*RuleSetProvider
+}
+
+func NewRuleSetMatcher(providers []*RuleSetProvider) *RuleSetMatcher {
+	return &RuleSetMatcher{
+		providers: providers,
+	}
+}
+
+// Apply implements Condition.
+func (m *RuleSetMatcher) Apply(ctx routing.Context) bool {
+	if domain := ctx.GetTargetDomain(); len(domain) > 0 {
+		for _, p := range m.providers {
+			if p.applyDomain(domain) {
+				return true
+			}
+		}
+	}
+	// Target IPs may trigger a DNS lookup, so only ask for them if a rule set
+	// actually has IPs.
+	for _, p := range m.providers {
+		if p.hasIP() && p.applyIPs(ctx) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseRuleSet(config *RuleSet, data []byte) ([]*Domain, []*CIDR, error) {
+	switch config.Format {
+	case RuleSet_Text:
+		return parseTextRuleSet(data)
+	case RuleSet_GeoSite:
+		var list GeoSiteList
+		if err := proto.Unmarshal(data, &list); err != nil {
+			return nil, nil, err
+		}
+		for _, site := range list.Entry {
+			if strings.EqualFold(site.CountryCode, config.Code) {
+				return site.Domain, nil, nil
+			}
+		}
+		return nil, nil, errors.New("list not found: ", config.Code)
+	case RuleSet_GeoIP:
+		var list GeoIPList
+		if err := proto.Unmarshal(data, &list); err != nil {
+			return nil, nil, err
+		}
+		for _, geoip := range list.Entry {
+			if strings.EqualFold(geoip.CountryCode, config.Code) {
+				return nil, geoip.Cidr, nil
+			}
+		}
+		return nil, nil, errors.New("list not found: ", config.Code)
+	case RuleSet_Binary:
+		return DecodeRuleSet(bytes.NewReader(data))
+	default:
+		return nil, nil, errors.New("unknown rule set format ", config.Format)
+	}
+}
+
+var textRuleSetPrefixes = []struct {
+	prefix string
+	t      Domain_Type
+}{
+	{"full:", Domain_Full},
+	{"domain:", Domain_Domain},
+	{"keyword:", Domain_Plain},
+	{"regexp:", Domain_Regex},
+}
+
+func parseTextRuleSet(data []byte) ([]*Domain, []*CIDR, error) {
+	var domains []*Domain
+	var cidrs []*CIDR
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+
+		if prefix, err := netip.ParsePrefix(line); err == nil {
+			cidrs = append(cidrs, &CIDR{
+				Ip:     prefix.Addr().AsSlice(),
+				Prefix: uint32(prefix.Bits()),
+			})
+			continue
+		}
+		if addr, err := netip.ParseAddr(line); err == nil {
+			cidrs = append(cidrs, &CIDR{
+				Ip:     addr.AsSlice(),
+				Prefix: uint32(addr.BitLen()),
+			})
+			continue
+		}
+
+		domain := &Domain{
+			Type:  Domain_Domain,
+			Value: line,
+		}
+		for _, p := range textRuleSetPrefixes {
+			if strings.HasPrefix(line, p.prefix) {
+				domain.Type = p.t
+				domain.Value = line[len(p.prefix):]
+				break
+			}
+		}
+		if domain.Type != Domain_Regex {
+			domain.Value = strings.ToLower(domain.Value)
+		}
+		if len(domain.Value) == 0 {
+			return nil, nil, errors.New("empty domain in line: ", line)
+		}
+		domains = append(domains, domain)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return domains, cidrs, nil
+}
+
+// The binary rule set format is the magic "XRS", a version byte, and then a
+// zlib stream of the item count followed by the items. An item is a type
+// byte, which is a Domain_Type for domains and ruleSetItemCIDR for CIDRs, and
+// a length prefixed value. The value of a CIDR is the IP followed by the
+// prefix length. Counts and lengths are uvarints.
+const (
+	ruleSetMagic    = "XRS"
+	ruleSetVersion  = 1
+	ruleSetItemCIDR = 0x10
+
+	maxRuleSetItemLength = 4096
+)
+
+// EncodeRuleSet writes domains and CIDRs in the binary rule set format.
+func EncodeRuleSet(w io.Writer, domains []*Domain, cidrs []*CIDR) error {
+	if _, err := io.WriteString(w, ruleSetMagic); err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte{ruleSetVersion}); err != nil {
+		return err
+	}
+
+	zw := zlib.NewWriter(w)
+	bw := bufio.NewWriter(zw)
+	writeItem := func(t byte, value []byte) error {
+		if len(value) > maxRuleSetItemLength {
+			return errors.New("rule set item too long: ", len(value))
+		}
+		bw.WriteByte(t)
+		bw.Write(binary.AppendUvarint(nil, uint64(len(value))))
+		_, err := bw.Write(value)
+		return err
+	}
+
+	bw.Write(binary.AppendUvarint(nil, uint64(len(domains)+len(cidrs))))
+	for _, d := range domains {
+		if err := writeItem(byte(d.Type), []byte(d.Value)); err != nil {
+			return err
+		}
+	}
+	for _, c := range cidrs {
+		if err := writeItem(ruleSetItemCIDR, append(append([]byte(nil), c.Ip...), byte(c.Prefix))); err != nil {
+			return err
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// DecodeRuleSet reads domains and CIDRs in the binary rule set format.
+func DecodeRuleSet(r io.Reader) ([]*Domain, []*CIDR, error) {
+	header := make([]byte, len(ruleSetMagic)+1)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, nil, errors.New("failed to read rule set header").Base(err)
+	}
+	if string(header[:len(ruleSetMagic)]) != ruleSetMagic {
+		return nil, nil, errors.New("not a binary rule set")
+	}
+	if header[len(ruleSetMagic)] != ruleSetVersion {
+		return nil, nil, errors.New("unsupported rule set version ", header[len(ruleSetMagic)])
+	}
+
+	zr, err := zlib.NewReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer zr.Close()
+	br := bufio.NewReader(zr)
+
+	count, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, nil, err
+	}
+	var domains []*Domain
+	var cidrs []*CIDR
+	for range count {
+		t, err := br.ReadByte()
+		if err != nil {
+			return nil, nil, err
+		}
+		length, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, nil, err
+		}
+		if length > maxRuleSetItemLength {
+			return nil, nil, errors.New("rule set item too long: ", length)
+		}
+		value := make([]byte, length)
+		if _, err := io.ReadFull(br, value); err != nil {
+			return nil, nil, err
+		}
+
+		switch t {
+		case byte(Domain_Plain), byte(Domain_Regex), byte(Domain_Domain), byte(Domain_Full):
+			domains = append(domains, &Domain{
+				Type:  Domain_Type(t),
+				Value: string(value),
+			})
+		case ruleSetItemCIDR:
+			if length != 4+1 && length != 16+1 {
+				return nil, nil, errors.New("invalid CIDR item of length ", length)
+			}
+			cidrs = append(cidrs, &CIDR{
+				Ip:     value[:length-1],
+				Prefix: uint32(value[length-1]),
+			})
+		default:
+			return nil, nil, errors.New("unknown rule set item type ", t)
+		}
+	}
+	return domains, cidrs, nil
+}
@@ -0,0 +1,125 @@
+package router_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/GFW-knocker/Xray-core/app/router"
+	"github.com/GFW-knocker/Xray-core/common"
+	"github.com/GFW-knocker/Xray-core/common/net"
+	"github.com/GFW-knocker/Xray-core/common/session"
+	"github.com/GFW-knocker/Xray-core/features/routing"
+	routing_session "github.com/GFW-knocker/Xray-core/features/routing/session"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func withTarget(dest net.Destination) routing.Context {
+	return routing_session.AsRoutingContext(session.ContextWithOutbounds(context.Background(), []*session.Outbound{{
+		Target: dest,
+	}}))
+}
+
+func TestRuleSetReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "proxy.txt")
+	common.Must(os.WriteFile(path, []byte("# comment\nexample.com\nfull:www.example.org\n10.0.0.0/8\n"), 0o644))
+
+	r := new(Router)
+	common.Must(r.Init(context.TODO(), &Config{
+		RuleSet: []*RuleSet{
+			{
+				Tag:            "proxy",
+				Path:           path,
+				Format:         RuleSet_Text,
+				ReloadInterval: 1,
+			},
+		},
+		Rule: []*RoutingRule{
+			{
+				TargetTag: &RoutingRule_Tag{
+					Tag: "proxy",
+				},
+				RuleSet: []string{"proxy"},
+			},
+		},
+	}, nil, nil, nil))
+	defer r.Close()
+
+	match := func(dest net.Destination) bool {
+		_, err := r.PickRoute(withTarget(dest))
+		return err == nil
+	}
+
+	for dest, expected := range map[net.Destination]bool{
+		net.TCPDestination(net.DomainAddress("a.example.com"), 443):   true,
+		net.TCPDestination(net.DomainAddress("www.example.org"), 443): true,
+		net.TCPDestination(net.DomainAddress("a.example.org"), 443):   false,
+		net.TCPDestination(net.ParseAddress("10.1.2.3"), 443):         true,
+		net.TCPDestination(net.ParseAddress("11.1.2.3"), 443):         false,
+	} {
+		if match(dest) != expected {
+			t.Error("expect ", dest, " to match: ", expected)
+		}
+	}
+
+	common.Must(os.WriteFile(path, []byte("example.net\n"), 0o644))
+	time.Sleep(1500 * time.Millisecond)
+
+	if match(net.TCPDestination(net.DomainAddress("a.example.com"), 443)) {
+		t.Error("expect old rule set to be replaced")
+	}
+	if !match(net.TCPDestination(net.DomainAddress("a.example.net"), 443)) {
+		t.Error("expect new rule set to be loaded")
+	}
+
+	// A broken file keeps the previous content.
+	common.Must(os.WriteFile(path, []byte("regexp:(\n"), 0o644))
+	time.Sleep(1500 * time.Millisecond)
+
+	if !match(net.TCPDestination(net.DomainAddress("a.example.net"), 443)) {
+		t.Error("expect rule set to be kept on reload error")
+	}
+}
+
+func TestRuleSetUnknownTag(t *testing.T) {
+	rule := &RoutingRule{
+		TargetTag: &RoutingRule_Tag{
+			Tag: "proxy",
+		},
+		RuleSet: []string{"proxy"},
+	}
+	if _, err := rule.BuildCondition(); err == nil {
+		t.Error("expect error for unknown rule set")
+	}
+}
+
+func TestRuleSetBinary(t *testing.T) {
+	domains := []*Domain{
+		{Type: Domain_Domain, Value: "example.com"},
+		{Type: Domain_Regex, Value: "^ads?\\."},
+	}
+	cidrs := []*CIDR{
+		{Ip: []byte{10, 0, 0, 0}, Prefix: 8},
+		{Ip: net.ParseAddress("2001:db8::").IP(), Prefix: 32},
+	}
+
+	var b bytes.Buffer
+	common.Must(EncodeRuleSet(&b, domains, cidrs))
+	decodedDomains, decodedCIDRs, err := DecodeRuleSet(bytes.NewReader(b.Bytes()))
+	common.Must(err)
+
+	if r := cmp.Diff(decodedDomains, domains, protocmp.Transform()); r != "" {
+		t.Error(r)
+	}
+	if r := cmp.Diff(decodedCIDRs, cidrs, protocmp.Transform()); r != "" {
+		t.Error(r)
+	}
+
+	if _, _, err := DecodeRuleSet(bytes.NewReader([]byte("XRS\x02"))); err == nil {
+		t.Error("expect error for unknown version")
+	}
+}
@@ -0,0 +1,115 @@
+package router
+
+import (
+	"context"
+	"hash/fnv"
+
+	"github.com/GFW-knocker/Xray-core/common"
+	"github.com/GFW-knocker/Xray-core/core"
+	"github.com/GFW-knocker/Xray-core/features/extension"
+	"github.com/GFW-knocker/Xray-core/features/routing"
+)
+
+// ConsistentHashStrategy keeps connections with the same key on the same
+// outbound. It uses rendezvous hashing over the alive candidates, so when an
+// outbound dies only the keys it was serving move elsewhere, and they come
+// back once it is alive again.
+type ConsistentHashStrategy struct {
+	FallbackTag string
+
+	keys        []StrategyConsistentHashConfig_Key
+	ctx         context.Context
+	observatory extension.Observatory
+}
+
+// NewConsistentHashStrategy creates a new ConsistentHashStrategy with settings
+func NewConsistentHashStrategy(settings *StrategyConsistentHashConfig, fallbackTag string) *ConsistentHashStrategy {
+	keys := settings.GetKey()
+	if len(keys) == 0 {
+		keys = []StrategyConsistentHashConfig_Key{StrategyConsistentHashConfig_SourceIP}
+	}
+	return &ConsistentHashStrategy{
+		FallbackTag: fallbackTag,
+		keys:        keys,
+	}
+}
+
+// InjectContext implements extension.ContextReceiver. The observatory, if
+// any, is used to skip dead outbounds whether or not there is a fallback.
+func (s *ConsistentHashStrategy) InjectContext(ctx context.Context) {
+	s.ctx = ctx
+	common.Must(core.OptionalFeatures(s.ctx, func(observatory extension.Observatory) error {
+		s.observatory = observatory
+		return nil
+	}))
+}
+
+func (s *ConsistentHashStrategy) GetPrincipleTarget(strings []string) []string {
+	return strings
+}
+
+// PickOutbound implements BalancingStrategy. Without a connection, all keys
+// are empty and the pick only depends on the candidates.
+func (s *ConsistentHashStrategy) PickOutbound(candidates []string) string {
+	return s.pick(nil, candidates)
+}
+
+// PickOutboundForContext implements BalancingContextStrategy.
+func (s *ConsistentHashStrategy) PickOutboundForContext(ctx routing.Context, candidates []string) string {
+	return s.pick(s.hashKey(ctx), candidates)
+}
+
+func (s *ConsistentHashStrategy) pick(key []byte, candidates []string) string {
+	candidates = filterAlive(s.ctx, s.observatory, candidates)
+	var selected string
+	var highest uint64
+	for _, tag := range candidates {
+		if weight := rendezvousWeight(key, tag); selected == "" || weight > highest {
+			selected = tag
+			highest = weight
+		}
+	}
+	// goes to fallbackTag if there is no candidate
+	return selected
+}
+
+// hashKey joins the configured fields of the connection into a key.
+func (s *ConsistentHashStrategy) hashKey(ctx routing.Context) []byte {
+	var key []byte
+	for _, k := range s.keys {
+		switch k {
+		case StrategyConsistentHashConfig_SourceIP:
+			if ips := ctx.GetSourceIPs(); len(ips) > 0 {
+				key = append(key, ips[0]...)
+			}
+		case StrategyConsistentHashConfig_TargetDomain:
+			if domain := ctx.GetTargetDomain(); len(domain) > 0 {
+				key = append(key, domain...)
+			} else if ips := ctx.GetTargetIPs(); len(ips) > 0 {
+				key = append(key, ips[0]...)
+			}
+		case StrategyConsistentHashConfig_UserEmail:
+			key = append(key, ctx.GetUser()...)
+		}
+		// separates the fields, so that different splits of the same bytes
+		// don't collide
+		key = append(key, 0)
+	}
+	return key
+}
+
+// rendezvousWeight is the weight of tag for key. The tag with the highest
+// weight wins.
+func rendezvousWeight(key []byte, tag string) uint64 {
+	h := fnv.New64a()
+	h.Write(key)
+	h.Write([]byte(tag))
+	// FNV alone mixes the last bytes poorly, so finalize it as in splitmix64.
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
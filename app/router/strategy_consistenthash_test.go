@@ -0,0 +1,135 @@
+package router_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GFW-knocker/Xray-core/app/observatory"
+	. "github.com/GFW-knocker/Xray-core/app/router"
+	"github.com/GFW-knocker/Xray-core/common"
+	"github.com/GFW-knocker/Xray-core/common/net"
+	"github.com/GFW-knocker/Xray-core/common/session"
+	"github.com/GFW-knocker/Xray-core/core"
+	"github.com/GFW-knocker/Xray-core/features/extension"
+	"github.com/GFW-knocker/Xray-core/features/routing"
+	routing_session "github.com/GFW-knocker/Xray-core/features/routing/session"
+	"google.golang.org/protobuf/proto"
+)
+
+func withSource(i int) routing.Context {
+	ctx := session.ContextWithInbound(context.Background(), &session.Inbound{
+		Source: net.TCPDestination(net.IPAddress([]byte{10, 0, byte(i >> 8), byte(i)}), 12345),
+	})
+	return routing_session.AsRoutingContext(session.ContextWithOutbounds(ctx, []*session.Outbound{{
+		Target: net.TCPDestination(net.DomainAddress("example.com"), 443),
+	}}))
+}
+
+func TestConsistentHashStrategy(t *testing.T) {
+	strategy := NewConsistentHashStrategy(&StrategyConsistentHashConfig{}, "")
+	candidates := []string{"a", "b", "c", "d"}
+
+	picks := make(map[int]string)
+	counts := make(map[string]int)
+	for i := range 1000 {
+		ctx := withSource(i)
+		tag := strategy.PickOutboundForContext(ctx, candidates)
+		if again := strategy.PickOutboundForContext(ctx, candidates); again != tag {
+			t.Fatal("expect the same pick for the same source, got ", tag, " and ", again)
+		}
+		picks[i] = tag
+		counts[tag]++
+	}
+	for _, tag := range candidates {
+		if counts[tag] < 150 {
+			t.Error("outbound ", tag, " is picked only ", counts[tag], " times")
+		}
+	}
+
+	// Only the sources on the dead outbound move.
+	for i, previous := range picks {
+		ctx := withSource(i)
+		tag := strategy.PickOutboundForContext(ctx, []string{"a", "c", "d"})
+		if previous != "b" && tag != previous {
+			t.Error("source ", i, " moved from ", previous, " to ", tag)
+		}
+		if tag == "b" {
+			t.Error("dead outbound is picked")
+		}
+	}
+
+	if tag := strategy.PickOutbound(nil); tag != "" {
+		t.Error("expect empty tag without candidates, but got ", tag)
+	}
+}
+
+// staticObservatory reports the outbounds in dead as dead, and others alive.
+type staticObservatory struct {
+	dead map[string]bool
+}
+
+func (o *staticObservatory) Type() interface{} {
+	return extension.ObservatoryType()
+}
+
+func (o *staticObservatory) Start() error {
+	return nil
+}
+
+func (o *staticObservatory) Close() error {
+	return nil
+}
+
+func (o *staticObservatory) GetObservation(ctx context.Context) (proto.Message, error) {
+	result := &observatory.ObservationResult{}
+	for _, tag := range []string{"a", "b", "c", "d"} {
+		result.Status = append(result.Status, &observatory.OutboundStatus{
+			OutboundTag: tag,
+			Alive:       !o.dead[tag],
+		})
+	}
+	return result, nil
+}
+
+func TestConsistentHashStrategyObservatory(t *testing.T) {
+	v, err := core.New(&core.Config{})
+	common.Must(err)
+	ctx := context.WithValue(context.Background(), core.XrayKey(1), v)
+
+	// no fallback tag, the observatory is still used
+	strategy := NewConsistentHashStrategy(&StrategyConsistentHashConfig{}, "")
+	strategy.InjectContext(ctx)
+	o := &staticObservatory{dead: map[string]bool{}}
+	common.Must(v.AddFeature(o))
+
+	candidates := []string{"a", "b", "c", "d"}
+	picks := make(map[int]string)
+	for i := range 1000 {
+		picks[i] = strategy.PickOutboundForContext(withSource(i), candidates)
+	}
+
+	o.dead["b"] = true
+	moved := 0
+	for i, previous := range picks {
+		tag := strategy.PickOutboundForContext(withSource(i), candidates)
+		if tag == "b" {
+			t.Fatal("dead outbound is picked")
+		}
+		if previous != "b" && tag != previous {
+			t.Error("source ", i, " moved from ", previous, " to ", tag)
+		}
+		if tag != previous {
+			moved++
+		}
+	}
+	if moved == 0 {
+		t.Error("no source moved off the dead outbound")
+	}
+
+	delete(o.dead, "b")
+	for i, previous := range picks {
+		if tag := strategy.PickOutboundForContext(withSource(i), candidates); tag != previous {
+			t.Error("source ", i, " did not come back to ", previous)
+		}
+	}
+}
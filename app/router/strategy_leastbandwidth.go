@@ -0,0 +1,154 @@
+package router
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/GFW-knocker/Xray-core/common"
+	"github.com/GFW-knocker/Xray-core/common/errors"
+	"github.com/GFW-knocker/Xray-core/core"
+	"github.com/GFW-knocker/Xray-core/features/extension"
+	"github.com/GFW-knocker/Xray-core/features/policy"
+	"github.com/GFW-knocker/Xray-core/features/stats"
+)
+
+const (
+	defaultBandwidthWindow = time.Minute
+	defaultConnectionCost  = 32 * 1024
+
+	// bandwidthSamples is the number of samples kept per window.
+	bandwidthSamples = 16
+)
+
+// LeastBandwidthStrategy picks the outbound with the least traffic over a
+// sliding window, as counted by the outbound uplink and downlink stats, plus
+// a cost for each of its active connections.
+type LeastBandwidthStrategy struct {
+	FallbackTag string
+
+	costs          *WeightManager
+	window         time.Duration
+	connectionCost float64
+
+	ctx         context.Context
+	observatory extension.Observatory
+	stats       stats.Manager
+
+	mu      sync.Mutex
+	samples map[string][]bandwidthSample
+}
+
+type bandwidthSample struct {
+	time  time.Time
+	bytes int64
+}
+
+// NewLeastBandwidthStrategy creates a new LeastBandwidthStrategy with settings
+func NewLeastBandwidthStrategy(settings *StrategyLeastBandwidthConfig, fallbackTag string) *LeastBandwidthStrategy {
+	s := &LeastBandwidthStrategy{
+		FallbackTag:    fallbackTag,
+		window:         time.Duration(settings.Window),
+		connectionCost: float64(settings.ConnectionCost),
+		costs: NewWeightManager(
+			settings.Costs, 1,
+			func(value, cost float64) float64 {
+				return value * cost
+			},
+		),
+		samples: make(map[string][]bandwidthSample),
+	}
+	if s.window <= 0 {
+		s.window = defaultBandwidthWindow
+	}
+	if s.connectionCost <= 0 {
+		s.connectionCost = defaultConnectionCost
+	}
+	return s
+}
+
+// InjectContext implements extension.ContextReceiver. The observatory, if
+// any, is used to skip dead outbounds whether or not there is a fallback.
+func (s *LeastBandwidthStrategy) InjectContext(ctx context.Context) {
+	s.ctx = ctx
+	common.Must(core.RequireFeatures(s.ctx, func(sm stats.Manager, pm policy.Manager) error {
+		s.stats = sm
+		if p := pm.ForSystem().Stats; !p.OutboundUplink || !p.OutboundDownlink {
+			errors.LogWarning(s.ctx, "statsOutboundUplink and statsOutboundDownlink are needed by the leastBandwidth strategy, which picks by costs only without them")
+		}
+		return nil
+	}))
+	common.Must(core.OptionalFeatures(s.ctx, func(observatory extension.Observatory) error {
+		s.observatory = observatory
+		return nil
+	}))
+}
+
+func (s *LeastBandwidthStrategy) GetPrincipleTarget(strings []string) []string {
+	return strings
+}
+
+// PickOutbound implements BalancingStrategy.
+func (s *LeastBandwidthStrategy) PickOutbound(candidates []string) string {
+	candidates = filterAlive(s.ctx, s.observatory, candidates)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	var selected string
+	var least float64
+	for _, tag := range candidates {
+		// Idle outbounds still differ in cost.
+		load := s.costs.Apply(tag, s.bandwidth(tag, now)+s.connections(tag)*s.connectionCost+1)
+		if selected == "" || load < least {
+			selected = tag
+			least = load
+		}
+	}
+	// goes to fallbackTag if there is no candidate
+	return selected
+}
+
+func (s *LeastBandwidthStrategy) counter(name string) int64 {
+	if s.stats == nil {
+		return 0
+	}
+	if c := s.stats.GetCounter(name); c != nil {
+		return c.Value()
+	}
+	return 0
+}
+
+func (s *LeastBandwidthStrategy) connections(tag string) float64 {
+	return float64(s.counter("outbound>>>" + tag + ">>>connections"))
+}
+
+// bandwidth records a sample of the traffic of tag, and returns its rate in
+// bytes per second since the oldest sample within the window.
+func (s *LeastBandwidthStrategy) bandwidth(tag string, now time.Time) float64 {
+	bytes := s.counter("outbound>>>"+tag+">>>traffic>>>uplink") + s.counter("outbound>>>"+tag+">>>traffic>>>downlink")
+
+	samples := s.samples[tag]
+	if n := len(samples); n > 0 && bytes < samples[n-1].bytes {
+		// the counters were reset
+		samples = samples[:0]
+	}
+	if n := len(samples); n == 0 || now.Sub(samples[n-1].time) >= s.window/bandwidthSamples {
+		samples = append(samples, bandwidthSample{time: now, bytes: bytes})
+	}
+	// Keep one sample at or before the start of the window as the base.
+	start := now.Add(-s.window)
+	drop := 0
+	for drop+1 < len(samples) && !samples[drop+1].time.After(start) {
+		drop++
+	}
+	samples = samples[drop:]
+	s.samples[tag] = samples
+
+	base := samples[0]
+	elapsed := now.Sub(base.time).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(bytes-base.bytes) / elapsed
+}
@@ -0,0 +1,120 @@
+package router_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GFW-knocker/Xray-core/app/dispatcher"
+	"github.com/GFW-knocker/Xray-core/app/policy"
+	"github.com/GFW-knocker/Xray-core/app/proxyman"
+	. "github.com/GFW-knocker/Xray-core/app/router"
+	"github.com/GFW-knocker/Xray-core/app/stats"
+	"github.com/GFW-knocker/Xray-core/common"
+	"github.com/GFW-knocker/Xray-core/common/net"
+	"github.com/GFW-knocker/Xray-core/common/serial"
+	"github.com/GFW-knocker/Xray-core/core"
+	"github.com/GFW-knocker/Xray-core/features/routing"
+	feature_stats "github.com/GFW-knocker/Xray-core/features/stats"
+	"github.com/GFW-knocker/Xray-core/proxy/blackhole"
+)
+
+func TestLeastBandwidthBalancer(t *testing.T) {
+	outbounds := []*core.OutboundHandlerConfig{}
+	for _, tag := range []string{"test-a", "test-b", "test-slow"} {
+		outbounds = append(outbounds, &core.OutboundHandlerConfig{
+			Tag:           tag,
+			ProxySettings: serial.ToTypedMessage(&blackhole.Config{}),
+		})
+	}
+	config := &core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&stats.Config{}),
+			serial.ToTypedMessage(&policy.Config{
+				System: &policy.SystemPolicy{
+					Stats: &policy.SystemPolicy_Stats{
+						OutboundUplink:      true,
+						OutboundDownlink:    true,
+						OutboundConnections: true,
+					},
+				},
+			}),
+			serial.ToTypedMessage(&Config{
+				Rule: []*RoutingRule{
+					{
+						TargetTag: &RoutingRule_BalancingTag{
+							BalancingTag: "balance",
+						},
+						Networks: []net.Network{net.Network_TCP},
+					},
+				},
+				BalancingRule: []*BalancingRule{
+					{
+						Tag:              "balance",
+						OutboundSelector: []string{"test-"},
+						Strategy:         "leastbandwidth",
+						StrategySettings: serial.ToTypedMessage(&StrategyLeastBandwidthConfig{
+							Costs: []*StrategyWeight{
+								{Match: "slow", Value: 100},
+							},
+							Window:         int64(time.Second),
+							ConnectionCost: 1000,
+						}),
+					},
+				},
+			}),
+		},
+		Outbound: outbounds,
+	}
+
+	v, err := core.New(config)
+	common.Must(err)
+	r := v.GetFeature(routing.RouterType()).(routing.Router)
+	sm := v.GetFeature(feature_stats.ManagerType()).(feature_stats.Manager)
+
+	pick := func() string {
+		route, err := r.PickRoute(withTarget(net.TCPDestination(net.DomainAddress("example.com"), 443)))
+		common.Must(err)
+		return route.GetOutboundTag()
+	}
+
+	if tag := pick(); tag != "test-a" {
+		t.Error("expect the first idle outbound of least cost, but got ", tag)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	sm.GetCounter("outbound>>>test-a>>>traffic>>>downlink").Add(100000)
+	if tag := pick(); tag != "test-b" {
+		t.Error("expect test-b as test-a is busy, but got ", tag)
+	}
+
+	sm.GetCounter("outbound>>>test-b>>>connections").Add(10000)
+	if tag := pick(); tag != "test-slow" {
+		t.Error("expect test-slow as the others are busy, but got ", tag)
+	}
+
+	// Traffic out of the window no longer counts.
+	sm.GetCounter("outbound>>>test-b>>>connections").Set(0)
+	time.Sleep(1500 * time.Millisecond)
+	if tag := pick(); tag != "test-a" {
+		t.Error("expect test-a after the window passed, but got ", tag)
+	}
+}
+
+func TestLeastBandwidthStrategyObservatory(t *testing.T) {
+	v, err := core.New(&core.Config{})
+	common.Must(err)
+	ctx := context.WithValue(context.Background(), core.XrayKey(1), v)
+
+	// no fallback tag, the observatory is still used
+	strategy := NewLeastBandwidthStrategy(&StrategyLeastBandwidthConfig{}, "")
+	strategy.InjectContext(ctx)
+	o := &staticObservatory{dead: map[string]bool{"a": true}}
+	common.Must(v.AddFeature(o))
+
+	if tag := strategy.PickOutbound([]string{"a", "b"}); tag != "b" {
+		t.Error("expect the alive outbound, but got ", tag)
+	}
+}
@@ -0,0 +1,76 @@
+package router
+
+import (
+	"github.com/GFW-knocker/Xray-core/features/routing"
+)
+
+// conditionName returns the name of cond as used in rule configs.
+func conditionName(cond Condition) string {
+	switch c := cond.(type) {
+	case *DomainMatcher:
+		return "domain"
+	case *MultiGeoIPMatcher:
+		switch {
+		case c.onLocal:
+			return "localIP"
+		case c.onSource:
+			return "source"
+		default:
+			return "ip"
+		}
+	case *PortMatcher:
+		switch {
+		case c.onLocal:
+			return "localPort"
+		case c.onSource:
+			return "sourcePort"
+		default:
+			return "port"
+		}
+	case NetworkMatcher:
+		return "network"
+	case *UserMatcher:
+		return "user"
+	case *InboundTagMatcher:
+		return "inboundTag"
+	case *ProtocolMatcher:
+		return "protocol"
+	case *AttributeMatcher:
+		return "attrs"
+	case *RuleSetMatcher:
+		return "ruleSet"
+	case *ScheduleMatcher:
+		return "schedule"
+	case *ALPNMatcher:
+		return "alpn"
+	case *TLSVersionMatcher:
+		return "tlsVersion"
+	case *ECHMatcher:
+		return "ech"
+	case *SNIMatcher:
+		return "sni"
+	default:
+		return "unknown"
+	}
+}
+
+// traceRule evaluates every condition of the rule, instead of stopping at
+// the first one that fails.
+func traceRule(index int, rule *Rule, ctx routing.Context) *routing.RuleTrace {
+	trace := &routing.RuleTrace{
+		Index:   index,
+		RuleTag: rule.RuleTag,
+		Matched: true,
+	}
+	conds := []Condition{rule.Condition}
+	if chain, ok := rule.Condition.(*ConditionChan); ok {
+		conds = *chain
+	}
+	for _, cond := range conds {
+		if !cond.Apply(ctx) {
+			trace.Matched = false
+			trace.FailedConditions = append(trace.FailedConditions, conditionName(cond))
+		}
+	}
+	return trace
+}
@@ -21,6 +21,10 @@ var (
 
 var LookupIP = net.LookupIP
 
+var LookupTXT = net.LookupTXT
+
+var LookupSRV = net.LookupSRV
+
 var FileConn = net.FileConn
 
 // ParseIP is an alias of net.ParseIP
@@ -0,0 +1,129 @@
+// Package noise sends junk packets ahead of the real traffic of UDP based
+// outbounds and transports, to confuse censors that classify flows by their
+// first packets.
+package noise
+
+import (
+	"crypto/rand"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/GFW-knocker/Xray-core/common/errors"
+	"github.com/GFW-knocker/Xray-core/common/net"
+)
+
+// maxCount bounds the number of packets of a Noise in a round.
+const maxCount = 100
+
+// Sender sends the noise of a Config through a write function, as one round
+// on demand and then periodically when KeepAlive is set.
+type Sender struct {
+	sync.Mutex
+	config    *Config
+	templates []*Template // parsed Template of every Noise, if any
+	port      net.Port
+	write     func(b []byte) error
+	counter   uint32
+	done      chan struct{}
+	once      sync.Once
+}
+
+// NewSender creates a Sender. port is the destination port, for templates.
+func NewSender(config *Config, port net.Port, write func(b []byte) error) (*Sender, error) {
+	templates := make([]*Template, len(config.Noises))
+	for i, n := range config.Noises {
+		if n.Template == "" {
+			continue
+		}
+		template, err := ParseTemplate(n.Template)
+		if err != nil {
+			return nil, err
+		}
+		templates[i] = template
+	}
+	return &Sender{
+		config:    config,
+		templates: templates,
+		port:      port,
+		write:     write,
+		done:      make(chan struct{}),
+	}, nil
+}
+
+// Send sends a round of noise: every Noise its count of packets, waiting
+// its delay after each one.
+func (s *Sender) Send() error {
+	s.Lock()
+	defer s.Unlock()
+	for i, n := range s.config.Noises {
+		template := s.templates[i]
+		var packet []byte
+		switch {
+		case template != nil:
+			// rendered on every packet below
+		case n.Packet != nil:
+			packet = n.Packet
+		default:
+			packet = appendRandom(nil, int(randBetween(int64(n.LengthMin), int64(n.LengthMax))))
+		}
+
+		count := min(max(randBetween(int64(n.CountMin), int64(n.CountMax)), 1), maxCount)
+		var delay time.Duration
+		if n.DelayMin != 0 || n.DelayMax != 0 {
+			delay = time.Duration(randBetween(int64(n.DelayMin), int64(n.DelayMax))) * time.Millisecond
+		}
+
+		for range count {
+			if template != nil {
+				packet = template.Render(s.counter, s.port)
+			}
+			s.counter++
+			if err := s.write(packet); err != nil {
+				return errors.New("failed to write noise").Base(err)
+			}
+			if delay > 0 {
+				time.Sleep(delay)
+			}
+		}
+	}
+	return nil
+}
+
+// KeepAlive sends a round of noise every KeepAlive seconds, until the Sender
+// is closed. It does nothing if KeepAlive is 0.
+func (s *Sender) KeepAlive() {
+	if s.config.KeepAlive == 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(time.Duration(s.config.KeepAlive) * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.Send(); err != nil {
+					return
+				}
+			case <-s.done:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the keepalive.
+func (s *Sender) Close() error {
+	s.once.Do(func() {
+		close(s.done)
+	})
+	return nil
+}
+
+func randBetween(left int64, right int64) int64 {
+	if left >= right {
+		return left
+	}
+	bigInt, _ := rand.Int(rand.Reader, big.NewInt(right-left+1))
+	return left + bigInt.Int64()
+}
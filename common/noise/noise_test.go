@@ -0,0 +1,90 @@
+package noise_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GFW-knocker/Xray-core/common"
+	. "github.com/GFW-knocker/Xray-core/common/noise"
+)
+
+type packetRecorder struct {
+	sync.Mutex
+	packets [][]byte
+}
+
+func (r *packetRecorder) write(b []byte) error {
+	r.Lock()
+	defer r.Unlock()
+	r.packets = append(r.packets, append([]byte(nil), b...))
+	return nil
+}
+
+func (r *packetRecorder) count() int {
+	r.Lock()
+	defer r.Unlock()
+	return len(r.packets)
+}
+
+func TestSender(t *testing.T) {
+	recorder := new(packetRecorder)
+	sender, err := NewSender(&Config{
+		Noises: []*Noise{
+			{Packet: []byte("hello"), CountMin: 2, CountMax: 2},
+			{LengthMin: 10, LengthMax: 20},
+			{Template: "{counter}{port}"},
+		},
+	}, 443, recorder.write)
+	common.Must(err)
+	common.Must(sender.Send())
+	common.Must(sender.Send())
+
+	if len(recorder.packets) != 8 {
+		t.Fatal("expect 8 packets but got ", len(recorder.packets))
+	}
+	for i, packet := range recorder.packets[:4] {
+		switch i % 4 {
+		case 0, 1:
+			if !bytes.Equal(packet, []byte("hello")) {
+				t.Error("unexpected packet ", i, ": ", packet)
+			}
+		case 2:
+			if len(packet) < 10 || len(packet) > 20 {
+				t.Error("unexpected length of random packet: ", len(packet))
+			}
+		case 3:
+			if !bytes.Equal(packet, []byte{0, 0, 0, 3, 0x01, 0xbb}) {
+				t.Error("unexpected template packet: ", packet)
+			}
+		}
+	}
+	if counter := binary.BigEndian.Uint32(recorder.packets[7]); counter != 7 {
+		t.Error("expect counter 7 but got ", counter)
+	}
+
+	if _, err := NewSender(&Config{Noises: []*Noise{{Template: "{unknown}"}}}, 443, recorder.write); err == nil {
+		t.Error("expect error of invalid template")
+	}
+}
+
+func TestSenderKeepAlive(t *testing.T) {
+	recorder := new(packetRecorder)
+	sender, err := NewSender(&Config{
+		Noises:    []*Noise{{Packet: []byte("hello")}},
+		KeepAlive: 1,
+	}, 443, recorder.write)
+	common.Must(err)
+	sender.KeepAlive()
+	time.Sleep(1500 * time.Millisecond)
+	common.Must(sender.Close())
+	if n := recorder.count(); n != 1 {
+		t.Error("expect 1 packet but got ", n)
+	}
+	time.Sleep(1 * time.Second)
+	if n := recorder.count(); n != 1 {
+		t.Error("noise is sent after close")
+	}
+}
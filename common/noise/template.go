@@ -0,0 +1,198 @@
+package noise
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GFW-knocker/Xray-core/common/errors"
+	"github.com/GFW-knocker/Xray-core/common/net"
+)
+
+// maxLength bounds the random bytes of a placeholder, which must fit in
+// a UDP datagram.
+const maxLength = 65507
+
+// Template renders noise packets from a template, where text is copied
+// as is and placeholders in braces are replaced on every packet:
+//
+//	{rand:N} or {rand:N-M}  N, or between N and M, random bytes
+//	{hex:0a0b}              the given bytes
+//	{timestamp}             the current unix time, 4 bytes big endian
+//	{counter}               the number of noise packets sent before, 4 bytes big endian
+//	{port}                  the destination port, 2 bytes big endian
+//	{dns}                   a DNS query of A records for a random name
+//	{stun}                  a STUN binding request
+//	{quic}                  a QUIC long header of an Initial packet, without payload
+//
+// "{{" stands for a literal "{".
+type Template struct {
+	parts []templatePart
+}
+
+// templatePart appends its rendering to b.
+type templatePart func(b []byte, counter uint32, port net.Port) []byte
+
+func ParseTemplate(template string) (*Template, error) {
+	t := new(Template)
+	var literal []byte
+	for s := template; s != ""; {
+		i := strings.IndexByte(s, '{')
+		if i < 0 {
+			literal = append(literal, s...)
+			break
+		}
+		literal = append(literal, s[:i]...)
+		s = s[i+1:]
+		if strings.HasPrefix(s, "{") {
+			literal = append(literal, '{')
+			s = s[1:]
+			continue
+		}
+		j := strings.IndexByte(s, '}')
+		if j < 0 {
+			return nil, errors.New("unterminated placeholder in template")
+		}
+		part, err := parseTemplatePart(s[:j])
+		if err != nil {
+			return nil, err
+		}
+		if len(literal) > 0 {
+			t.parts = append(t.parts, literalPart(literal))
+			literal = nil
+		}
+		t.parts = append(t.parts, part)
+		s = s[j+1:]
+	}
+	if len(literal) > 0 {
+		t.parts = append(t.parts, literalPart(literal))
+	}
+	if len(t.parts) == 0 {
+		return nil, errors.New("empty template")
+	}
+	return t, nil
+}
+
+// Render returns a new noise packet. counter is the number of noise packets
+// sent before and port the destination port.
+func (t *Template) Render(counter uint32, port net.Port) []byte {
+	var b []byte
+	for _, part := range t.parts {
+		b = part(b, counter, port)
+	}
+	return b
+}
+
+func literalPart(literal []byte) templatePart {
+	return func(b []byte, _ uint32, _ net.Port) []byte {
+		return append(b, literal...)
+	}
+}
+
+func parseTemplatePart(placeholder string) (templatePart, error) {
+	name, arg, hasArg := strings.Cut(placeholder, ":")
+	if hasArg != (name == "rand" || name == "hex") {
+		return nil, errors.New("invalid placeholder: ", placeholder)
+	}
+	switch name {
+	case "rand":
+		from, to, err := parseLength(arg)
+		if err != nil {
+			return nil, errors.New("invalid placeholder: ", placeholder).Base(err)
+		}
+		return func(b []byte, _ uint32, _ net.Port) []byte {
+			return appendRandom(b, int(randBetween(from, to)))
+		}, nil
+	case "hex":
+		literal, err := hex.DecodeString(arg)
+		if err != nil {
+			return nil, errors.New("invalid placeholder: ", placeholder).Base(err)
+		}
+		return literalPart(literal), nil
+	case "timestamp":
+		return func(b []byte, _ uint32, _ net.Port) []byte {
+			return binary.BigEndian.AppendUint32(b, uint32(time.Now().Unix()))
+		}, nil
+	case "counter":
+		return func(b []byte, counter uint32, _ net.Port) []byte {
+			return binary.BigEndian.AppendUint32(b, counter)
+		}, nil
+	case "port":
+		return func(b []byte, _ uint32, port net.Port) []byte {
+			return binary.BigEndian.AppendUint16(b, uint16(port))
+		}, nil
+	case "dns":
+		return appendDNSQuery, nil
+	case "stun":
+		return appendSTUNRequest, nil
+	case "quic":
+		return appendQUICHeader, nil
+	default:
+		return nil, errors.New("unknown placeholder: ", placeholder)
+	}
+}
+
+// parseLength parses "N" or "N-M".
+func parseLength(s string) (int64, int64, error) {
+	fromStr, toStr, isRange := strings.Cut(s, "-")
+	if !isRange {
+		toStr = fromStr
+	}
+	from, err := strconv.ParseInt(fromStr, 10, 32)
+	if err != nil {
+		return 0, 0, err
+	}
+	to, err := strconv.ParseInt(toStr, 10, 32)
+	if err != nil {
+		return 0, 0, err
+	}
+	if from < 1 || from > to || to > maxLength {
+		return 0, 0, errors.New("length must be within 1-", maxLength)
+	}
+	return from, to, nil
+}
+
+func appendRandom(b []byte, n int) []byte {
+	b = append(b, make([]byte, n)...)
+	rand.Read(b[len(b)-n:])
+	return b
+}
+
+// appendDNSQuery appends a recursive query of A records for a random name
+// under .com.
+func appendDNSQuery(b []byte, _ uint32, _ net.Port) []byte {
+	b = appendRandom(b, 2)                // ID
+	b = append(b, 0x01, 0x00, 0x00, 0x01) // RD, QDCOUNT 1
+	b = append(b, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00)
+	const letters = "abcdefghijklmnopqrstuvwxyz"
+	label := appendRandom(nil, int(randBetween(6, 14)))
+	for i := range label {
+		label[i] = letters[int(label[i])%len(letters)]
+	}
+	b = append(b, byte(len(label)))
+	b = append(b, label...)
+	b = append(b, 3, 'c', 'o', 'm', 0)
+	return append(b, 0x00, 0x01, 0x00, 0x01) // QTYPE A, QCLASS IN
+}
+
+// appendSTUNRequest appends a STUN binding request without attributes.
+func appendSTUNRequest(b []byte, _ uint32, _ net.Port) []byte {
+	b = append(b, 0x00, 0x01, 0x00, 0x00) // binding request, no attributes
+	b = append(b, 0x21, 0x12, 0xa4, 0x42) // magic cookie
+	return appendRandom(b, 12)            // transaction ID
+}
+
+// appendQUICHeader appends the start of the long header of a QUIC v1
+// Initial packet with random connection IDs and no token. The length and
+// what follows are left to the rest of the template.
+func appendQUICHeader(b []byte, _ uint32, _ net.Port) []byte {
+	b = append(b, 0xc1, 0x00, 0x00, 0x00, 0x01) // Initial, version 1
+	b = append(b, 8)
+	b = appendRandom(b, 8)
+	b = append(b, 8)
+	b = appendRandom(b, 8)
+	return append(b, 0x00) // token length
+}
@@ -0,0 +1,68 @@
+package noise_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/GFW-knocker/Xray-core/common"
+	. "github.com/GFW-knocker/Xray-core/common/noise"
+)
+
+func TestTemplate(t *testing.T) {
+	template, err := ParseTemplate("ab{{{hex:0102}{port}{counter}{rand:16}")
+	common.Must(err)
+	b := template.Render(7, 443)
+	if len(b) != 3+2+2+4+16 {
+		t.Fatal("unexpected length ", len(b))
+	}
+	if !bytes.Equal(b[:11], []byte{'a', 'b', '{', 1, 2, 0x01, 0xbb, 0, 0, 0, 7}) {
+		t.Error("unexpected noise ", b)
+	}
+	c := template.Render(8, 443)
+	if binary.BigEndian.Uint32(c[7:]) != 8 {
+		t.Error("unexpected counter ", c[7:11])
+	}
+	if bytes.Equal(c[11:], b[11:]) {
+		t.Error("random bytes are not rendered again")
+	}
+}
+
+func TestTemplateProtocols(t *testing.T) {
+	template, err := ParseTemplate("{stun}")
+	common.Must(err)
+	if b := template.Render(0, 0); len(b) != 20 || binary.BigEndian.Uint32(b[4:]) != 0x2112a442 {
+		t.Error("invalid STUN binding request ", b)
+	}
+
+	template, err = ParseTemplate("{dns}")
+	common.Must(err)
+	b := template.Render(0, 0)
+	if len(b) < 12+1+6+5+4 || binary.BigEndian.Uint16(b[4:]) != 1 || int(b[12]) != len(b)-12-1-5-4 {
+		t.Error("invalid DNS query ", b)
+	}
+
+	template, err = ParseTemplate("{quic}{rand:1200}")
+	common.Must(err)
+	if b := template.Render(0, 0); len(b) != 24+1200 || b[0]&0xf0 != 0xc0 || binary.BigEndian.Uint32(b[1:]) != 1 {
+		t.Error("invalid QUIC Initial header ", b[:24])
+	}
+}
+
+func TestTemplateErrors(t *testing.T) {
+	for _, template := range []string{
+		"",
+		"{rand}",
+		"{rand:0}",
+		"{rand:5-2}",
+		"{rand:70000}",
+		"{hex:zz}",
+		"{port:2}",
+		"{unknown}",
+		"{stun",
+	} {
+		if _, err := ParseTemplate(template); err == nil {
+			t.Error("expect error for template ", template)
+		}
+	}
+}
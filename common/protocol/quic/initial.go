@@ -0,0 +1,344 @@
+package quic
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+
+	"github.com/GFW-knocker/Xray-core/common/errors"
+	"github.com/quic-go/quic-go/quicvarint"
+	"golang.org/x/crypto/hkdf"
+)
+
+// Frame types that may appear in Initial packets.
+const (
+	FramePadding         uint64 = 0x00
+	FramePing            uint64 = 0x01
+	FrameAck             uint64 = 0x02
+	FrameAckECN          uint64 = 0x03
+	FrameCrypto          uint64 = 0x06
+	FrameConnectionClose uint64 = 0x1c
+)
+
+// MinInitialDatagramSize is the size clients must pad datagrams carrying
+// Initial packets to, see RFC 9000 section 14.1.
+const MinInitialDatagramSize = 1200
+
+// InitialPacket is a decrypted QUIC Initial packet.
+type InitialPacket struct {
+	Version    uint32
+	DestConnID []byte
+	SrcConnID  []byte
+	Token      []byte
+	// PacketNumber is the packet number as encoded on the wire, in
+	// PacketNumberLen bytes. Use DecodePacketNumber to recover the full one.
+	PacketNumber    uint64
+	PacketNumberLen int
+	Payload         []byte
+}
+
+// InitialHeader reads the version and connection IDs of the Initial packet
+// at the start of b, without decrypting it.
+func InitialHeader(b []byte) (version uint32, destConnID, srcConnID []byte, err error) {
+	if len(b) < 7 || b[0]&0xc0 != 0xc0 || b[0]&0x30 != 0 {
+		return 0, nil, nil, errNotQuicInitial
+	}
+	version = binary.BigEndian.Uint32(b[1:5])
+	if version != version1 && version != versionDraft29 {
+		return 0, nil, nil, errNotQuic
+	}
+	b = b[5:]
+	l := int(b[0])
+	if l > 20 || len(b) < 2+l {
+		return 0, nil, nil, errNotQuic
+	}
+	destConnID, b = b[1:1+l], b[1+l:]
+	l = int(b[0])
+	if l > 20 || len(b) < 1+l {
+		return 0, nil, nil, errNotQuic
+	}
+	srcConnID = b[1 : 1+l]
+	return version, destConnID, srcConnID, nil
+}
+
+// InitialKeys protects the Initial packets sent by one side of a connection.
+type InitialKeys struct {
+	aead cipher.AEAD
+	hp   cipher.Block
+}
+
+// NewInitialKeys derives the keys of Initial packets sent by the client, or
+// by the server if isClient is false. connID is the destination connection
+// ID of the first Initial packet sent by the client.
+func NewInitialKeys(version uint32, connID []byte, isClient bool) (*InitialKeys, error) {
+	salt := quicSalt
+	if version == versionDraft29 {
+		salt = quicSaltOld
+	}
+	label := "server in"
+	if isClient {
+		label = "client in"
+	}
+	initialSecret := hkdf.Extract(crypto.SHA256.New, connID, salt)
+	secret := hkdfExpandLabel(crypto.SHA256, initialSecret, []byte{}, label, crypto.SHA256.Size())
+	hp, err := aes.NewCipher(hkdfExpandLabel(initialSuite.Hash, secret, []byte{}, "quic hp", initialSuite.KeyLen))
+	if err != nil {
+		return nil, err
+	}
+	key := hkdfExpandLabel(crypto.SHA256, secret, []byte{}, "quic key", 16)
+	iv := hkdfExpandLabel(crypto.SHA256, secret, []byte{}, "quic iv", 12)
+	return &InitialKeys{
+		aead: AEADAESGCMTLS13(key, iv),
+		hp:   hp,
+	}, nil
+}
+
+// Open decrypts the Initial packet at the start of b. It returns the packet
+// and what follows it in the datagram. b is left unchanged.
+func (k *InitialKeys) Open(b []byte) (*InitialPacket, []byte, error) {
+	p := new(InitialPacket)
+	var err error
+	p.Version, p.DestConnID, p.SrcConnID, err = InitialHeader(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	hdrLen := 7 + len(p.DestConnID) + len(p.SrcConnID)
+	tokenLen, n, err := quicvarint.Parse(b[hdrLen:])
+	if err != nil || uint64(len(b)-hdrLen-n) < tokenLen {
+		return nil, nil, errNotQuic
+	}
+	hdrLen += n
+	p.Token = b[hdrLen : hdrLen+int(tokenLen)]
+	hdrLen += int(tokenLen)
+	length, n, err := quicvarint.Parse(b[hdrLen:])
+	if err != nil || length < 20 || uint64(len(b)-hdrLen-n) < length {
+		return nil, nil, errNotQuic
+	}
+	hdrLen += n
+	rest := b[hdrLen+int(length):]
+
+	packet := make([]byte, hdrLen+int(length))
+	copy(packet, b)
+	mask := make([]byte, k.hp.BlockSize())
+	k.hp.Encrypt(mask, packet[hdrLen+4:hdrLen+4+len(mask)])
+	packet[0] ^= mask[0] & 0xf
+	p.PacketNumberLen = int(packet[0]&0x3) + 1
+	nonce := make([]byte, k.aead.NonceSize())
+	for i := range p.PacketNumberLen {
+		packet[hdrLen+i] ^= mask[i+1]
+		p.PacketNumber = p.PacketNumber<<8 | uint64(packet[hdrLen+i])
+	}
+	binary.BigEndian.PutUint64(nonce[len(nonce)-8:], p.PacketNumber)
+
+	extHdrLen := hdrLen + p.PacketNumberLen
+	p.Payload, err = k.aead.Open(nil, nonce, packet[extHdrLen:], packet[:extHdrLen])
+	if err != nil {
+		return nil, nil, err
+	}
+	return p, rest, nil
+}
+
+// Seal encrypts p and appends the resulting packet to dst. Payloads too short
+// for header protection sampling are padded.
+func (k *InitialKeys) Seal(dst []byte, p *InitialPacket) []byte {
+	payload := p.Payload
+	if min := 4 - p.PacketNumberLen; len(payload) < min {
+		payload = append(append([]byte(nil), payload...), make([]byte, min-len(payload))...)
+	}
+	start := len(dst)
+	dst = append(dst, 0xc0|byte(p.PacketNumberLen-1))
+	dst = binary.BigEndian.AppendUint32(dst, p.Version)
+	dst = append(dst, byte(len(p.DestConnID)))
+	dst = append(dst, p.DestConnID...)
+	dst = append(dst, byte(len(p.SrcConnID)))
+	dst = append(dst, p.SrcConnID...)
+	dst = quicvarint.Append(dst, uint64(len(p.Token)))
+	dst = append(dst, p.Token...)
+	dst = quicvarint.Append(dst, uint64(p.PacketNumberLen+len(payload)+k.aead.Overhead()))
+	pnOffset := len(dst)
+	for i := p.PacketNumberLen - 1; i >= 0; i-- {
+		dst = append(dst, byte(p.PacketNumber>>(8*i)))
+	}
+	nonce := make([]byte, k.aead.NonceSize())
+	binary.BigEndian.PutUint64(nonce[len(nonce)-8:], p.PacketNumber)
+	dst = k.aead.Seal(dst, nonce, payload, dst[start:])
+
+	mask := make([]byte, k.hp.BlockSize())
+	k.hp.Encrypt(mask, dst[pnOffset+4:pnOffset+4+len(mask)])
+	dst[start] ^= mask[0] & 0xf
+	for i := range p.PacketNumberLen {
+		dst[pnOffset+i] ^= mask[i+1]
+	}
+	return dst
+}
+
+// SealedSize returns the size of p once sealed.
+func (k *InitialKeys) SealedSize(p *InitialPacket) int {
+	length := p.PacketNumberLen + len(p.Payload) + k.aead.Overhead()
+	return 7 + len(p.DestConnID) + len(p.SrcConnID) + quicvarint.Len(uint64(len(p.Token))) + len(p.Token) +
+		quicvarint.Len(uint64(length)) + length
+}
+
+// DecodePacketNumber recovers a full packet number from its truncated form,
+// given the largest packet number received so far, see RFC 9000 appendix A.3.
+func DecodePacketNumber(largest, truncated uint64, length int) uint64 {
+	expected := largest + 1
+	win := uint64(1) << (8 * length)
+	hwin := win / 2
+	mask := win - 1
+	candidate := (expected &^ mask) | truncated
+	if candidate+hwin <= expected && candidate < (1<<62)-win {
+		return candidate + win
+	}
+	if candidate > expected+hwin && candidate >= win {
+		return candidate - win
+	}
+	return candidate
+}
+
+// AckRange is an inclusive range of acknowledged packet numbers.
+type AckRange struct {
+	Smallest uint64
+	Largest  uint64
+}
+
+// Frame is a frame of an Initial packet. Fields are filled according to Type,
+// frames other than ACK and CRYPTO are only kept in Raw.
+type Frame struct {
+	Type uint64
+	Raw  []byte
+
+	// ACK frames, ranges from the largest down.
+	AckDelay  uint64
+	AckRanges []AckRange
+	ECNCounts [3]uint64
+
+	// CRYPTO frames.
+	Offset uint64
+	Data   []byte
+}
+
+// ParseFrames splits the payload of an Initial packet into frames. Runs of
+// PADDING are returned as a single frame.
+func ParseFrames(payload []byte) ([]*Frame, error) {
+	var frames []*Frame
+	b := payload
+	for len(b) > 0 {
+		start := b
+		frameType, n, err := quicvarint.Parse(b)
+		if err != nil {
+			return nil, err
+		}
+		b = b[n:]
+		f := &Frame{Type: frameType}
+		switch frameType {
+		case FramePadding:
+			for len(b) > 0 && b[0] == 0 {
+				b = b[1:]
+			}
+		case FramePing:
+		case FrameAck, FrameAckECN:
+			var v [4]uint64
+			for i := range v {
+				if v[i], n, err = quicvarint.Parse(b); err != nil {
+					return nil, err
+				}
+				b = b[n:]
+			}
+			largest, rangeCount, first := v[0], v[2], v[3]
+			f.AckDelay = v[1]
+			if first > largest {
+				return nil, errors.New("invalid ACK frame")
+			}
+			f.AckRanges = append(f.AckRanges, AckRange{Smallest: largest - first, Largest: largest})
+			for range rangeCount {
+				var gap, length uint64
+				if gap, n, err = quicvarint.Parse(b); err != nil {
+					return nil, err
+				}
+				b = b[n:]
+				if length, n, err = quicvarint.Parse(b); err != nil {
+					return nil, err
+				}
+				b = b[n:]
+				smallest := f.AckRanges[len(f.AckRanges)-1].Smallest
+				if smallest < gap+2+length {
+					return nil, errors.New("invalid ACK frame")
+				}
+				l := smallest - gap - 2
+				f.AckRanges = append(f.AckRanges, AckRange{Smallest: l - length, Largest: l})
+			}
+			if frameType == FrameAckECN {
+				for i := range f.ECNCounts {
+					if f.ECNCounts[i], n, err = quicvarint.Parse(b); err != nil {
+						return nil, err
+					}
+					b = b[n:]
+				}
+			}
+		case FrameCrypto:
+			var length uint64
+			if f.Offset, n, err = quicvarint.Parse(b); err != nil {
+				return nil, err
+			}
+			b = b[n:]
+			if length, n, err = quicvarint.Parse(b); err != nil {
+				return nil, err
+			}
+			b = b[n:]
+			if uint64(len(b)) < length {
+				return nil, errors.New("invalid CRYPTO frame")
+			}
+			f.Data, b = b[:length], b[length:]
+		case FrameConnectionClose:
+			var v [3]uint64
+			for i := range v {
+				if v[i], n, err = quicvarint.Parse(b); err != nil {
+					return nil, err
+				}
+				b = b[n:]
+			}
+			if uint64(len(b)) < v[2] {
+				return nil, errors.New("invalid CONNECTION_CLOSE frame")
+			}
+			b = b[v[2]:]
+		default:
+			return nil, errors.New("unexpected frame type ", frameType, " in initial packet")
+		}
+		f.Raw = start[:len(start)-len(b)]
+		frames = append(frames, f)
+	}
+	return frames, nil
+}
+
+// AppendFrame encodes f and appends it to dst. ACK and CRYPTO frames are
+// encoded from their fields, others are copied from Raw.
+func AppendFrame(dst []byte, f *Frame) []byte {
+	switch f.Type {
+	case FrameAck, FrameAckECN:
+		dst = quicvarint.Append(dst, f.Type)
+		first := f.AckRanges[0]
+		dst = quicvarint.Append(dst, first.Largest)
+		dst = quicvarint.Append(dst, f.AckDelay)
+		dst = quicvarint.Append(dst, uint64(len(f.AckRanges)-1))
+		dst = quicvarint.Append(dst, first.Largest-first.Smallest)
+		for i := 1; i < len(f.AckRanges); i++ {
+			r := f.AckRanges[i]
+			dst = quicvarint.Append(dst, f.AckRanges[i-1].Smallest-r.Largest-2)
+			dst = quicvarint.Append(dst, r.Largest-r.Smallest)
+		}
+		if f.Type == FrameAckECN {
+			for _, c := range f.ECNCounts {
+				dst = quicvarint.Append(dst, c)
+			}
+		}
+		return dst
+	case FrameCrypto:
+		dst = quicvarint.Append(dst, f.Type)
+		dst = quicvarint.Append(dst, f.Offset)
+		dst = quicvarint.Append(dst, uint64(len(f.Data)))
+		return append(dst, f.Data...)
+	}
+	return append(dst, f.Raw...)
+}
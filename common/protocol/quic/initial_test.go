@@ -0,0 +1,100 @@
+package quic_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/GFW-knocker/Xray-core/common"
+	"github.com/GFW-knocker/Xray-core/common/protocol/quic"
+)
+
+func TestInitialPacket(t *testing.T) {
+	pkt, err := hex.DecodeString("cd0000000108f1fb7bcc78aa5e7203a8f86400421531fe825b19541876db6c55c38890cd73149d267a084afee6087304095417a3033df6a81bbb71d8512e7a3e16df1e277cae5df3182cb214b8fe982ba3fdffbaa9ffec474547d55945f0fddbeadfb0b5243890b2fa3da45169e2bd34ec04b2e29382f48d612b28432a559757504d158e9e505407a77dd34f4b60b8d3b555ee85aacd6648686802f4de25e7216b19e54c5f78e8a5963380c742d861306db4c16e4f7fc94957aa50b9578a0b61f1e406b2ad5f0cd3cd271c4d99476409797b0c3cb3efec256118912d4b7e4fd79d9cb9016b6e5eaa4f5e57b637b217755daf8968a4092bed0ed5413f5d04904b3a61e4064f9211b2629e5b52a89c7b19f37a713e41e27743ea6dfa736dfa1bb0a4b2bc8c8dc632c6ce963493a20c550e6fdb2475213665e9a85cfc394da9cec0cf41f0c8abed3fc83be5245b2b5aa5e825d29349f721d30774ef5bf965b540f3d8d98febe20956b1fc8fa047e10e7d2f921c9c6622389e02322e80621a1cf5264e245b7276966eb02932584e3f7038bd36aa908766ad3fb98344025dec18670d6db43a1c5daac00937fce7b7c7d61ff4e6efd01a2bdee0ee183108b926393df4f3d74bbcbb015f240e7e346b7d01c41111a401225ce3b095ab4623a5836169bf9599eeca79d1d2e9b2202b5960a09211e978058d6fc0484eff3e91ce4649a5e3ba15b906d334cf66e28d9ff575406e1ae1ac2febafd72870b6f5d58fc5fb949cb1f40feb7c1d9ce5e71b")
+	common.Must(err)
+
+	version, destConnID, _, err := quic.InitialHeader(pkt)
+	common.Must(err)
+	keys, err := quic.NewInitialKeys(version, destConnID, true)
+	common.Must(err)
+	p, rest, err := keys.Open(pkt)
+	common.Must(err)
+	if len(rest) != 0 {
+		t.Error("unexpected ", len(rest), " bytes after packet")
+	}
+
+	frames, err := quic.ParseFrames(p.Payload)
+	common.Must(err)
+	var payload []byte
+	for _, f := range frames {
+		payload = quic.AppendFrame(payload, f)
+		if f.Type == quic.FrameCrypto && !bytes.Contains(f.Data, []byte("www.google.com")) {
+			t.Error("server name not found in crypto frame")
+		}
+	}
+	if !bytes.Equal(payload, p.Payload) {
+		t.Error("frames are not re-encoded as is")
+	}
+
+	sealed := keys.Seal(nil, p)
+	if len(sealed) != keys.SealedSize(p) {
+		t.Error("expect sealed size ", keys.SealedSize(p), " but got ", len(sealed))
+	}
+	if !bytes.Equal(sealed, pkt[:len(sealed)]) {
+		t.Error("packet is not sealed back as is")
+	}
+}
+
+func TestInitialPacketRoundTrip(t *testing.T) {
+	connID := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	keys, err := quic.NewInitialKeys(1, connID, false)
+	common.Must(err)
+
+	var payload []byte
+	payload = quic.AppendFrame(payload, &quic.Frame{
+		Type:      quic.FrameAck,
+		AckDelay:  10,
+		AckRanges: []quic.AckRange{{Smallest: 7, Largest: 9}, {Smallest: 2, Largest: 4}, {Smallest: 0, Largest: 0}},
+	})
+	payload = quic.AppendFrame(payload, &quic.Frame{Type: quic.FrameCrypto, Offset: 100, Data: []byte("server hello")})
+	p := &quic.InitialPacket{
+		Version:         1,
+		DestConnID:      []byte{9, 9},
+		SrcConnID:       connID,
+		PacketNumber:    0x1234,
+		PacketNumberLen: 2,
+		Payload:         payload,
+	}
+	datagram := keys.Seal(nil, p)
+	datagram = append(datagram, 0xff, 0xff)
+
+	opened, rest, err := keys.Open(datagram)
+	common.Must(err)
+	if opened.PacketNumber != p.PacketNumber || opened.PacketNumberLen != 2 || !bytes.Equal(rest, []byte{0xff, 0xff}) {
+		t.Error("packet header is corrupted")
+	}
+	frames, err := quic.ParseFrames(opened.Payload)
+	common.Must(err)
+	if len(frames) != 2 {
+		t.Fatal("expect 2 frames but got ", len(frames))
+	}
+	if r := frames[0].AckRanges; len(r) != 3 || r[1] != (quic.AckRange{Smallest: 2, Largest: 4}) || r[2].Largest != 0 {
+		t.Error("unexpected ack ranges ", r)
+	}
+	if frames[1].Offset != 100 || string(frames[1].Data) != "server hello" {
+		t.Error("crypto frame is corrupted")
+	}
+
+	if _, _, err := keys.Open(append([]byte(nil), datagram[:40]...)); err == nil {
+		t.Error("expect error for truncated packet")
+	}
+}
+
+func TestDecodePacketNumber(t *testing.T) {
+	if pn := quic.DecodePacketNumber(0xa82f30ea, 0x9b32, 2); pn != 0xa82f9b32 {
+		t.Errorf("expect 0xa82f9b32 but got %#x", pn)
+	}
+	if pn := quic.DecodePacketNumber(0, 1, 1); pn != 1 {
+		t.Error("expect 1 but got ", pn)
+	}
+}
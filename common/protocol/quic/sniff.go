@@ -2,7 +2,6 @@ package quic
 
 import (
 	"crypto"
-	"crypto/aes"
 	"crypto/tls"
 	"encoding/binary"
 	"io"
@@ -17,7 +16,10 @@ import (
 )
 
 type SniffHeader struct {
-	domain string
+	domain     string
+	alpn       []string
+	tlsVersion uint16
+	ech        bool
 }
 
 func (s SniffHeader) Protocol() string {
@@ -28,6 +30,21 @@ func (s SniffHeader) Domain() string {
 	return s.domain
 }
 
+// ALPN returns the application protocols offered by the client.
+func (s SniffHeader) ALPN() []string {
+	return s.alpn
+}
+
+// TLSVersion returns the highest TLS version offered by the client.
+func (s SniffHeader) TLSVersion() uint16 {
+	return s.tlsVersion
+}
+
+// ECH returns whether the client hello carries an encrypted client hello.
+func (s SniffHeader) ECH() bool {
+	return s.ech
+}
+
 const (
 	versionDraft29 uint32 = 0xff00001d
 	version1       uint32 = 0x1
@@ -55,8 +72,6 @@ func SniffQUIC(b []byte) (*SniffHeader, error) {
 	cryptoLen := int32(0)
 	cryptoDataBuf := buf.NewWithSize(32767)
 	defer cryptoDataBuf.Release()
-	cache := buf.New()
-	defer cache.Release()
 
 	// Parse QUIC packets
 	for len(b) > 0 {
@@ -130,126 +145,33 @@ func SniffQUIC(b []byte) (*SniffHeader, error) {
 			continue
 		}
 
-		var salt []byte
-		if versionNumber == version1 {
-			salt = quicSalt
-		} else {
-			salt = quicSaltOld
-		}
-		initialSecret := hkdf.Extract(crypto.SHA256.New, destConnID, salt)
-		secret := hkdfExpandLabel(crypto.SHA256, initialSecret, []byte{}, "client in", crypto.SHA256.Size())
-		hpKey := hkdfExpandLabel(initialSuite.Hash, secret, []byte{}, "quic hp", initialSuite.KeyLen)
-		block, err := aes.NewCipher(hpKey)
+		keys, err := NewInitialKeys(versionNumber, destConnID, true)
 		if err != nil {
 			return nil, err
 		}
-
-		cache.Clear()
-		mask := cache.Extend(int32(block.BlockSize()))
-		block.Encrypt(mask, b[hdrLen+4:hdrLen+4+len(mask)])
-		b[0] ^= mask[0] & 0xf
-		packetNumberLength := int(b[0]&0x3 + 1)
-		for i := range packetNumberLength {
-			b[hdrLen+i] ^= mask[i+1]
-		}
-
-		key := hkdfExpandLabel(crypto.SHA256, secret, []byte{}, "quic key", 16)
-		iv := hkdfExpandLabel(crypto.SHA256, secret, []byte{}, "quic iv", 12)
-		cipher := AEADAESGCMTLS13(key, iv)
-
-		nonce := cache.Extend(int32(cipher.NonceSize()))
-		_, err = buffer.Read(nonce[len(nonce)-packetNumberLength:])
+		packet, _, err := keys.Open(b[:hdrLen+int(packetLen)])
 		if err != nil {
 			return nil, err
 		}
-
-		extHdrLen := hdrLen + packetNumberLength
-		data := b[extHdrLen : int(packetLen)+hdrLen]
-		decrypted, err := cipher.Open(b[extHdrLen:extHdrLen], nonce, data, b[:extHdrLen])
+		// Only PADDING, PING, ACK, CRYPTO and CONNECTION_CLOSE frames are permitted in initial packet.
+		// See https://www.rfc-editor.org/rfc/rfc9000.html#section-17.2.2-8
+		frames, err := ParseFrames(packet.Payload)
 		if err != nil {
-			return nil, err
+			return nil, errNotQuicInitial
 		}
-		buffer = buf.FromBytes(decrypted)
-		for !buffer.IsEmpty() {
-			frameType, _ := buffer.ReadByte()
-			for frameType == 0x0 && !buffer.IsEmpty() {
-				frameType, _ = buffer.ReadByte()
+		for _, frame := range frames {
+			if frame.Type != FrameCrypto {
+				continue
+			}
+			currentCryptoLen := int32(frame.Offset + uint64(len(frame.Data)))
+			if frame.Offset+uint64(len(frame.Data)) > uint64(cryptoDataBuf.Cap()) {
+				return nil, io.ErrShortBuffer
 			}
-			switch frameType {
-			case 0x00: // PADDING frame
-			case 0x01: // PING frame
-			case 0x02, 0x03: // ACK frame
-				if _, err = quicvarint.Read(buffer); err != nil { // Field: Largest Acknowledged
-					return nil, io.ErrUnexpectedEOF
-				}
-				if _, err = quicvarint.Read(buffer); err != nil { // Field: ACK Delay
-					return nil, io.ErrUnexpectedEOF
-				}
-				ackRangeCount, err := quicvarint.Read(buffer) // Field: ACK Range Count
-				if err != nil {
-					return nil, io.ErrUnexpectedEOF
-				}
-				if _, err = quicvarint.Read(buffer); err != nil { // Field: First ACK Range
-					return nil, io.ErrUnexpectedEOF
-				}
-				for i := 0; i < int(ackRangeCount); i++ { // Field: ACK Range
-					if _, err = quicvarint.Read(buffer); err != nil { // Field: ACK Range -> Gap
-						return nil, io.ErrUnexpectedEOF
-					}
-					if _, err = quicvarint.Read(buffer); err != nil { // Field: ACK Range -> ACK Range Length
-						return nil, io.ErrUnexpectedEOF
-					}
-				}
-				if frameType == 0x03 {
-					if _, err = quicvarint.Read(buffer); err != nil { // Field: ECN Counts -> ECT0 Count
-						return nil, io.ErrUnexpectedEOF
-					}
-					if _, err = quicvarint.Read(buffer); err != nil { // Field: ECN Counts -> ECT1 Count
-						return nil, io.ErrUnexpectedEOF
-					}
-					if _, err = quicvarint.Read(buffer); err != nil { //nolint:misspell // Field: ECN Counts -> ECT-CE Count
-						return nil, io.ErrUnexpectedEOF
-					}
-				}
-			case 0x06: // CRYPTO frame, we will use this frame
-				offset, err := quicvarint.Read(buffer) // Field: Offset
-				if err != nil {
-					return nil, io.ErrUnexpectedEOF
-				}
-				length, err := quicvarint.Read(buffer) // Field: Length
-				if err != nil || length > uint64(buffer.Len()) {
-					return nil, io.ErrUnexpectedEOF
-				}
-				currentCryptoLen := int32(offset + length)
-				if cryptoLen < currentCryptoLen {
-					if cryptoDataBuf.Cap() < currentCryptoLen {
-						return nil, io.ErrShortBuffer
-					}
-					cryptoDataBuf.Extend(currentCryptoLen - cryptoLen)
-					cryptoLen = currentCryptoLen
-				}
-				if _, err := buffer.Read(cryptoDataBuf.BytesRange(int32(offset), currentCryptoLen)); err != nil { // Field: Crypto Data
-					return nil, io.ErrUnexpectedEOF
-				}
-			case 0x1c: // CONNECTION_CLOSE frame, only 0x1c is permitted in initial packet
-				if _, err = quicvarint.Read(buffer); err != nil { // Field: Error Code
-					return nil, io.ErrUnexpectedEOF
-				}
-				if _, err = quicvarint.Read(buffer); err != nil { // Field: Frame Type
-					return nil, io.ErrUnexpectedEOF
-				}
-				length, err := quicvarint.Read(buffer) // Field: Reason Phrase Length
-				if err != nil {
-					return nil, io.ErrUnexpectedEOF
-				}
-				if _, err := buffer.ReadBytes(int32(length)); err != nil { // Field: Reason Phrase
-					return nil, io.ErrUnexpectedEOF
-				}
-			default:
-				// Only above frame types are permitted in initial packet.
-				// See https://www.rfc-editor.org/rfc/rfc9000.html#section-17.2.2-8
-				return nil, errNotQuicInitial
+			if cryptoLen < currentCryptoLen {
+				cryptoDataBuf.Extend(currentCryptoLen - cryptoLen)
+				cryptoLen = currentCryptoLen
 			}
+			copy(cryptoDataBuf.BytesRange(int32(frame.Offset), currentCryptoLen), frame.Data)
 		}
 
 		tlsHdr := &ptls.SniffHeader{}
@@ -260,7 +182,12 @@ func SniffQUIC(b []byte) (*SniffHeader, error) {
 			b = restPayload
 			continue
 		}
-		return &SniffHeader{domain: tlsHdr.Domain()}, nil
+		return &SniffHeader{
+			domain:     tlsHdr.Domain(),
+			alpn:       tlsHdr.ALPN(),
+			tlsVersion: tlsHdr.TLSVersion(),
+			ech:        tlsHdr.ECH(),
+		}, nil
 	}
 	// All payload is parsed as valid QUIC packets, but we need more packets for crypto data to read client hello.
 	return nil, protocol.ErrProtoNeedMoreData
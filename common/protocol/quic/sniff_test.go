@@ -17,6 +17,12 @@ func TestSniffQUIC(t *testing.T) {
 	if err != nil || quicHdr.Domain() != "www.google.com" {
 		t.Error("failed")
 	}
+	if alpn := quicHdr.ALPN(); len(alpn) != 1 || alpn[0] != "h3" {
+		t.Error("unexpected ALPN ", alpn)
+	}
+	if quicHdr.TLSVersion() != 0x0304 {
+		t.Error("unexpected TLS version ", quicHdr.TLSVersion())
+	}
 }
 
 func TestSniffQUICComplex(t *testing.T) {
@@ -9,7 +9,13 @@ import (
 )
 
 type SniffHeader struct {
-	domain string
+	domain     string
+	alpn       []string
+	tlsVersion uint16
+	ech        bool
+
+	// serverNameOffset is the position of domain in the client hello message.
+	serverNameOffset int
 }
 
 func (h *SniffHeader) Protocol() string {
@@ -20,6 +26,21 @@ func (h *SniffHeader) Domain() string {
 	return h.domain
 }
 
+// ALPN returns the application protocols offered by the client.
+func (h *SniffHeader) ALPN() []string {
+	return h.alpn
+}
+
+// TLSVersion returns the highest TLS version offered by the client.
+func (h *SniffHeader) TLSVersion() uint16 {
+	return h.tlsVersion
+}
+
+// ECH returns whether the client hello carries an encrypted client hello.
+func (h *SniffHeader) ECH() bool {
+	return h.ech
+}
+
 var (
 	errNotTLS         = errors.New("not TLS header")
 	errNotClientHello = errors.New("not client hello")
@@ -29,46 +50,83 @@ func IsValidTLSVersion(major, minor byte) bool {
 	return major == 3
 }
 
-// ReadClientHello returns server name (if any) from TLS client hello message.
-// https://github.com/golang/go/blob/master/src/crypto/tls/handshake_messages.go#L300
-func ReadClientHello(data []byte, h *SniffHeader) error {
+// Client hello extension types used by this package.
+const (
+	ExtensionServerName uint16 = 0x00
+	ExtensionALPN       uint16 = 0x10
+	ExtensionVersions   uint16 = 0x2b
+	ExtensionKeyShare   uint16 = 0x33
+	ExtensionECH        uint16 = 0xfe0d
+)
+
+// isGREASE returns whether v is one of the reserved values of RFC 8701.
+func isGREASE(v uint16) bool {
+	return v&0x0f0f == 0x0a0a && v>>8 == v&0xff
+}
+
+// Extension is the position of a client hello extension, header included.
+type Extension struct {
+	Type  uint16
+	Start int
+	End   int
+}
+
+// readExtensions skips the fixed part of a client hello message and returns
+// its extensions block.
+func readExtensions(data []byte) ([]byte, error) {
 	if len(data) < 42 {
-		return common.ErrNoClue
+		return nil, common.ErrNoClue
 	}
 	sessionIDLen := int(data[38])
 	if sessionIDLen > 32 || len(data) < 39+sessionIDLen {
-		return common.ErrNoClue
+		return nil, common.ErrNoClue
 	}
 	data = data[39+sessionIDLen:]
 	if len(data) < 2 {
-		return common.ErrNoClue
+		return nil, common.ErrNoClue
 	}
 	// cipherSuiteLen is the number of bytes of cipher suite numbers. Since
 	// they are uint16s, the number must be even.
 	cipherSuiteLen := int(data[0])<<8 | int(data[1])
 	if cipherSuiteLen%2 == 1 || len(data) < 2+cipherSuiteLen {
-		return errNotClientHello
+		return nil, errNotClientHello
 	}
 	data = data[2+cipherSuiteLen:]
 	if len(data) < 1 {
-		return common.ErrNoClue
+		return nil, common.ErrNoClue
 	}
 	compressionMethodsLen := int(data[0])
 	if len(data) < 1+compressionMethodsLen {
-		return common.ErrNoClue
+		return nil, common.ErrNoClue
 	}
 	data = data[1+compressionMethodsLen:]
 
 	if len(data) < 2 {
-		return errNotClientHello
+		return nil, errNotClientHello
 	}
 
 	extensionsLength := int(data[0])<<8 | int(data[1])
 	data = data[2:]
 	if extensionsLength != len(data) {
-		return errNotClientHello
+		return nil, errNotClientHello
+	}
+	return data, nil
+}
+
+// ReadClientHello returns server name (if any) from TLS client hello message,
+// along with the offered application protocols and versions.
+// https://github.com/golang/go/blob/master/src/crypto/tls/handshake_messages.go#L300
+func ReadClientHello(data []byte, h *SniffHeader) error {
+	message := data
+	if len(message) >= 6 {
+		h.tlsVersion = uint16(message[4])<<8 | uint16(message[5])
+	}
+	data, err := readExtensions(data)
+	if err != nil {
+		return err
 	}
 
+	hasServerName := false
 	for len(data) != 0 {
 		if len(data) < 4 {
 			return errNotClientHello
@@ -80,7 +138,11 @@ func ReadClientHello(data []byte, h *SniffHeader) error {
 			return errNotClientHello
 		}
 
-		if extension == 0x00 { /* extensionServerName */
+		switch extension {
+		case ExtensionServerName:
+			if hasServerName {
+				break
+			}
 			d := data[:length]
 			if len(d) < 2 {
 				return errNotClientHello
@@ -117,15 +179,65 @@ func ReadClientHello(data []byte, h *SniffHeader) error {
 					}
 					serverName := string(d[:nameLen])
 					h.domain = serverName
-					return nil
+					h.serverNameOffset = len(message) - len(data) + length - len(d)
+					hasServerName = true
+					break
 				}
 				d = d[nameLen:]
 			}
+		// ALPN and versions only refine routing, so malformed ones are
+		// skipped rather than failing the sniffing of the server name.
+		case ExtensionALPN:
+			if alpn, ok := parseALPN(data[:length]); ok {
+				h.alpn = alpn
+			}
+		case ExtensionVersions:
+			if version, ok := parseVersions(data[:length]); ok && version > h.tlsVersion {
+				h.tlsVersion = version
+			}
+		case ExtensionECH:
+			h.ech = true
 		}
 		data = data[length:]
 	}
 
-	return errNotTLS
+	if !hasServerName {
+		return errNotTLS
+	}
+	return nil
+}
+
+// parseALPN returns the protocols of an ALPN extension.
+func parseALPN(d []byte) ([]string, bool) {
+	if len(d) < 2 || int(d[0])<<8|int(d[1]) != len(d)-2 {
+		return nil, false
+	}
+	var alpn []string
+	for d = d[2:]; len(d) > 0; {
+		protoLen := int(d[0])
+		if protoLen == 0 || len(d) < 1+protoLen {
+			return nil, false
+		}
+		alpn = append(alpn, string(d[1:1+protoLen]))
+		d = d[1+protoLen:]
+	}
+	return alpn, true
+}
+
+// parseVersions returns the highest version, other than GREASE ones, of a
+// supported_versions extension.
+func parseVersions(d []byte) (uint16, bool) {
+	if len(d) < 1 || int(d[0]) != len(d)-1 || d[0]%2 == 1 {
+		return 0, false
+	}
+	var highest uint16
+	for d = d[1:]; len(d) > 0; d = d[2:] {
+		version := uint16(d[0])<<8 | uint16(d[1])
+		if !isGREASE(version) && version > highest {
+			highest = version
+		}
+	}
+	return highest, true
 }
 
 func SniffTLS(b []byte) (*SniffHeader, error) {
@@ -151,3 +263,43 @@ func SniffTLS(b []byte) (*SniffHeader, error) {
 	}
 	return nil, err
 }
+
+// FindServerName returns the position of the server name in b, which must
+// start with a TLS record carrying a client hello.
+func FindServerName(b []byte) (start, end int, err error) {
+	h, err := SniffTLS(b)
+	if err != nil {
+		return 0, 0, err
+	}
+	start = 5 + h.serverNameOffset
+	return start, start + len(h.domain), nil
+}
+
+// FindExtensions returns the positions of all extensions of the client hello
+// in b, which must start with a TLS record carrying it.
+func FindExtensions(b []byte) ([]Extension, error) {
+	if _, err := SniffTLS(b); err != nil {
+		return nil, err
+	}
+	headerLen := int(binary.BigEndian.Uint16(b[3:5]))
+	data, err := readExtensions(b[5 : 5+headerLen])
+	if err != nil {
+		return nil, err
+	}
+	offset := 5 + headerLen - len(data)
+	var extensions []Extension
+	for len(data) >= 4 {
+		length := int(data[2])<<8 | int(data[3])
+		if len(data) < 4+length {
+			return nil, errNotClientHello
+		}
+		extensions = append(extensions, Extension{
+			Type:  uint16(data[0])<<8 | uint16(data[1]),
+			Start: offset,
+			End:   offset + 4 + length,
+		})
+		offset += 4 + length
+		data = data[4+length:]
+	}
+	return extensions, nil
+}
@@ -1,6 +1,8 @@
 package tls_test
 
 import (
+	"encoding/binary"
+	"reflect"
 	"testing"
 
 	. "github.com/GFW-knocker/Xray-core/common/protocol/tls"
@@ -156,6 +158,81 @@ func TestTLSHeaders(t *testing.T) {
 			if header.Domain() != test.domain {
 				t.Error("expect domain ", test.domain, " but got ", header.Domain())
 			}
+			start, end, err := FindServerName(test.input)
+			if err != nil || string(test.input[start:end]) != test.domain {
+				t.Error("expect server name at ", start, "-", end, " to be ", test.domain)
+			}
 		}
 	}
 }
+
+func buildClientHello(extensions ...[]byte) []byte {
+	var exts []byte
+	for _, e := range extensions {
+		exts = append(exts, e...)
+	}
+	body := []byte{0x03, 0x03}               // legacy version
+	body = append(body, make([]byte, 32)...) // random
+	body = append(body, 0)                   // session id
+	body = append(body, 0x00, 0x02, 0x13, 0x01, 0x01, 0x00)
+	body = binary.BigEndian.AppendUint16(body, uint16(len(exts)))
+	body = append(body, exts...)
+
+	hello := []byte{0x01, 0x00}
+	hello = binary.BigEndian.AppendUint16(hello, uint16(len(body)))
+	hello = append(hello, body...)
+	record := []byte{0x16, 0x03, 0x01}
+	record = binary.BigEndian.AppendUint16(record, uint16(len(hello)))
+	return append(record, hello...)
+}
+
+func extension(t uint16, data ...byte) []byte {
+	e := binary.BigEndian.AppendUint16(nil, t)
+	e = binary.BigEndian.AppendUint16(e, uint16(len(data)))
+	return append(e, data...)
+}
+
+func TestTLSClientHelloMetadata(t *testing.T) {
+	serverName := extension(ExtensionServerName, 0x00, 0x0e, 0x00, 0x00, 0x0b, 'e', 'x', 'a', 'm', 'p', 'l', 'e', '.', 'c', 'o', 'm')
+	alpn := extension(ExtensionALPN, 0x00, 0x0c, 0x02, 'h', '2', 0x08, 'h', 't', 't', 'p', '/', '1', '.', '1')
+	versions := extension(ExtensionVersions, 0x06, 0x3a, 0x3a, 0x03, 0x04, 0x03, 0x03)
+	ech := extension(ExtensionECH, 0x00, 0x01, 0x02)
+
+	header, err := SniffTLS(buildClientHello(serverName, alpn, versions, ech))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Domain() != "example.com" {
+		t.Error("unexpected domain ", header.Domain())
+	}
+	if !reflect.DeepEqual(header.ALPN(), []string{"h2", "http/1.1"}) {
+		t.Error("unexpected ALPN ", header.ALPN())
+	}
+	if header.TLSVersion() != 0x0304 {
+		t.Error("unexpected TLS version ", header.TLSVersion())
+	}
+	if !header.ECH() {
+		t.Error("expect ECH")
+	}
+
+	header, err = SniffTLS(buildClientHello(alpn, serverName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.TLSVersion() != 0x0303 || header.ECH() || len(header.ALPN()) != 2 {
+		t.Error("unexpected metadata ", header.TLSVersion(), header.ECH(), header.ALPN())
+	}
+
+	brokenALPN := extension(ExtensionALPN, 0x00, 0x05, 0x02, 'h', '2')
+	brokenVersions := extension(ExtensionVersions, 0x03, 0x3a, 0x3a, 0x03)
+	header, err = SniffTLS(buildClientHello(serverName, brokenALPN, brokenVersions))
+	if err != nil {
+		t.Fatal("expect malformed ALPN and versions to be skipped, got ", err)
+	}
+	if header.Domain() != "example.com" {
+		t.Error("unexpected domain ", header.Domain())
+	}
+	if len(header.ALPN()) != 0 || header.TLSVersion() != 0x0303 {
+		t.Error("unexpected metadata ", header.TLSVersion(), header.ALPN())
+	}
+}
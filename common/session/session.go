@@ -38,6 +38,11 @@ type Inbound struct {
 	Source net.Destination
 	// Gateway address.
 	Gateway net.Destination
+	// Local address the inbound connection was accepted on. Unlike Gateway,
+	// it is the actual address of TCP connections when listening on all
+	// interfaces. UDP packets only carry it when the socket receives their
+	// original destination (TPROXY), otherwise it is the listen address.
+	Local net.Destination
 	// Tag of the inbound proxy that handles the connection.
 	Tag string
 	// Name of the inbound proxy that handles the connection.
@@ -70,6 +75,9 @@ type Outbound struct {
 	// CanSpliceCopy is a property for this connection
 	// 1 = can, 2 = after processing protocol info should be able to, 3 = cannot
 	CanSpliceCopy int
+	// Names of the fragment and noise profiles the routing rule asks the outbound to use.
+	FragmentProfile string
+	NoiseProfile    string
 }
 
 // SniffingRequest controls the behavior of content sniffing.
@@ -86,6 +94,9 @@ type Content struct {
 	// Protocol of current content.
 	Protocol string
 
+	// TLS is the client hello metadata of current content, if sniffed out.
+	TLS *TLSInfo
+
 	SniffingRequest SniffingRequest
 
 	Attributes map[string]string
@@ -93,6 +104,18 @@ type Content struct {
 	SkipDNSResolve bool
 }
 
+// TLSInfo is the metadata of a TLS client hello, carried over TLS or QUIC.
+type TLSInfo struct {
+	// ALPN is the list of offered application protocols.
+	ALPN []string
+	// Version is the highest offered TLS version.
+	Version uint16
+	// ECH is whether the client hello carries an encrypted client hello.
+	ECH bool
+	// SNI is whether the client hello carries a server name.
+	SNI bool
+}
+
 // Sockopt is the settings for socket connection.
 type Sockopt struct {
 	// Mark of the socket connection.
@@ -5,6 +5,7 @@ import (
 	"github.com/GFW-knocker/Xray-core/common/net"
 	"github.com/GFW-knocker/Xray-core/common/serial"
 	"github.com/GFW-knocker/Xray-core/features"
+	"github.com/miekg/dns"
 )
 
 // IPOption is an object for IP query options.
@@ -24,6 +25,17 @@ type Client interface {
 	LookupIP(domain string, option IPOption) ([]net.IP, uint32, error)
 }
 
+// RecordClient is a Client which also looks up records of other types than A
+// and AAAA, such as HTTPS, SVCB, TXT and SRV.
+//
+// xray:api:beta
+type RecordClient interface {
+	Client
+
+	// LookupRecords returns the answers of type qtype for the given domain, with their TTL.
+	LookupRecords(domain string, qtype uint16) ([]dns.RR, uint32, error)
+}
+
 // ClientType returns the type of Client interface. Can be used for implementing common.HasType.
 //
 // xray:api:beta
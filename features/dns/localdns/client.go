@@ -1,8 +1,10 @@
 package localdns
 
 import (
+	"github.com/GFW-knocker/Xray-core/common/errors"
 	"github.com/GFW-knocker/Xray-core/common/net"
 	"github.com/GFW-knocker/Xray-core/features/dns"
+	mdns "github.com/miekg/dns"
 )
 
 // Client is an implementation of dns.Client, which queries localhost for DNS.
@@ -60,6 +62,49 @@ func (*Client) LookupIP(host string, option dns.IPOption) ([]net.IP, uint32, err
 	return nil, 0, dns.ErrEmptyResponse
 }
 
+// LookupRecords implements RecordClient. The system resolver only looks up
+// TXT and SRV records, without their TTL.
+func (*Client) LookupRecords(domain string, qtype uint16) ([]mdns.RR, uint32, error) {
+	name := mdns.Fqdn(domain)
+	header := mdns.RR_Header{
+		Name:   name,
+		Rrtype: qtype,
+		Class:  mdns.ClassINET,
+		Ttl:    dns.DefaultTTL,
+	}
+	var records []mdns.RR
+	switch qtype {
+	case mdns.TypeTXT:
+		txts, err := net.LookupTXT(domain)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, txt := range txts {
+			records = append(records, &mdns.TXT{Hdr: header, Txt: []string{txt}})
+		}
+	case mdns.TypeSRV:
+		_, srvs, err := net.LookupSRV("", "", domain)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, srv := range srvs {
+			records = append(records, &mdns.SRV{
+				Hdr:      header,
+				Priority: srv.Priority,
+				Weight:   srv.Weight,
+				Port:     srv.Port,
+				Target:   srv.Target,
+			})
+		}
+	default:
+		return nil, 0, errors.New("system DNS does not look up records of type ", mdns.Type(qtype))
+	}
+	if len(records) == 0 {
+		return nil, 0, dns.ErrEmptyResponse
+	}
+	return records, dns.DefaultTTL, nil
+}
+
 // New create a new dns.Client that queries localhost for DNS.
 func New() *Client {
 	return &Client{}
@@ -13,6 +13,13 @@ type Observatory interface {
 	GetObservation(ctx context.Context) (proto.Message, error)
 }
 
+// OutcomeObserver is an Observatory which also learns from real connections
+// through outbounds. Outbound handlers report the outcome of each connection
+// to it, with a nil err for success.
+type OutcomeObserver interface {
+	ReportOutcome(tag string, err error)
+}
+
 func ObservatoryType() interface{} {
 	return (*Observatory)(nil)
 }
@@ -47,6 +47,10 @@ type SystemStats struct {
 	OutboundUplink bool
 	// Whether or not to enable stat counter for downlink traffic in outbound handlers.
 	OutboundDownlink bool
+	// Whether or not to enable stat counter for hits of tagged routing rules.
+	RuleHits bool
+	// Whether or not to enable stat counter for active connections of outbounds.
+	OutboundConnections bool
 }
 
 // System contains policy settings at system level.
@@ -17,6 +17,12 @@ type Context interface {
 	// GetSourcePort returns the source port of the connection.
 	GetSourcePort() net.Port
 
+	// GetLocalIPs returns the local IPs the connection was accepted on.
+	GetLocalIPs() []net.IP
+
+	// GetLocalPort returns the local port the connection was accepted on.
+	GetLocalPort() net.Port
+
 	// GetTargetIPs returns the target IP of the connection or resolved IPs of target domain.
 	GetTargetIPs() []net.IP
 
@@ -32,6 +38,18 @@ type Context interface {
 	// GetProtocol returns the protocol from the connection content, if sniffed out.
 	GetProtocol() string
 
+	// GetALPN returns the application protocols offered in the sniffed TLS client hello.
+	GetALPN() []string
+
+	// GetTLSVersion returns the highest TLS version offered in the sniffed TLS client hello, or 0.
+	GetTLSVersion() uint16
+
+	// GetECH returns whether the sniffed TLS client hello carries an encrypted client hello.
+	GetECH() bool
+
+	// GetSNI returns whether the sniffed TLS client hello carries a server name.
+	GetSNI() bool
+
 	// GetUser returns the user email from the connection content, if exists.
 	GetUser() string
 
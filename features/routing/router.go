@@ -33,6 +33,34 @@ type Route interface {
 
 	// GetRuleTag returns the matching rule tag for debugging if exists
 	GetRuleTag() string
+
+	// GetFragmentProfile returns the name of the fragment profile the matching rule asks the outbound to use, if any.
+	GetFragmentProfile() string
+
+	// GetNoiseProfile returns the name of the noise profile the matching rule asks the outbound to use, if any.
+	GetNoiseProfile() string
+}
+
+// RuleTrace is the evaluation of a routing rule while picking a route.
+type RuleTrace struct {
+	// Index is the position of the rule in the rule list.
+	Index int
+	// RuleTag is the tag of the rule, if any.
+	RuleTag string
+	// Matched is whether all conditions of the rule matched.
+	Matched bool
+	// FailedConditions are the names of the conditions that did not match.
+	FailedConditions []string
+	// Retry is set when the rule is evaluated again with the resolved IPs of
+	// the target domain.
+	Retry bool
+}
+
+// RouteTracer is a Router that can explain how a route is picked.
+type RouteTracer interface {
+	// TraceRoute picks a route like PickRoute, and returns the evaluation of
+	// every rule tried, even if no rule matches. It does not count rule hits.
+	TraceRoute(ctx Context) (Route, []*RuleTrace, error)
 }
 
 // RouterType return the type of Router interface. Can be used to implement common.HasType.
@@ -44,6 +44,28 @@ func (ctx *Context) GetSourcePort() net.Port {
 	return ctx.Inbound.Source.Port
 }
 
+// GetLocalIPs implements routing.Context.
+func (ctx *Context) GetLocalIPs() []net.IP {
+	if ctx.Inbound == nil || !ctx.Inbound.Local.IsValid() {
+		return nil
+	}
+	dest := ctx.Inbound.Local
+	// A listener on all interfaces tells nothing about the local IP.
+	if dest.Address.Family().IsDomain() || dest.Address.IP().IsUnspecified() {
+		return nil
+	}
+
+	return []net.IP{dest.Address.IP()}
+}
+
+// GetLocalPort implements routing.Context.
+func (ctx *Context) GetLocalPort() net.Port {
+	if ctx.Inbound == nil || !ctx.Inbound.Local.IsValid() {
+		return 0
+	}
+	return ctx.Inbound.Local.Port
+}
+
 // GetTargetIPs implements routing.Context.
 func (ctx *Context) GetTargetIPs() []net.IP {
 	if ctx.Outbound == nil || !ctx.Outbound.Target.IsValid() {
@@ -98,6 +120,38 @@ func (ctx *Context) GetProtocol() string {
 	return ctx.Content.Protocol
 }
 
+// GetALPN implements routing.Context.
+func (ctx *Context) GetALPN() []string {
+	if ctx.Content == nil || ctx.Content.TLS == nil {
+		return nil
+	}
+	return ctx.Content.TLS.ALPN
+}
+
+// GetTLSVersion implements routing.Context.
+func (ctx *Context) GetTLSVersion() uint16 {
+	if ctx.Content == nil || ctx.Content.TLS == nil {
+		return 0
+	}
+	return ctx.Content.TLS.Version
+}
+
+// GetECH implements routing.Context.
+func (ctx *Context) GetECH() bool {
+	if ctx.Content == nil || ctx.Content.TLS == nil {
+		return false
+	}
+	return ctx.Content.TLS.ECH
+}
+
+// GetSNI implements routing.Context.
+func (ctx *Context) GetSNI() bool {
+	if ctx.Content == nil || ctx.Content.TLS == nil {
+		return false
+	}
+	return ctx.Content.TLS.SNI
+}
+
 // GetUser implements routing.Context.
 func (ctx *Context) GetUser() string {
 	if ctx.Inbound == nil || ctx.Inbound.User == nil {
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/GFW-knocker/Xray-core/app/commander"
+	dnsservice "github.com/GFW-knocker/Xray-core/app/dns/command"
 	loggerservice "github.com/GFW-knocker/Xray-core/app/log/command"
 	observatoryservice "github.com/GFW-knocker/Xray-core/app/observatory/command"
 	handlerservice "github.com/GFW-knocker/Xray-core/app/proxyman/command"
@@ -39,6 +40,8 @@ func (c *APIConfig) Build() (*commander.Config, error) {
 			services = append(services, serial.ToTypedMessage(&observatoryservice.Config{}))
 		case "routingservice":
 			services = append(services, serial.ToTypedMessage(&routerservice.Config{}))
+		case "dnsservice":
+			services = append(services, serial.ToTypedMessage(&dnsservice.Config{}))
 		}
 	}
 
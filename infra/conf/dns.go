@@ -197,6 +197,11 @@ type DNSConfig struct {
 	DisableFallback        bool                `json:"disableFallback"`
 	DisableFallbackIfMatch bool                `json:"disableFallbackIfMatch"`
 	UseSystemHosts         bool                `json:"useSystemHosts"`
+	CacheFile              string              `json:"cacheFile"`
+	ServeStale             bool                `json:"serveStale"`
+	ServeExpiredTTL        uint32              `json:"serveExpiredTTL"`
+	Prefetch               bool                `json:"prefetch"`
+	QueryStats             bool                `json:"queryStats"`
 }
 
 type HostAddress struct {
@@ -394,6 +399,15 @@ func (c *DNSConfig) Build() (*dns.Config, error) {
 		DisableFallback:        c.DisableFallback,
 		DisableFallbackIfMatch: c.DisableFallbackIfMatch,
 		QueryStrategy:          resolveQueryStrategy(c.QueryStrategy),
+		CacheFile:              c.CacheFile,
+		ServeStale:             c.ServeStale,
+		ServeExpiredTtl:        c.ServeExpiredTTL,
+		Prefetch:               c.Prefetch,
+		QueryStats:             c.QueryStats,
+	}
+	if config.ServeStale && config.ServeExpiredTtl == 0 {
+		// expired answers are never served forever
+		config.ServeExpiredTtl = 86400
 	}
 
 	if c.ClientIP != nil {
@@ -38,3 +38,23 @@ func (c *DNSOutboundConfig) Build() (proto.Message, error) {
 	config.BlockTypes = c.BlockTypes
 	return config, nil
 }
+
+type DNSInboundConfig struct {
+	Network   *NetworkList `json:"network"`
+	UserLevel uint32       `json:"userLevel"`
+	DohPath   string       `json:"dohPath"`
+}
+
+func (c *DNSInboundConfig) Build() (proto.Message, error) {
+	if len(c.DohPath) > 0 && c.DohPath[0] != '/' {
+		return nil, errors.New(`"dohPath" must start with "/": `, c.DohPath)
+	}
+	config := &dns.ServerConfig{
+		UserLevel: c.UserLevel,
+		DohPath:   c.DohPath,
+	}
+	if c.Network != nil {
+		config.Networks = c.Network.Build()
+	}
+	return config, nil
+}
@@ -32,3 +32,30 @@ func TestDnsProxyConfig(t *testing.T) {
 		},
 	})
 }
+
+func TestDnsInboundConfig(t *testing.T) {
+	creator := func() Buildable {
+		return new(DNSInboundConfig)
+	}
+
+	runMultiTestCase(t, []TestCase{
+		{
+			Input:  `{}`,
+			Parser: loadJSON(creator),
+			Output: &dns.ServerConfig{},
+		},
+		{
+			Input: `{
+				"network": "tcp",
+				"userLevel": 1,
+				"dohPath": "/dns-query"
+			}`,
+			Parser: loadJSON(creator),
+			Output: &dns.ServerConfig{
+				UserLevel: 1,
+				Networks:  []net.Network{net.Network_TCP},
+				DohPath:   "/dns-query",
+			},
+		},
+	})
+}
@@ -103,5 +103,39 @@ func TestDNSConfigParsing(t *testing.T) {
 				DisableFallback: true,
 			},
 		},
+		{
+			Input: `{
+				"cacheFile": "dns.cache",
+				"serveStale": true,
+				"serveExpiredTTL": 86400,
+				"prefetch": true
+			}`,
+			Parser: parserCreator(),
+			Output: &dns.Config{
+				CacheFile:       "dns.cache",
+				ServeStale:      true,
+				ServeExpiredTtl: 86400,
+				Prefetch:        true,
+			},
+		},
+		{
+			Input: `{
+				"serveStale": true
+			}`,
+			Parser: parserCreator(),
+			Output: &dns.Config{
+				ServeStale:      true,
+				ServeExpiredTtl: 86400,
+			},
+		},
+		{
+			Input: `{
+				"queryStats": true
+			}`,
+			Parser: parserCreator(),
+			Output: &dns.Config{
+				QueryStats: true,
+			},
+		},
 	})
 }
@@ -1,45 +1,271 @@
 package conf
 
 import (
-	"encoding/base64"
-	"encoding/hex"
+	"encoding/json"
 	"net"
+	"runtime"
 	"strconv"
 	"strings"
 
 	"github.com/GFW-knocker/Xray-core/common/errors"
 	v2net "github.com/GFW-knocker/Xray-core/common/net"
+	"github.com/GFW-knocker/Xray-core/common/noise"
 	"github.com/GFW-knocker/Xray-core/common/protocol"
+	"github.com/GFW-knocker/Xray-core/common/serial"
 	"github.com/GFW-knocker/Xray-core/proxy/freedom"
+	"github.com/GFW-knocker/Xray-core/transport/internet"
 	"google.golang.org/protobuf/proto"
 )
 
 type FreedomConfig struct {
-	DomainStrategy string    `json:"domainStrategy"`
-	Redirect       string    `json:"redirect"`
-	UserLevel      uint32    `json:"userLevel"`
-	Fragment       *Fragment `json:"fragment"`
-	Noise          *Noise    `json:"noise"`
-	Noises         []*Noise  `json:"noises"`
-	NoiseKeepAlive uint32    `json:"noiseKeepAlive"`
-	ProxyProtocol  uint32    `json:"proxyProtocol"`
+	DomainStrategy   string                  `json:"domainStrategy"`
+	Redirect         string                  `json:"redirect"`
+	UserLevel        uint32                  `json:"userLevel"`
+	Fragment         *Fragment               `json:"fragment"`
+	Noise            *Noise                  `json:"noise"`
+	Noises           []*Noise                `json:"noises"`
+	NoiseKeepAlive   uint32                  `json:"noiseKeepAlive"`
+	ProxyProtocol    uint32                  `json:"proxyProtocol"`
+	ClientHello      *ClientHello            `json:"clientHello"`
+	QuicFragment     *QuicFragment           `json:"quicFragment"`
+	AdaptiveFragment *AdaptiveFragment       `json:"adaptiveFragment"`
+	FragmentProfiles map[string]*Fragment    `json:"fragmentProfiles"`
+	NoiseProfiles    map[string]*NoiseConfig `json:"noiseProfiles"`
 }
 
+var fragmentStrategyConfigLoader = NewJSONConfigLoader(ConfigCreatorCache{
+	internet.FragmentStrategyFakeHost:   func() interface{} { return new(fragmentEmptyConfig) },
+	internet.FragmentStrategyTCPSegment: func() interface{} { return new(fragmentEmptyConfig) },
+	internet.FragmentStrategyTLSRecord:  func() interface{} { return new(fragmentTLSRecordConfig) },
+	internet.FragmentStrategySNI:        func() interface{} { return new(fragmentSNIConfig) },
+	internet.FragmentStrategyMixed:      func() interface{} { return new(fragmentEmptyConfig) },
+}, "", "")
+
 type Fragment struct {
-	Packets      string `json:"packets"`
-	Length       string `json:"length"`
-	Interval     string `json:"interval"`
-	Host1_header string `json:"host1_header"`
-	Host1_domain string `json:"host1_domain"`
-	Host2_header string `json:"host2_header"`
-	Host2_domain string `json:"host2_domain"`
+	Packets      string           `json:"packets"`
+	Length       string           `json:"length"`
+	Interval     string           `json:"interval"`
+	Host1_header string           `json:"host1_header"`
+	Host1_domain string           `json:"host1_domain"`
+	Host2_header string           `json:"host2_header"`
+	Host2_domain string           `json:"host2_domain"`
+	Strategy     string           `json:"strategy"`
+	Settings     *json.RawMessage `json:"settings"`
+	Fake         *FragmentFake    `json:"fake"`
+}
+
+type FragmentFake struct {
+	TTL        uint32 `json:"ttl"`
+	ServerName string `json:"serverName"`
+}
+
+func (c *Fragment) Build() (*internet.Fragment, error) {
+	config := new(internet.Fragment)
+	var err, err2 error
+
+	config.FakeHost = false
+
+	switch strings.ToLower(c.Packets) {
+	case "tlshello":
+		// TLS Hello Fragmentation (into multiple handshake messages)
+		config.PacketsFrom = 0
+		config.PacketsTo = 1
+	case "fakehost":
+		// fake host header with no fragmentation
+		config.PacketsFrom = 1
+		config.PacketsTo = 1
+		config.FakeHost = true
+	case "":
+		// TCP Segmentation (all packets)
+		config.PacketsFrom = 0
+		config.PacketsTo = 0
+	default:
+		// TCP Segmentation (range)
+		packetsFromTo := strings.Split(c.Packets, "-")
+		if len(packetsFromTo) == 2 {
+			config.PacketsFrom, err = strconv.ParseUint(packetsFromTo[0], 10, 64)
+			config.PacketsTo, err2 = strconv.ParseUint(packetsFromTo[1], 10, 64)
+		} else {
+			config.PacketsFrom, err = strconv.ParseUint(packetsFromTo[0], 10, 64)
+			config.PacketsTo = config.PacketsFrom
+		}
+		if err != nil {
+			return nil, errors.New("Invalid PacketsFrom").Base(err)
+		}
+		if err2 != nil {
+			return nil, errors.New("Invalid PacketsTo").Base(err2)
+		}
+		if config.PacketsFrom > config.PacketsTo {
+			config.PacketsFrom, config.PacketsTo = config.PacketsTo, config.PacketsFrom
+		}
+		if config.PacketsFrom == 0 {
+			return nil, errors.New("PacketsFrom can't be 0")
+		}
+	}
+
+	{
+		if c.Length == "" {
+			return nil, errors.New("Length can't be empty")
+		}
+		lengthMinMax := strings.Split(c.Length, "-")
+		if len(lengthMinMax) == 2 {
+			config.LengthMin, err = strconv.ParseUint(lengthMinMax[0], 10, 64)
+			config.LengthMax, err2 = strconv.ParseUint(lengthMinMax[1], 10, 64)
+		} else {
+			config.LengthMin, err = strconv.ParseUint(lengthMinMax[0], 10, 64)
+			config.LengthMax = config.LengthMin
+		}
+		if err != nil {
+			return nil, errors.New("Invalid LengthMin").Base(err)
+		}
+		if err2 != nil {
+			return nil, errors.New("Invalid LengthMax").Base(err2)
+		}
+		if config.LengthMin > config.LengthMax {
+			config.LengthMin, config.LengthMax = config.LengthMax, config.LengthMin
+		}
+		if config.LengthMin == 0 {
+			return nil, errors.New("LengthMin can't be 0")
+		}
+	}
+
+	{
+		if c.Interval == "" {
+			return nil, errors.New("Interval can't be empty")
+		}
+		intervalMinMax := strings.Split(c.Interval, "-")
+		if len(intervalMinMax) == 2 {
+			config.IntervalMin, err = strconv.ParseUint(intervalMinMax[0], 10, 64)
+			config.IntervalMax, err2 = strconv.ParseUint(intervalMinMax[1], 10, 64)
+		} else {
+			config.IntervalMin, err = strconv.ParseUint(intervalMinMax[0], 10, 64)
+			config.IntervalMax = config.IntervalMin
+		}
+		if err != nil {
+			return nil, errors.New("Invalid IntervalMin").Base(err)
+		}
+		if err2 != nil {
+			return nil, errors.New("Invalid IntervalMax").Base(err2)
+		}
+		if config.IntervalMin > config.IntervalMax {
+			config.IntervalMin, config.IntervalMax = config.IntervalMax, config.IntervalMin
+		}
+	}
+
+	{
+		if c.Host1_header == "" {
+			config.Host1Header = "Host : "
+		} else {
+			config.Host1Header = c.Host1_header
+		}
+
+		if c.Host1_domain == "" {
+			config.Host1Domain = "cloudflare.com"
+		} else {
+			config.Host1Domain = c.Host1_domain
+		}
+
+		if c.Host2_header == "" {
+			config.Host2Header = "Host:   "
+		} else {
+			config.Host2Header = c.Host2_header
+		}
+
+		if c.Host2_domain == "" {
+			config.Host2Domain = "cloudflare.com"
+		} else {
+			config.Host2Domain = c.Host2_domain
+		}
+	}
+
+	if c.Strategy != "" {
+		settings := []byte("{}")
+		if c.Settings != nil {
+			settings = ([]byte)(*c.Settings)
+		}
+		rawConfig, err := fragmentStrategyConfigLoader.LoadWithID(settings, c.Strategy)
+		if err != nil {
+			return nil, errors.New("failed to parse fragment strategy config").Base(err)
+		}
+		ts, err := rawConfig.(Buildable).Build()
+		if err != nil {
+			return nil, err
+		}
+		config.Strategy = strings.ToLower(c.Strategy)
+		config.StrategySettings = serial.ToTypedMessage(ts)
+	}
+
+	if c.Fake != nil {
+		// sending with a low TTL needs the sockets of Linux
+		if runtime.GOOS != "linux" && runtime.GOOS != "android" {
+			return nil, errors.New("fragment fake is not supported on ", runtime.GOOS)
+		}
+		if c.Fake.TTL == 0 || c.Fake.TTL > 255 {
+			return nil, errors.New("fragment fake ttl must be between 1 and 255")
+		}
+		config.Fake = &internet.FragmentFake{
+			Ttl:        c.Fake.TTL,
+			ServerName: c.Fake.ServerName,
+		}
+	}
+
+	return config, nil
+}
+
+type fragmentEmptyConfig struct{}
+
+func (c *fragmentEmptyConfig) Build() (proto.Message, error) {
+	return nil, nil
+}
+
+type fragmentTLSRecordConfig struct {
+	// number of records sent per write
+	Batch *Int32Range `json:"batch"`
+}
+
+func (c *fragmentTLSRecordConfig) Build() (proto.Message, error) {
+	config := &internet.TLSRecordFragmentConfig{BatchMin: 3, BatchMax: 5}
+	if c.Batch != nil {
+		if c.Batch.From < 1 {
+			return nil, errors.New("batch can't be less than 1")
+		}
+		config.BatchMin = uint64(c.Batch.From)
+		config.BatchMax = uint64(c.Batch.To)
+	}
+	return config, nil
 }
 
-type Noise struct {
-	Type   string      `json:"type"`
-	Packet string      `json:"packet"`
-	Delay  *Int32Range `json:"delay"`
-	Count  *Int32Range `json:"count"`
+type fragmentSNIConfig struct {
+	TLSRecord      bool   `json:"tlsRecord"`
+	ServerNameCuts uint32 `json:"serverNameCuts"`
+	SplitALPN      bool   `json:"splitAlpn"`
+	SplitKeyShare  bool   `json:"splitKeyShare"`
+}
+
+func (c *fragmentSNIConfig) Build() (proto.Message, error) {
+	return &internet.SNIFragmentConfig{
+		TlsRecord:      c.TLSRecord,
+		ServerNameCuts: c.ServerNameCuts,
+		SplitAlpn:      c.SplitALPN,
+		SplitKeyShare:  c.SplitKeyShare,
+	}, nil
+}
+
+type ClientHello struct {
+	RecordSize *Int32Range `json:"recordSize"`
+}
+
+type AdaptiveFragment struct {
+	Profiles        []*Fragment `json:"profiles"`
+	ByServerName    bool        `json:"byServerName"`
+	ReprobeInterval uint32      `json:"reprobeInterval"`
+	Stats           bool        `json:"stats"`
+}
+
+type QuicFragment struct {
+	Packets uint32 `json:"packets"`
+	Reorder bool   `json:"reorder"`
+	Decoys  uint32 `json:"decoys"`
 }
 
 // Build implements Buildable
@@ -73,137 +299,91 @@ func (c *FreedomConfig) Build() (proto.Message, error) {
 	}
 
 	if c.Fragment != nil {
-		config.Fragment = new(freedom.Fragment)
-		var err, err2 error
-
-		config.Fragment.FakeHost = false
-
-		switch strings.ToLower(c.Fragment.Packets) {
-		case "tlshello":
-			// TLS Hello Fragmentation (into multiple handshake messages)
-			config.Fragment.PacketsFrom = 0
-			config.Fragment.PacketsTo = 1
-		case "fakehost":
-			// fake host header with no fragmentation
-			config.Fragment.PacketsFrom = 1
-			config.Fragment.PacketsTo = 1
-			config.Fragment.FakeHost = true
-		case "":
-			// TCP Segmentation (all packets)
-			config.Fragment.PacketsFrom = 0
-			config.Fragment.PacketsTo = 0
-		default:
-			// TCP Segmentation (range)
-			packetsFromTo := strings.Split(c.Fragment.Packets, "-")
-			if len(packetsFromTo) == 2 {
-				config.Fragment.PacketsFrom, err = strconv.ParseUint(packetsFromTo[0], 10, 64)
-				config.Fragment.PacketsTo, err2 = strconv.ParseUint(packetsFromTo[1], 10, 64)
-			} else {
-				config.Fragment.PacketsFrom, err = strconv.ParseUint(packetsFromTo[0], 10, 64)
-				config.Fragment.PacketsTo = config.Fragment.PacketsFrom
-			}
-			if err != nil {
-				return nil, errors.New("Invalid PacketsFrom").Base(err)
-			}
-			if err2 != nil {
-				return nil, errors.New("Invalid PacketsTo").Base(err2)
-			}
-			if config.Fragment.PacketsFrom > config.Fragment.PacketsTo {
-				config.Fragment.PacketsFrom, config.Fragment.PacketsTo = config.Fragment.PacketsTo, config.Fragment.PacketsFrom
-			}
-			if config.Fragment.PacketsFrom == 0 {
-				return nil, errors.New("PacketsFrom can't be 0")
-			}
+		fragment, err := c.Fragment.Build()
+		if err != nil {
+			return nil, err
 		}
+		config.Fragment = fragment
+	}
 
-		{
-			if c.Fragment.Length == "" {
-				return nil, errors.New("Length can't be empty")
-			}
-			lengthMinMax := strings.Split(c.Fragment.Length, "-")
-			if len(lengthMinMax) == 2 {
-				config.Fragment.LengthMin, err = strconv.ParseUint(lengthMinMax[0], 10, 64)
-				config.Fragment.LengthMax, err2 = strconv.ParseUint(lengthMinMax[1], 10, 64)
-			} else {
-				config.Fragment.LengthMin, err = strconv.ParseUint(lengthMinMax[0], 10, 64)
-				config.Fragment.LengthMax = config.Fragment.LengthMin
-			}
+	if c.Noise != nil {
+		return nil, errors.PrintRemovedFeatureError("noise = { ... }", "noises = [ { ... } ]")
+	}
+
+	if c.Noises != nil {
+		for _, n := range c.Noises {
+			NConfig, err := ParseNoise(n)
 			if err != nil {
-				return nil, errors.New("Invalid LengthMin").Base(err)
-			}
-			if err2 != nil {
-				return nil, errors.New("Invalid LengthMax").Base(err2)
-			}
-			if config.Fragment.LengthMin > config.Fragment.LengthMax {
-				config.Fragment.LengthMin, config.Fragment.LengthMax = config.Fragment.LengthMax, config.Fragment.LengthMin
-			}
-			if config.Fragment.LengthMin == 0 {
-				return nil, errors.New("LengthMin can't be 0")
+				return nil, err
 			}
+			config.Noises = append(config.Noises, NConfig)
 		}
+	}
 
-		{
-			if c.Fragment.Interval == "" {
-				return nil, errors.New("Interval can't be empty")
-			}
-			intervalMinMax := strings.Split(c.Fragment.Interval, "-")
-			if len(intervalMinMax) == 2 {
-				config.Fragment.IntervalMin, err = strconv.ParseUint(intervalMinMax[0], 10, 64)
-				config.Fragment.IntervalMax, err2 = strconv.ParseUint(intervalMinMax[1], 10, 64)
-			} else {
-				config.Fragment.IntervalMin, err = strconv.ParseUint(intervalMinMax[0], 10, 64)
-				config.Fragment.IntervalMax = config.Fragment.IntervalMin
+	if len(c.FragmentProfiles) > 0 {
+		config.FragmentProfiles = make(map[string]*internet.Fragment, len(c.FragmentProfiles))
+		for name, f := range c.FragmentProfiles {
+			if f == nil {
+				return nil, errors.New("empty fragment profile ", name)
 			}
+			fragment, err := f.Build()
 			if err != nil {
-				return nil, errors.New("Invalid IntervalMin").Base(err)
-			}
-			if err2 != nil {
-				return nil, errors.New("Invalid IntervalMax").Base(err2)
-			}
-			if config.Fragment.IntervalMin > config.Fragment.IntervalMax {
-				config.Fragment.IntervalMin, config.Fragment.IntervalMax = config.Fragment.IntervalMax, config.Fragment.IntervalMin
+				return nil, errors.New("invalid fragment profile ", name).Base(err)
 			}
+			config.FragmentProfiles[name] = fragment
 		}
+	}
 
-		{
-			if c.Fragment.Host1_header == "" {
-				config.Fragment.Host1Header = "Host : "
-			} else {
-				config.Fragment.Host1Header = c.Fragment.Host1_header
-			}
-
-			if c.Fragment.Host1_domain == "" {
-				config.Fragment.Host1Domain = "cloudflare.com"
-			} else {
-				config.Fragment.Host1Domain = c.Fragment.Host1_domain
-			}
-
-			if c.Fragment.Host2_header == "" {
-				config.Fragment.Host2Header = "Host:   "
-			} else {
-				config.Fragment.Host2Header = c.Fragment.Host2_header
+	if len(c.NoiseProfiles) > 0 {
+		config.NoiseProfiles = make(map[string]*noise.Config, len(c.NoiseProfiles))
+		for name, p := range c.NoiseProfiles {
+			if p == nil || len(p.Noises) == 0 {
+				return nil, errors.New("noise profile ", name, " needs at least one noise")
 			}
-
-			if c.Fragment.Host2_domain == "" {
-				config.Fragment.Host2Domain = "cloudflare.com"
-			} else {
-				config.Fragment.Host2Domain = c.Fragment.Host2_domain
+			profile, err := p.Build()
+			if err != nil {
+				return nil, errors.New("invalid noise profile ", name).Base(err)
 			}
+			config.NoiseProfiles[name] = profile
 		}
-
 	}
 
-	if c.Noise != nil {
-		return nil, errors.PrintRemovedFeatureError("noise = { ... }", "noises = [ { ... } ]")
+	if c.ClientHello != nil && c.ClientHello.RecordSize != nil {
+		if c.ClientHello.RecordSize.From < 1 {
+			return nil, errors.New("clientHello recordSize can't be less than 1")
+		}
+		config.ClientHello = &internet.ClientHello{
+			RecordMin: uint64(c.ClientHello.RecordSize.From),
+			RecordMax: uint64(c.ClientHello.RecordSize.To),
+		}
 	}
 
-	if c.Noises != nil {
-		for _, n := range c.Noises {
-			NConfig, err := ParseNoise(n)
+	if c.AdaptiveFragment != nil {
+		if len(c.AdaptiveFragment.Profiles) == 0 {
+			return nil, errors.New("adaptiveFragment needs at least one profile")
+		}
+		config.AdaptiveFragment = &freedom.AdaptiveFragment{
+			ByServerName:    c.AdaptiveFragment.ByServerName,
+			ReprobeInterval: c.AdaptiveFragment.ReprobeInterval,
+			Stats:           c.AdaptiveFragment.Stats,
+		}
+		for i, p := range c.AdaptiveFragment.Profiles {
+			profile, err := p.Build()
 			if err != nil {
-				return nil, err
+				return nil, errors.New("invalid adaptiveFragment profile ", i).Base(err)
 			}
-			config.Noises = append(config.Noises, NConfig)
+			config.AdaptiveFragment.Profiles = append(config.AdaptiveFragment.Profiles, profile)
+		}
+	}
+
+	if c.QuicFragment != nil {
+		if c.QuicFragment.Packets < 2 {
+			return nil, errors.New("quicFragment packets can't be less than 2")
+		}
+		config.QuicFragment = &freedom.QuicFragment{
+			Packets: c.QuicFragment.Packets,
+			Reorder: c.QuicFragment.Reorder,
+			Decoys:  c.QuicFragment.Decoys,
 		}
 	}
 
@@ -236,55 +416,3 @@ func (c *FreedomConfig) Build() (proto.Message, error) {
 	}
 	return config, nil
 }
-
-func ParseNoise(noise *Noise) (*freedom.Noise, error) {
-	var err error
-	NConfig := new(freedom.Noise)
-	noise.Packet = strings.TrimSpace(noise.Packet)
-
-	switch noise.Type {
-	case "rand":
-		min, max, err := ParseRangeString(noise.Packet)
-		if err != nil {
-			return nil, errors.New("invalid value for rand Length").Base(err)
-		}
-		NConfig.LengthMin = uint64(min)
-		NConfig.LengthMax = uint64(max)
-		if NConfig.LengthMin == 0 {
-			return nil, errors.New("rand lengthMin or lengthMax cannot be 0")
-		}
-
-	case "str":
-		// user input string
-		NConfig.Packet = []byte(noise.Packet)
-
-	case "hex":
-		// user input hex
-		NConfig.Packet, err = hex.DecodeString(noise.Packet)
-		if err != nil {
-			return nil, errors.New("Invalid hex string").Base(err)
-		}
-
-	case "base64":
-		// user input base64
-		NConfig.Packet, err = base64.RawURLEncoding.DecodeString(strings.NewReplacer("+", "-", "/", "_", "=", "").Replace(noise.Packet))
-		if err != nil {
-			return nil, errors.New("Invalid base64 string").Base(err)
-		}
-
-	default:
-		return nil, errors.New("Invalid packet, only rand/str/hex/base64 are supported")
-	}
-
-	if noise.Delay != nil {
-		NConfig.DelayMin = uint64(noise.Delay.From)
-		NConfig.DelayMax = uint64(noise.Delay.To)
-	}
-
-	if noise.Count != nil {
-		NConfig.CountMin = uint64(noise.Count.From)
-		NConfig.CountMax = uint64(noise.Count.To)
-	}
-
-	return NConfig, nil
-}
@@ -4,9 +4,12 @@ import (
 	"testing"
 
 	"github.com/GFW-knocker/Xray-core/common/net"
+	"github.com/GFW-knocker/Xray-core/common/noise"
 	"github.com/GFW-knocker/Xray-core/common/protocol"
+	"github.com/GFW-knocker/Xray-core/common/serial"
 	. "github.com/GFW-knocker/Xray-core/infra/conf"
 	"github.com/GFW-knocker/Xray-core/proxy/freedom"
+	"github.com/GFW-knocker/Xray-core/transport/internet"
 )
 
 func TestFreedomConfig(t *testing.T) {
@@ -37,5 +40,225 @@ func TestFreedomConfig(t *testing.T) {
 				UserLevel: 1,
 			},
 		},
+		{
+			Input: `{
+				"fragment": {
+					"packets": "tlshello",
+					"length": "100-200",
+					"interval": "10-20",
+					"strategy": "SNI",
+					"settings": {
+						"tlsRecord": true,
+						"serverNameCuts": 3,
+						"splitAlpn": true
+					}
+				}
+			}`,
+			Parser: loadJSON(creator),
+			Output: &freedom.Config{
+				Fragment: &internet.Fragment{
+					PacketsFrom: 0,
+					PacketsTo:   1,
+					LengthMin:   100,
+					LengthMax:   200,
+					IntervalMin: 10,
+					IntervalMax: 20,
+					Host1Header: "Host : ",
+					Host1Domain: "cloudflare.com",
+					Host2Header: "Host:   ",
+					Host2Domain: "cloudflare.com",
+					Strategy:    "sni",
+					StrategySettings: serial.ToTypedMessage(&internet.SNIFragmentConfig{
+						TlsRecord:      true,
+						ServerNameCuts: 3,
+						SplitAlpn:      true,
+					}),
+				},
+			},
+		},
+		{
+			Input: `{
+				"fragment": {
+					"packets": "tlshello",
+					"length": "100-200",
+					"interval": "10-20",
+					"fake": {
+						"ttl": 3,
+						"serverName": "www.example.com"
+					}
+				}
+			}`,
+			Parser: loadJSON(creator),
+			Output: &freedom.Config{
+				Fragment: &internet.Fragment{
+					PacketsFrom: 0,
+					PacketsTo:   1,
+					LengthMin:   100,
+					LengthMax:   200,
+					IntervalMin: 10,
+					IntervalMax: 20,
+					Host1Header: "Host : ",
+					Host1Domain: "cloudflare.com",
+					Host2Header: "Host:   ",
+					Host2Domain: "cloudflare.com",
+					Fake: &internet.FragmentFake{
+						Ttl:        3,
+						ServerName: "www.example.com",
+					},
+				},
+			},
+		},
+		{
+			Input: `{
+				"clientHello": {
+					"recordSize": "50-100"
+				}
+			}`,
+			Parser: loadJSON(creator),
+			Output: &freedom.Config{
+				ClientHello: &internet.ClientHello{
+					RecordMin: 50,
+					RecordMax: 100,
+				},
+			},
+		},
+		{
+			Input: `{
+				"adaptiveFragment": {
+					"profiles": [
+						{
+							"packets": "tlshello",
+							"length": "100-200",
+							"interval": "0"
+						},
+						{
+							"length": "10-20",
+							"interval": "1-2",
+							"strategy": "sni"
+						}
+					],
+					"byServerName": true,
+					"reprobeInterval": 600,
+					"stats": true
+				}
+			}`,
+			Parser: loadJSON(creator),
+			Output: &freedom.Config{
+				AdaptiveFragment: &freedom.AdaptiveFragment{
+					Profiles: []*internet.Fragment{
+						{
+							PacketsFrom: 0,
+							PacketsTo:   1,
+							LengthMin:   100,
+							LengthMax:   200,
+							Host1Header: "Host : ",
+							Host1Domain: "cloudflare.com",
+							Host2Header: "Host:   ",
+							Host2Domain: "cloudflare.com",
+						},
+						{
+							LengthMin:        10,
+							LengthMax:        20,
+							IntervalMin:      1,
+							IntervalMax:      2,
+							Host1Header:      "Host : ",
+							Host1Domain:      "cloudflare.com",
+							Host2Header:      "Host:   ",
+							Host2Domain:      "cloudflare.com",
+							Strategy:         "sni",
+							StrategySettings: serial.ToTypedMessage(&internet.SNIFragmentConfig{}),
+						},
+					},
+					ByServerName:    true,
+					ReprobeInterval: 600,
+					Stats:           true,
+				},
+			},
+		},
+		{
+			Input: `{
+				"quicFragment": {
+					"packets": 3,
+					"reorder": true,
+					"decoys": 2
+				}
+			}`,
+			Parser: loadJSON(creator),
+			Output: &freedom.Config{
+				QuicFragment: &freedom.QuicFragment{
+					Packets: 3,
+					Reorder: true,
+					Decoys:  2,
+				},
+			},
+		},
+		{
+			Input: `{
+				"fragmentProfiles": {
+					"light": {
+						"packets": "tlshello",
+						"length": "100-200",
+						"interval": "10-20"
+					}
+				},
+				"noiseProfiles": {
+					"quic": {
+						"noises": [
+							{
+								"type": "str",
+								"packet": "hello"
+							}
+						],
+						"noiseKeepAlive": 10
+					}
+				}
+			}`,
+			Parser: loadJSON(creator),
+			Output: &freedom.Config{
+				FragmentProfiles: map[string]*internet.Fragment{
+					"light": {
+						PacketsFrom: 0,
+						PacketsTo:   1,
+						LengthMin:   100,
+						LengthMax:   200,
+						IntervalMin: 10,
+						IntervalMax: 20,
+						Host1Header: "Host : ",
+						Host1Domain: "cloudflare.com",
+						Host2Header: "Host:   ",
+						Host2Domain: "cloudflare.com",
+					},
+				},
+				NoiseProfiles: map[string]*noise.Config{
+					"quic": {
+						Noises: []*noise.Noise{
+							{Packet: []byte("hello")},
+						},
+						KeepAlive: 10,
+					},
+				},
+			},
+		},
+		{
+			Input: `{
+				"noises": [
+					{
+						"type": "template",
+						"packet": "{stun}{rand:4-8}",
+						"count": "2"
+					}
+				]
+			}`,
+			Parser: loadJSON(creator),
+			Output: &freedom.Config{
+				Noises: []*noise.Noise{
+					{
+						Template: "{stun}{rand:4-8}",
+						CountMin: 2,
+						CountMax: 2,
+					},
+				},
+			},
+		},
 	})
 }
@@ -0,0 +1,99 @@
+package conf
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	"github.com/GFW-knocker/Xray-core/common/errors"
+	"github.com/GFW-knocker/Xray-core/common/noise"
+)
+
+type Noise struct {
+	Type   string      `json:"type"`
+	Packet string      `json:"packet"`
+	Delay  *Int32Range `json:"delay"`
+	Count  *Int32Range `json:"count"`
+}
+
+// NoiseConfig is the noise of outbounds and transports other than freedom,
+// embedded in their settings.
+type NoiseConfig struct {
+	Noises         []*Noise `json:"noises"`
+	NoiseKeepAlive uint32   `json:"noiseKeepAlive"`
+}
+
+// Build returns nil if there is no noise.
+func (c *NoiseConfig) Build() (*noise.Config, error) {
+	if len(c.Noises) == 0 {
+		return nil, nil
+	}
+	config := &noise.Config{KeepAlive: c.NoiseKeepAlive}
+	for _, n := range c.Noises {
+		NConfig, err := ParseNoise(n)
+		if err != nil {
+			return nil, err
+		}
+		config.Noises = append(config.Noises, NConfig)
+	}
+	return config, nil
+}
+
+func ParseNoise(n *Noise) (*noise.Noise, error) {
+	var err error
+	NConfig := new(noise.Noise)
+	n.Packet = strings.TrimSpace(n.Packet)
+
+	switch n.Type {
+	case "rand":
+		min, max, err := ParseRangeString(n.Packet)
+		if err != nil {
+			return nil, errors.New("invalid value for rand Length").Base(err)
+		}
+		NConfig.LengthMin = uint64(min)
+		NConfig.LengthMax = uint64(max)
+		if NConfig.LengthMin == 0 {
+			return nil, errors.New("rand lengthMin or lengthMax cannot be 0")
+		}
+
+	case "str":
+		// user input string
+		NConfig.Packet = []byte(n.Packet)
+
+	case "hex":
+		// user input hex
+		NConfig.Packet, err = hex.DecodeString(n.Packet)
+		if err != nil {
+			return nil, errors.New("Invalid hex string").Base(err)
+		}
+
+	case "base64":
+		// user input base64
+		NConfig.Packet, err = base64.RawURLEncoding.DecodeString(strings.NewReplacer("+", "-", "/", "_", "=", "").Replace(n.Packet))
+		if err != nil {
+			return nil, errors.New("Invalid base64 string").Base(err)
+		}
+
+	case "template":
+		// rendered on every send, see noise.Template
+		if _, err := noise.ParseTemplate(n.Packet); err != nil {
+			return nil, errors.New("Invalid noise template").Base(err)
+		}
+		NConfig.Template = n.Packet
+
+	default:
+		return nil, errors.New("Invalid packet, only rand/str/hex/base64/template are supported")
+	}
+
+	if n.Delay != nil {
+		NConfig.DelayMin = uint64(n.Delay.From)
+		NConfig.DelayMax = uint64(n.Delay.To)
+	}
+
+	if n.Count != nil {
+		NConfig.CountMin = uint64(n.Count.From)
+		NConfig.CountMax = uint64(n.Count.To)
+	}
+
+	return NConfig, nil
+}
@@ -1,6 +1,8 @@
 package conf
 
 import (
+	"strings"
+
 	"google.golang.org/protobuf/proto"
 
 	"github.com/GFW-knocker/Xray-core/app/observatory"
@@ -14,16 +16,67 @@ type ObservatoryConfig struct {
 	ProbeURL          string            `json:"probeURL"`
 	ProbeInterval     duration.Duration `json:"probeInterval"`
 	EnableConcurrency bool              `json:"enableConcurrency"`
+	Probe             *ProbeConfig      `json:"probe"`
+	FailureThreshold  uint32            `json:"failureThreshold"`
 }
 
 func (o *ObservatoryConfig) Build() (proto.Message, error) {
-	return &observatory.Config{SubjectSelector: o.SubjectSelector, ProbeUrl: o.ProbeURL, ProbeInterval: int64(o.ProbeInterval), EnableConcurrency: o.EnableConcurrency}, nil
+	config := &observatory.Config{SubjectSelector: o.SubjectSelector, ProbeUrl: o.ProbeURL, ProbeInterval: int64(o.ProbeInterval), EnableConcurrency: o.EnableConcurrency, FailureThreshold: o.FailureThreshold}
+	if o.Probe != nil {
+		probe, err := o.Probe.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.Probe = probe
+	}
+	return config, nil
+}
+
+var probeTypes = map[string]observatory.ProbeConfig_Type{
+	"http": observatory.ProbeConfig_HTTP,
+	"tcp":  observatory.ProbeConfig_TCP,
+	"tls":  observatory.ProbeConfig_TLS,
+	"dns":  observatory.ProbeConfig_DNS,
+	"udp":  observatory.ProbeConfig_UDP,
+}
+
+// ProbeConfig configures probes other than HTTP requests.
+type ProbeConfig struct {
+	Type        string `json:"type"`
+	Destination string `json:"destination"`
+	ServerName  string `json:"serverName"`
+	Query       string `json:"query"`
+	Payload     string `json:"payload"`
+}
+
+func (c *ProbeConfig) Build() (*observatory.ProbeConfig, error) {
+	t, ok := probeTypes[strings.ToLower(c.Type)]
+	if !ok {
+		return nil, errors.New("unknown probe type: ", c.Type)
+	}
+	config := &observatory.ProbeConfig{
+		Type:        t,
+		Destination: c.Destination,
+		ServerName:  c.ServerName,
+		Query:       c.Query,
+		Payload:     []byte(c.Payload),
+	}
+	if len(config.Payload) == 0 {
+		config.Payload = nil
+	}
+	// Fail early instead of when the observatory starts.
+	if _, err := observatory.NewProber(config); err != nil {
+		return nil, err
+	}
+	return config, nil
 }
 
 type BurstObservatoryConfig struct {
 	SubjectSelector []string `json:"subjectSelector"`
 	// health check settings
 	HealthCheck *healthCheckSettings `json:"pingConfig,omitempty"`
+	// consecutive connection failures to mark an outbound dead
+	FailureThreshold uint32 `json:"failureThreshold"`
 }
 
 func (b BurstObservatoryConfig) Build() (proto.Message, error) {
@@ -31,7 +84,7 @@ func (b BurstObservatoryConfig) Build() (proto.Message, error) {
 		return nil, errors.New("BurstObservatory requires a valid pingConfig")
 	}
 	if result, err := b.HealthCheck.Build(); err == nil {
-		return &burst.Config{SubjectSelector: b.SubjectSelector, PingConfig: result.(*burst.HealthPingConfig)}, nil
+		return &burst.Config{SubjectSelector: b.SubjectSelector, PingConfig: result.(*burst.HealthPingConfig), FailureThreshold: b.FailureThreshold}, nil
 	} else {
 		return nil, err
 	}
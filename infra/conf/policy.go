@@ -53,19 +53,23 @@ func (t *Policy) Build() (*policy.Policy, error) {
 }
 
 type SystemPolicy struct {
-	StatsInboundUplink    bool `json:"statsInboundUplink"`
-	StatsInboundDownlink  bool `json:"statsInboundDownlink"`
-	StatsOutboundUplink   bool `json:"statsOutboundUplink"`
-	StatsOutboundDownlink bool `json:"statsOutboundDownlink"`
+	StatsInboundUplink       bool `json:"statsInboundUplink"`
+	StatsInboundDownlink     bool `json:"statsInboundDownlink"`
+	StatsOutboundUplink      bool `json:"statsOutboundUplink"`
+	StatsOutboundDownlink    bool `json:"statsOutboundDownlink"`
+	StatsRuleHits            bool `json:"statsRuleHits"`
+	StatsOutboundConnections bool `json:"statsOutboundConnections"`
 }
 
 func (p *SystemPolicy) Build() (*policy.SystemPolicy, error) {
 	return &policy.SystemPolicy{
 		Stats: &policy.SystemPolicy_Stats{
-			InboundUplink:    p.StatsInboundUplink,
-			InboundDownlink:  p.StatsInboundDownlink,
-			OutboundUplink:   p.StatsOutboundUplink,
-			OutboundDownlink: p.StatsOutboundDownlink,
+			InboundUplink:       p.StatsInboundUplink,
+			InboundDownlink:     p.StatsInboundDownlink,
+			OutboundUplink:      p.StatsOutboundUplink,
+			OutboundDownlink:    p.StatsOutboundDownlink,
+			RuleHits:            p.StatsRuleHits,
+			OutboundConnections: p.StatsOutboundConnections,
 		},
 	}, nil
 }
@@ -2,6 +2,7 @@ package conf
 
 import (
 	"encoding/json"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
@@ -40,7 +41,7 @@ func (r *BalancingRule) Build() (*router.BalancingRule, error) {
 	switch r.Strategy.Type {
 	case "":
 		r.Strategy.Type = strategyRandom
-	case strategyRandom, strategyLeastLoad, strategyLeastPing, strategyRoundRobin:
+	case strategyRandom, strategyLeastLoad, strategyLeastPing, strategyRoundRobin, strategyConsistentHash, strategyLeastBandwidth:
 	default:
 		return nil, errors.New("unknown balancing strategy: " + r.Strategy.Type)
 	}
@@ -74,10 +75,65 @@ type RouterConfig struct {
 	RuleList       []json.RawMessage `json:"rules"`
 	DomainStrategy *string           `json:"domainStrategy"`
 	Balancers      []*BalancingRule  `json:"balancers"`
+	RuleSets       []*RuleSetConfig  `json:"ruleSets"`
 
 	DomainMatcher string `json:"domainMatcher"`
 }
 
+type RuleSetConfig struct {
+	Tag            string `json:"tag"`
+	Path           string `json:"path"`
+	Format         string `json:"format"`
+	Code           string `json:"code"`
+	ReloadInterval uint32 `json:"reloadInterval"`
+}
+
+// Build builds the rule set. The format is guessed from the file extension if
+// not set, except for .dat files which may hold either geosite or geoip lists.
+func (c *RuleSetConfig) Build() (*router.RuleSet, error) {
+	if c.Tag == "" {
+		return nil, errors.New("empty rule set tag")
+	}
+	if c.Path == "" {
+		return nil, errors.New("empty path of rule set ", c.Tag)
+	}
+
+	format := strings.ToLower(c.Format)
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(c.Path)) {
+		case ".txt", ".list":
+			format = "text"
+		case ".xrs", ".srs":
+			format = "binary"
+		default:
+			return nil, errors.New("unknown format of rule set ", c.Tag)
+		}
+	}
+
+	config := &router.RuleSet{
+		Tag:            c.Tag,
+		Path:           c.Path,
+		Code:           c.Code,
+		ReloadInterval: c.ReloadInterval,
+	}
+	switch format {
+	case "text":
+		config.Format = router.RuleSet_Text
+	case "geosite":
+		config.Format = router.RuleSet_GeoSite
+	case "geoip":
+		config.Format = router.RuleSet_GeoIP
+	case "binary":
+		config.Format = router.RuleSet_Binary
+	default:
+		return nil, errors.New("unknown rule set format: ", c.Format)
+	}
+	if (config.Format == router.RuleSet_GeoSite || config.Format == router.RuleSet_GeoIP) && c.Code == "" {
+		return nil, errors.New("empty code of rule set ", c.Tag)
+	}
+	return config, nil
+}
+
 func (c *RouterConfig) getDomainStrategy() router.Config_DomainStrategy {
 	ds := ""
 	if c.DomainStrategy != nil {
@@ -124,6 +180,13 @@ func (c *RouterConfig) Build() (*router.Config, error) {
 		}
 		config.BalancingRule = append(config.BalancingRule, balancer)
 	}
+	for _, rawRuleSet := range c.RuleSets {
+		ruleSet, err := rawRuleSet.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.RuleSet = append(config.RuleSet, ruleSet)
+	}
 	return config, nil
 }
 
@@ -134,6 +197,117 @@ type RouterRule struct {
 	BalancerTag string `json:"balancerTag"`
 
 	DomainMatcher string `json:"domainMatcher"`
+
+	FragmentProfile string `json:"fragmentProfile"`
+	NoiseProfile    string `json:"noiseProfile"`
+}
+
+// ScheduleConfig is the schedule of a rule. A window is written as optional
+// days followed by a time range, like "Mon-Fri 09:00-17:00", "Sat,Sun
+// 10:00-14:00" or "22:00-06:00".
+type ScheduleConfig struct {
+	Timezone string     `json:"timezone"`
+	Windows  StringList `json:"windows"`
+}
+
+var weekdays = map[string]uint32{
+	"sun": 0,
+	"mon": 1,
+	"tue": 2,
+	"wed": 3,
+	"thu": 4,
+	"fri": 5,
+	"sat": 6,
+}
+
+func parseWeekday(s string) (uint32, error) {
+	day, found := weekdays[strings.ToLower(s)]
+	if !found {
+		return 0, errors.New("invalid weekday: ", s)
+	}
+	return day, nil
+}
+
+func parseScheduleTime(s string) (uint32, error) {
+	hour, minute, found := strings.Cut(s, ":")
+	if !found {
+		return 0, errors.New("invalid time: ", s)
+	}
+	h, err := strconv.ParseUint(hour, 10, 32)
+	if err != nil {
+		return 0, errors.New("invalid time: ", s).Base(err)
+	}
+	m, err := strconv.ParseUint(minute, 10, 32)
+	if err != nil {
+		return 0, errors.New("invalid time: ", s).Base(err)
+	}
+	if m >= 60 || h*60+m > 24*60 {
+		return 0, errors.New("invalid time: ", s)
+	}
+	return uint32(h*60 + m), nil
+}
+
+func parseScheduleWindow(s string) (*router.ScheduleWindow, error) {
+	window := new(router.ScheduleWindow)
+	fields := strings.Fields(s)
+	switch len(fields) {
+	case 1:
+	case 2:
+		for _, days := range strings.Split(fields[0], ",") {
+			from, to, isRange := strings.Cut(days, "-")
+			first, err := parseWeekday(from)
+			if err != nil {
+				return nil, err
+			}
+			last := first
+			if isRange {
+				if last, err = parseWeekday(to); err != nil {
+					return nil, err
+				}
+			}
+			for day := first; ; day = (day + 1) % 7 {
+				window.Weekday = append(window.Weekday, day)
+				if day == last {
+					break
+				}
+			}
+		}
+	default:
+		return nil, errors.New("invalid schedule window: ", s)
+	}
+
+	start, end, found := strings.Cut(fields[len(fields)-1], "-")
+	if !found {
+		return nil, errors.New("invalid time range in schedule window: ", s)
+	}
+	var err error
+	if window.Start, err = parseScheduleTime(start); err != nil {
+		return nil, err
+	}
+	if window.End, err = parseScheduleTime(end); err != nil {
+		return nil, err
+	}
+	if window.Start == 24*60 {
+		return nil, errors.New("invalid start of schedule window: ", s)
+	}
+	return window, nil
+}
+
+func (c *ScheduleConfig) Build() (*router.Schedule, error) {
+	if len(c.Windows) == 0 {
+		return nil, errors.New("empty schedule windows")
+	}
+	schedule := &router.Schedule{
+		Timezone: c.Timezone,
+	}
+	for _, s := range c.Windows {
+		window, err := parseScheduleWindow(s)
+		if err != nil {
+			return nil, err
+		}
+		schedule.Window = append(schedule.Window, window)
+	}
+	return schedule, nil
 }
 
 func ParseIP(s string) (*router.CIDR, error) {
@@ -528,6 +702,13 @@ func ToCidrList(ips StringList) ([]*router.GeoIP, error) {
 	return geoipList, nil
 }
 
+var tlsVersions = map[string]uint32{
+	"1.0": 0x0301,
+	"1.1": 0x0302,
+	"1.2": 0x0303,
+	"1.3": 0x0304,
+}
+
 func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
 	type RawFieldRule struct {
 		RouterRule
@@ -538,10 +719,18 @@ func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
 		Network    *NetworkList      `json:"network"`
 		SourceIP   *StringList       `json:"source"`
 		SourcePort *PortList         `json:"sourcePort"`
+		LocalIP    *StringList       `json:"localIP"`
+		LocalPort  *PortList         `json:"localPort"`
 		User       *StringList       `json:"user"`
 		InboundTag *StringList       `json:"inboundTag"`
 		Protocols  *StringList       `json:"protocol"`
 		Attributes map[string]string `json:"attrs"`
+		RuleSet    *StringList       `json:"ruleSet"`
+		Schedule   *ScheduleConfig   `json:"schedule"`
+		ALPN       *StringList       `json:"alpn"`
+		TLSVersion *StringList       `json:"tlsVersion"`
+		ECH        *bool             `json:"ech"`
+		SNI        *bool             `json:"sni"`
 	}
 	rawFieldRule := new(RawFieldRule)
 	err := json.Unmarshal(msg, rawFieldRule)
@@ -551,6 +740,8 @@ func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
 
 	rule := new(router.RoutingRule)
 	rule.RuleTag = rawFieldRule.RuleTag
+	rule.FragmentProfile = rawFieldRule.FragmentProfile
+	rule.NoiseProfile = rawFieldRule.NoiseProfile
 	switch {
 	case len(rawFieldRule.OutboundTag) > 0:
 		rule.TargetTag = &router.RoutingRule_Tag{
@@ -616,6 +807,18 @@ func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
 		rule.SourcePortList = rawFieldRule.SourcePort.Build()
 	}
 
+	if rawFieldRule.LocalIP != nil {
+		geoipList, err := ToCidrList(*rawFieldRule.LocalIP)
+		if err != nil {
+			return nil, err
+		}
+		rule.LocalGeoip = geoipList
+	}
+
+	if rawFieldRule.LocalPort != nil {
+		rule.LocalPortList = rawFieldRule.LocalPort.Build()
+	}
+
 	if rawFieldRule.User != nil {
 		for _, s := range *rawFieldRule.User {
 			rule.UserEmail = append(rule.UserEmail, s)
@@ -638,6 +841,48 @@ func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
 		rule.Attributes = rawFieldRule.Attributes
 	}
 
+	if rawFieldRule.RuleSet != nil {
+		rule.RuleSet = *rawFieldRule.RuleSet
+	}
+
+	if rawFieldRule.ALPN != nil {
+		rule.Alpn = *rawFieldRule.ALPN
+	}
+
+	if rawFieldRule.TLSVersion != nil {
+		for _, s := range *rawFieldRule.TLSVersion {
+			version, found := tlsVersions[s]
+			if !found {
+				return nil, errors.New("unknown TLS version: ", s)
+			}
+			rule.TlsVersion = append(rule.TlsVersion, version)
+		}
+	}
+
+	if rawFieldRule.ECH != nil {
+		if *rawFieldRule.ECH {
+			rule.Ech = router.RoutingRule_ECHPresent
+		} else {
+			rule.Ech = router.RoutingRule_ECHAbsent
+		}
+	}
+
+	if rawFieldRule.SNI != nil {
+		if *rawFieldRule.SNI {
+			rule.Sni = router.RoutingRule_SNIPresent
+		} else {
+			rule.Sni = router.RoutingRule_SNIAbsent
+		}
+	}
+
+	if rawFieldRule.Schedule != nil {
+		schedule, err := rawFieldRule.Schedule.Build()
+		if err != nil {
+			return nil, err
+		}
+		rule.Schedule = schedule
+	}
+
 	return rule, nil
 }
 
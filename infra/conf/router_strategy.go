@@ -6,6 +6,7 @@ import (
 
 	"github.com/GFW-knocker/Xray-core/app/observatory/burst"
 	"github.com/GFW-knocker/Xray-core/app/router"
+	"github.com/GFW-knocker/Xray-core/common/errors"
 	"github.com/GFW-knocker/Xray-core/infra/conf/cfgcommon/duration"
 )
 
@@ -14,6 +15,9 @@ const (
 	strategyLeastPing  string = "leastping"
 	strategyRoundRobin string = "roundrobin"
 	strategyLeastLoad  string = "leastload"
+
+	strategyConsistentHash string = "consistenthash"
+	strategyLeastBandwidth string = "leastbandwidth"
 )
 
 var (
@@ -22,6 +26,9 @@ var (
 		strategyLeastPing:  func() interface{} { return new(strategyEmptyConfig) },
 		strategyRoundRobin: func() interface{} { return new(strategyEmptyConfig) },
 		strategyLeastLoad:  func() interface{} { return new(strategyLeastLoadConfig) },
+
+		strategyConsistentHash: func() interface{} { return new(strategyConsistentHashConfig) },
+		strategyLeastBandwidth: func() interface{} { return new(strategyLeastBandwidthConfig) },
 	}, "type", "settings")
 )
 
@@ -53,6 +60,7 @@ type healthCheckSettings struct {
 	SamplingCount int               `json:"sampling"`
 	Timeout       duration.Duration `json:"timeout"`
 	HttpMethod    string            `json:"httpMethod"`
+	Probe         *ProbeConfig      `json:"probe"`
 }
 
 func (h healthCheckSettings) Build() (proto.Message, error) {
@@ -62,14 +70,22 @@ func (h healthCheckSettings) Build() (proto.Message, error) {
 	} else {
 		httpMethod = strings.TrimSpace(h.HttpMethod)
 	}
-	return &burst.HealthPingConfig{
+	config := &burst.HealthPingConfig{
 		Destination:   h.Destination,
 		Connectivity:  h.Connectivity,
 		Interval:      int64(h.Interval),
 		Timeout:       int64(h.Timeout),
 		SamplingCount: int32(h.SamplingCount),
 		HttpMethod:    httpMethod,
-	}, nil
+	}
+	if h.Probe != nil {
+		probe, err := h.Probe.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.Probe = probe
+	}
+	return config, nil
 }
 
 // Build implements Buildable.
@@ -100,3 +116,51 @@ func (v *strategyLeastLoadConfig) Build() (proto.Message, error) {
 	}
 	return config, nil
 }
+
+type strategyConsistentHashConfig struct {
+	// fields the hash key is made of
+	Keys []string `json:"keys,omitempty"`
+}
+
+var consistentHashKeys = map[string]router.StrategyConsistentHashConfig_Key{
+	"sourceip": router.StrategyConsistentHashConfig_SourceIP,
+	"domain":   router.StrategyConsistentHashConfig_TargetDomain,
+	"email":    router.StrategyConsistentHashConfig_UserEmail,
+}
+
+// Build implements Buildable.
+func (v *strategyConsistentHashConfig) Build() (proto.Message, error) {
+	config := &router.StrategyConsistentHashConfig{}
+	for _, k := range v.Keys {
+		key, ok := consistentHashKeys[strings.ToLower(k)]
+		if !ok {
+			return nil, errors.New("unknown consistent hash key: ", k)
+		}
+		config.Key = append(config.Key, key)
+	}
+	return config, nil
+}
+
+type strategyLeastBandwidthConfig struct {
+	// weight settings
+	Costs []*router.StrategyWeight `json:"costs,omitempty"`
+	// length of the window traffic is measured over
+	Window duration.Duration `json:"window,omitempty"`
+	// bytes per second an active connection counts as
+	ConnectionCost int64 `json:"connectionCost,omitempty"`
+}
+
+// Build implements Buildable.
+func (v *strategyLeastBandwidthConfig) Build() (proto.Message, error) {
+	if v.Window < 0 {
+		return nil, errors.New("invalid window: ", v.Window)
+	}
+	if v.ConnectionCost < 0 {
+		return nil, errors.New("invalid connection cost: ", v.ConnectionCost)
+	}
+	return &router.StrategyLeastBandwidthConfig{
+		Costs:          v.Costs,
+		Window:         int64(v.Window),
+		ConnectionCost: v.ConnectionCost,
+	}, nil
+}
@@ -148,6 +148,33 @@ func TestRouterConfig(t *testing.T) {
 							}
 						},
 						"fallbackTag": "fall"
+					},
+					{
+						"tag": "b3",
+						"selector": ["test"],
+						"strategy": {
+							"type": "consistentHash",
+							"settings": {
+								"keys": ["sourceIP", "email"]
+							}
+						}
+					},
+					{
+						"tag": "b4",
+						"selector": ["test"],
+						"strategy": {
+							"type": "leastBandwidth",
+							"settings": {
+								"costs": [
+									{
+										"match": "slow",
+										"value": 4
+									}
+								],
+								"window": "30s",
+								"connectionCost": 1024
+							}
+						}
 					}
 				]
 			}`,
@@ -183,6 +210,32 @@ func TestRouterConfig(t *testing.T) {
 						}),
 						FallbackTag: "fall",
 					},
+					{
+						Tag:              "b3",
+						OutboundSelector: []string{"test"},
+						Strategy:         "consistenthash",
+						StrategySettings: serial.ToTypedMessage(&router.StrategyConsistentHashConfig{
+							Key: []router.StrategyConsistentHashConfig_Key{
+								router.StrategyConsistentHashConfig_SourceIP,
+								router.StrategyConsistentHashConfig_UserEmail,
+							},
+						}),
+					},
+					{
+						Tag:              "b4",
+						OutboundSelector: []string{"test"},
+						Strategy:         "leastbandwidth",
+						StrategySettings: serial.ToTypedMessage(&router.StrategyLeastBandwidthConfig{
+							Costs: []*router.StrategyWeight{
+								{
+									Match: "slow",
+									Value: 4,
+								},
+							},
+							Window:         int64(30 * time.Second),
+							ConnectionCost: 1024,
+						}),
+					},
 				},
 				Rule: []*router.RoutingRule{
 					{
@@ -307,5 +360,159 @@ func TestRouterConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			Input: `{
+				"ruleSets": [
+					{
+						"tag": "ads",
+						"path": "ads.list",
+						"reloadInterval": 300
+					},
+					{
+						"tag": "cn",
+						"path": "geosite.dat",
+						"format": "geosite",
+						"code": "cn"
+					}
+				],
+				"rules": [
+					{
+						"type": "field",
+						"ruleSet": ["ads", "cn"],
+						"outboundTag": "block"
+					}
+				]
+			}`,
+			Parser: createParser(),
+			Output: &router.Config{
+				DomainStrategy: router.Config_AsIs,
+				RuleSet: []*router.RuleSet{
+					{
+						Tag:            "ads",
+						Path:           "ads.list",
+						Format:         router.RuleSet_Text,
+						ReloadInterval: 300,
+					},
+					{
+						Tag:    "cn",
+						Path:   "geosite.dat",
+						Format: router.RuleSet_GeoSite,
+						Code:   "cn",
+					},
+				},
+				Rule: []*router.RoutingRule{
+					{
+						RuleSet: []string{"ads", "cn"},
+						TargetTag: &router.RoutingRule_Tag{
+							Tag: "block",
+						},
+					},
+				},
+			},
+		},
+		{
+			Input: `{
+				"rules": [
+					{
+						"type": "field",
+						"user": ["staff"],
+						"schedule": {
+							"timezone": "Europe/Berlin",
+							"windows": ["Mon-Fri 09:00-17:30", "Sat,Sun 22:00-06:00", "12:00-24:00"]
+						},
+						"outboundTag": "work"
+					}
+				]
+			}`,
+			Parser: createParser(),
+			Output: &router.Config{
+				DomainStrategy: router.Config_AsIs,
+				Rule: []*router.RoutingRule{
+					{
+						UserEmail: []string{"staff"},
+						Schedule: &router.Schedule{
+							Timezone: "Europe/Berlin",
+							Window: []*router.ScheduleWindow{
+								{Weekday: []uint32{1, 2, 3, 4, 5}, Start: 9 * 60, End: 17*60 + 30},
+								{Weekday: []uint32{6, 0}, Start: 22 * 60, End: 6 * 60},
+								{Start: 12 * 60, End: 24 * 60},
+							},
+						},
+						TargetTag: &router.RoutingRule_Tag{
+							Tag: "work",
+						},
+					},
+				},
+			},
+		},
+		{
+			Input: `{
+				"rules": [
+					{
+						"type": "field",
+						"protocol": ["quic"],
+						"alpn": "h3",
+						"tlsVersion": ["1.3"],
+						"ech": true,
+						"sni": false,
+						"outboundTag": "ech"
+					}
+				]
+			}`,
+			Parser: createParser(),
+			Output: &router.Config{
+				DomainStrategy: router.Config_AsIs,
+				Rule: []*router.RoutingRule{
+					{
+						Protocol:   []string{"quic"},
+						Alpn:       []string{"h3"},
+						TlsVersion: []uint32{0x0304},
+						Ech:        router.RoutingRule_ECHPresent,
+						Sni:        router.RoutingRule_SNIAbsent,
+						TargetTag: &router.RoutingRule_Tag{
+							Tag: "ech",
+						},
+					},
+				},
+			},
+		},
+		{
+			Input: `{
+				"rules": [
+					{
+						"type": "field",
+						"localIP": ["10.0.0.1"],
+						"localPort": "8000-8099",
+						"outboundTag": "direct"
+					}
+				]
+			}`,
+			Parser: createParser(),
+			Output: &router.Config{
+				DomainStrategy: router.Config_AsIs,
+				Rule: []*router.RoutingRule{
+					{
+						LocalGeoip: []*router.GeoIP{
+							{
+								Cidr: []*router.CIDR{
+									{
+										Ip:     []byte{10, 0, 0, 1},
+										Prefix: 32,
+									},
+								},
+							},
+						},
+						LocalPortList: &net.PortList{
+							Range: []*net.PortRange{
+								{From: 8000, To: 8099},
+							},
+						},
+						TargetTag: &router.RoutingRule_Tag{
+							Tag: "direct",
+						},
+					},
+				},
+			},
+		},
 	})
 }
@@ -55,6 +55,7 @@ type KCPConfig struct {
 	WriteBufferSize *uint32         `json:"writeBufferSize"`
 	HeaderConfig    json.RawMessage `json:"header"`
 	Seed            *string         `json:"seed"`
+	NoiseConfig
 }
 
 // Build implements Buildable.
@@ -116,6 +117,11 @@ func (c *KCPConfig) Build() (proto.Message, error) {
 		config.Seed = &kcp.EncryptionSeed{Seed: *c.Seed}
 	}
 
+	var err error
+	if config.Noise, err = c.NoiseConfig.Build(); err != nil {
+		return nil, errors.New("invalid mKCP noises").Base(err)
+	}
+
 	return config, nil
 }
 
@@ -338,6 +344,7 @@ type QUICConfig struct {
 	Header   json.RawMessage `json:"header"`
 	Security string          `json:"security"`
 	Key      string          `json:"key"`
+	NoiseConfig
 }
 
 // Build implements Buildable.
@@ -372,6 +379,11 @@ func (c *QUICConfig) Build() (proto.Message, error) {
 		Type: st,
 	}
 
+	var err error
+	if config.Noise, err = c.NoiseConfig.Build(); err != nil {
+		return nil, errors.New("invalid QUIC noises").Base(err)
+	}
+
 	return config, nil
 }
 
@@ -848,6 +860,7 @@ type SocketConfig struct {
 	CustomSockopt         []*CustomSockoptConfig `json:"customSockopt"`
 	AddressPortStrategy   string                 `json:"addressPortStrategy"`
 	HappyEyeballsSettings *HappyEyeballsConfig   `json:"happyEyeballs"`
+	Fragment              *Fragment              `json:"fragment"`
 }
 
 // Build implements Buildable.
@@ -947,6 +960,15 @@ func (c *SocketConfig) Build() (*internet.SocketConfig, error) {
 		happyEyeballs.MaxConcurrentTry = c.HappyEyeballsSettings.MaxConcurrentTry
 	}
 
+	var fragment *internet.Fragment
+	if c.Fragment != nil {
+		var err error
+		fragment, err = c.Fragment.Build()
+		if err != nil {
+			return nil, errors.New("invalid fragment in sockopt").Base(err)
+		}
+	}
+
 	return &internet.SocketConfig{
 		Mark:                 c.Mark,
 		Tfo:                  tfo,
@@ -967,6 +989,7 @@ func (c *SocketConfig) Build() (*internet.SocketConfig, error) {
 		CustomSockopt:        customSockopts,
 		AddressPortStrategy:  addressPortStrategy,
 		HappyEyeballs:        happyEyeballs,
+		Fragment:             fragment,
 	}, nil
 }
 
@@ -63,17 +63,25 @@ type WireGuardConfig struct {
 	Wnoisecount    string                 `json:"wnoisecount"`
 	Wnoisedelay    string                 `json:"wnoisedelay"`
 	Wpayloadsize   string                 `json:"wpayloadsize"`
+	NoiseConfig
 }
 
 func (c *WireGuardConfig) Build() (proto.Message, error) {
 	config := new(wireguard.DeviceConfig)
 
-	config.Wnoise = c.Wnoise
-	config.Wnoisecount = c.Wnoisecount
-	config.Wnoisedelay = c.Wnoisedelay
-	config.Wpayloadsize = c.Wpayloadsize
-
 	var err error
+	config.Noise, err = c.NoiseConfig.Build()
+	if err != nil {
+		return nil, errors.New("invalid WireGuard noises").Base(err)
+	}
+	config.LegacyNoise, err = c.legacyNoise()
+	if err != nil {
+		return nil, errors.New("invalid WireGuard noises").Base(err)
+	}
+	if config.Noise != nil && config.LegacyNoise != nil {
+		return nil, errors.New(`"wnoise" can't be used with "noises"`)
+	}
+
 	config.SecretKey, err = ParseWireGuardKey(c.SecretKey)
 	if err != nil {
 		return nil, errors.New("invalid WireGuard secret key: %w", err)
@@ -132,6 +140,73 @@ func (c *WireGuardConfig) Build() (proto.Message, error) {
 	return config, nil
 }
 
+// legacyNoise parses "wnoise", "wnoisecount", "wnoisedelay" and
+// "wpayloadsize". Every packet is the wnoise header ("quic", "random" or hex)
+// followed by wpayloadsize random bytes. It returns nil if wnoise is empty or
+// "none".
+func (c *WireGuardConfig) legacyNoise() (*wireguard.LegacyNoise, error) {
+	config := new(wireguard.LegacyNoise)
+	switch strings.ToLower(c.Wnoise) {
+	case "", "none":
+		return nil, nil
+	case "quic":
+		config.Type = "quic"
+	case "random":
+		config.Type = "random"
+	default:
+		header := c.Wnoise
+		if len(header)%2 != 0 {
+			header += "0"
+		}
+		header = header[:min(len(header), 100)]
+		b, err := hex.DecodeString(header)
+		if err != nil {
+			return nil, errors.New(`invalid "wnoise"`).Base(err)
+		}
+		config.Type = "hex"
+		config.Header = b
+	}
+
+	countMin, countMax, err := parseLegacyNoiseRange(c.Wnoisecount, 1, 2, 50)
+	if err != nil {
+		return nil, errors.New(`invalid "wnoisecount"`).Base(err)
+	}
+	delayMin, delayMax, err := parseLegacyNoiseRange(c.Wnoisedelay, 5, 10, 100)
+	if err != nil {
+		return nil, errors.New(`invalid "wnoisedelay"`).Base(err)
+	}
+	sizeMin, sizeMax, err := parseLegacyNoiseRange(c.Wpayloadsize, 5, 10, 100)
+	if err != nil {
+		return nil, errors.New(`invalid "wpayloadsize"`).Base(err)
+	}
+	if countMax == 0 {
+		return nil, nil
+	}
+	config.CountMin, config.CountMax = uint32(countMin), uint32(countMax)
+	config.DelayMin, config.DelayMax = uint32(delayMin), uint32(delayMax)
+	config.PayloadSizeMin, config.PayloadSizeMax = uint32(sizeMin), uint32(sizeMax)
+	return config, nil
+}
+
+// parseLegacyNoiseRange parses a range of the legacy WireGuard noise, which
+// defaults to defaultMin-defaultMax and is capped at limit.
+func parseLegacyNoiseRange(s string, defaultMin, defaultMax, limit int) (int, int, error) {
+	if s == "" {
+		return defaultMin, defaultMax, nil
+	}
+	from, to, err := ParseRangeString(s)
+	if err != nil {
+		return 0, 0, err
+	}
+	if from < 0 || to < 0 {
+		return 0, 0, errors.New("negative range: ", s)
+	}
+	if from > to {
+		from, to = to, from
+	}
+	return min(from, limit), min(to, limit), nil
+}
+
 func ParseWireGuardKey(str string) (string, error) {
 	var err error
 
@@ -3,6 +3,7 @@ package conf_test
 import (
 	"testing"
 
+	"github.com/GFW-knocker/Xray-core/common/noise"
 	. "github.com/GFW-knocker/Xray-core/infra/conf"
 	"github.com/GFW-knocker/Xray-core/proxy/wireguard"
 )
@@ -48,5 +49,60 @@ func TestWireGuardConfig(t *testing.T) {
 				NoKernelTun:    false,
 			},
 		},
+		{
+			Input: `{
+				"secretKey": "uJv5tZMDltsiYEn+kUwb0Ll/CXWhMkaSCWWhfPEZM3A=",
+				"noises": [
+					{
+						"type": "template",
+						"packet": "{quic}{rand:40-80}",
+						"count": "1-3"
+					}
+				],
+				"noiseKeepAlive": 30
+			}`,
+			Parser: loadJSON(creator),
+			Output: &wireguard.DeviceConfig{
+				SecretKey:      "b89bf9b5930396db226049fe914c1bd0b97f0975a13246920965a17cf1193370",
+				Endpoint:       []string{"10.0.0.1", "fd59:7153:2388:b5fd:0000:0000:0000:0001"},
+				Mtu:            1420,
+				DomainStrategy: wireguard.DeviceConfig_FORCE_IP,
+				Noise: &noise.Config{
+					Noises: []*noise.Noise{
+						{
+							Template: "{quic}{rand:40-80}",
+							CountMin: 1,
+							CountMax: 3,
+						},
+					},
+					KeepAlive: 30,
+				},
+			},
+		},
+		{
+			Input: `{
+				"secretKey": "uJv5tZMDltsiYEn+kUwb0Ll/CXWhMkaSCWWhfPEZM3A=",
+				"wnoise": "abc",
+				"wnoisecount": "3",
+				"wpayloadsize": "20-10"
+			}`,
+			Parser: loadJSON(creator),
+			Output: &wireguard.DeviceConfig{
+				SecretKey:      "b89bf9b5930396db226049fe914c1bd0b97f0975a13246920965a17cf1193370",
+				Endpoint:       []string{"10.0.0.1", "fd59:7153:2388:b5fd:0000:0000:0000:0001"},
+				Mtu:            1420,
+				DomainStrategy: wireguard.DeviceConfig_FORCE_IP,
+				LegacyNoise: &wireguard.LegacyNoise{
+					Type:           "hex",
+					Header:         []byte{0xab, 0xc0},
+					CountMin:       3,
+					CountMax:       3,
+					DelayMin:       5,
+					DelayMax:       10,
+					PayloadSizeMin: 10,
+					PayloadSizeMax: 20,
+				},
+			},
+		},
 	})
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/GFW-knocker/Xray-core/common/net"
 	"github.com/GFW-knocker/Xray-core/common/serial"
 	core "github.com/GFW-knocker/Xray-core/core"
+	"github.com/GFW-knocker/Xray-core/proxy/freedom"
 	"github.com/GFW-knocker/Xray-core/transport/internet"
 )
 
@@ -23,6 +24,7 @@ var (
 	inboundConfigLoader = NewJSONConfigLoader(ConfigCreatorCache{
 		"tunnel":        func() interface{} { return new(DokodemoConfig) },
 		"dokodemo-door": func() interface{} { return new(DokodemoConfig) },
+		"dns":           func() interface{} { return new(DNSInboundConfig) },
 		"http":          func() interface{} { return new(HTTPServerConfig) },
 		"shadowsocks":   func() interface{} { return new(ShadowsocksServerConfig) },
 		"mixed":         func() interface{} { return new(SocksServerConfig) },
@@ -352,6 +354,11 @@ func (c *OutboundDetourConfig) Build() (*core.OutboundHandlerConfig, error) {
 	if err != nil {
 		return nil, errors.New("failed to build outbound handler for protocol ", c.Protocol).Base(err)
 	}
+	// the ClientHello would be fragmented once by freedom and again by sockopt
+	if fc, ok := ts.(*freedom.Config); ok && senderSettings.StreamSettings.GetSocketSettings().GetFragment() != nil &&
+		(fc.Fragment != nil || len(fc.FragmentProfiles) > 0 || fc.AdaptiveFragment != nil || fc.ClientHello != nil) {
+		return nil, errors.New("fragment in freedom settings is conflicted with sockopt.fragment")
+	}
 
 	return &core.OutboundHandlerConfig{
 		SenderSettings: serial.ToTypedMessage(senderSettings),
@@ -210,6 +210,36 @@ func TestMuxConfig_Build(t *testing.T) {
 	}
 }
 
+func TestOutboundFragmentConflict(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings string
+		wantErr  bool
+	}{
+		{"sockopt only", `{}`, false},
+		{"fragment", `{"fragment": {"packets": "tlshello", "length": "10-20", "interval": "0"}}`, true},
+		{"fragment profiles", `{"fragmentProfiles": {"sni": {"strategy": "sni", "length": "1", "interval": "0"}}}`, true},
+		{"client hello", `{"clientHello": {"recordSize": "30-60"}}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &OutboundDetourConfig{}
+			common.Must(json.Unmarshal([]byte(`{
+				"protocol": "freedom",
+				"settings": `+tt.settings+`,
+				"streamSettings": {
+					"sockopt": {
+						"fragment": {"packets": "tlshello", "length": "10-20", "interval": "0"}
+					}
+				}
+			}`), c))
+			if _, err := c.Build(); (err != nil) != tt.wantErr {
+				t.Errorf("OutboundDetourConfig.Build() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestConfig_Override(t *testing.T) {
 	tests := []struct {
 		name string
@@ -0,0 +1,378 @@
+package dns
+
+import (
+	"context"
+	"encoding/base64"
+	go_errors "errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/GFW-knocker/Xray-core/common"
+	"github.com/GFW-knocker/Xray-core/common/buf"
+	"github.com/GFW-knocker/Xray-core/common/errors"
+	"github.com/GFW-knocker/Xray-core/common/net"
+	"github.com/GFW-knocker/Xray-core/common/protocol"
+	dns_proto "github.com/GFW-knocker/Xray-core/common/protocol/dns"
+	"github.com/GFW-knocker/Xray-core/common/session"
+	"github.com/GFW-knocker/Xray-core/common/signal"
+	"github.com/GFW-knocker/Xray-core/common/signal/semaphore"
+	"github.com/GFW-knocker/Xray-core/common/task"
+	"github.com/GFW-knocker/Xray-core/core"
+	"github.com/GFW-knocker/Xray-core/features/dns"
+	"github.com/GFW-knocker/Xray-core/features/policy"
+	"github.com/GFW-knocker/Xray-core/features/routing"
+	"github.com/GFW-knocker/Xray-core/transport/internet/stat"
+	mdns "github.com/miekg/dns"
+)
+
+func init() {
+	common.Must(common.RegisterConfig((*ServerConfig)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return NewServer(ctx, config.(*ServerConfig))
+	}))
+}
+
+// Server is a DNS server which answers queries with the DNS of the instance,
+// following its hosts, rules, fake DNS and cache.
+type Server struct {
+	config        *ServerConfig
+	client        dns.Client
+	policyManager policy.Manager
+}
+
+// NewServer creates a new Server object.
+func NewServer(ctx context.Context, config *ServerConfig) (*Server, error) {
+	s := &Server{
+		config: config,
+	}
+	if err := core.RequireFeatures(ctx, func(dnsClient dns.Client, policyManager policy.Manager) {
+		s.client = dnsClient
+		s.policyManager = policyManager
+	}); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Server) policy() policy.Session {
+	return s.policyManager.ForLevel(s.config.UserLevel)
+}
+
+// Network implements proxy.Inbound.
+func (s *Server) Network() []net.Network {
+	if len(s.config.Networks) > 0 {
+		return s.config.Networks
+	}
+	return []net.Network{net.Network_TCP, net.Network_UDP}
+}
+
+// Process implements proxy.Inbound.
+func (s *Server) Process(ctx context.Context, network net.Network, conn stat.Connection, _ routing.Dispatcher) error {
+	inbound := session.InboundFromContext(ctx)
+	inbound.Name = "dns"
+	inbound.User = &protocol.MemoryUser{
+		Level: s.config.UserLevel,
+	}
+
+	switch network {
+	case net.Network_TCP:
+		if len(s.config.DohPath) > 0 {
+			return s.serveDoH(ctx, conn)
+		}
+		return s.serveMessages(ctx, dns_proto.NewTCPReader(buf.NewReader(conn)), &dns_proto.TCPWriter{
+			Writer: buf.NewWriter(conn),
+		}, buf.Size)
+	case net.Network_UDP:
+		return s.serveMessages(ctx, &dns_proto.UDPReader{
+			Reader: buf.NewPacketReader(conn),
+		}, &dns_proto.UDPWriter{
+			Writer: buf.NewWriter(conn),
+		}, 0)
+	default:
+		return errors.New("unknown network: ", network)
+	}
+}
+
+// maxConcurrentQueries is the number of queries of a connection which are
+// answered at the same time.
+const maxConcurrentQueries = 64
+
+// serveMessages answers the queries from reader to writer. Responses are
+// truncated to size, or to the UDP payload size of the queries if size is 0.
+func (s *Server) serveMessages(ctx context.Context, reader dns_proto.MessageReader, writer dns_proto.MessageWriter, size int) error {
+	ctx, cancel := context.WithCancel(ctx)
+	timer := signal.CancelAfterInactivity(ctx, cancel, s.policy().Timeouts.ConnectionIdle)
+
+	var access sync.Mutex
+	// further queries are not read until one of these is answered
+	queries := semaphore.New(maxConcurrentQueries)
+	serve := func() error {
+		for {
+			b, err := reader.ReadMessage()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			timer.Update()
+
+			select {
+			case <-queries.Wait():
+			case <-ctx.Done():
+				b.Release()
+				return ctx.Err()
+			}
+			go func() {
+				defer queries.Signal()
+				defer b.Release()
+				resp, err := s.answer(ctx, b.Bytes())
+				if err != nil {
+					errors.LogInfoInner(ctx, err, "failed to answer DNS query")
+					return
+				}
+				packed, err := packResponse(resp, b.Bytes(), size)
+				if err != nil {
+					errors.LogInfoInner(ctx, err, "failed to pack DNS response")
+					return
+				}
+				access.Lock()
+				defer access.Unlock()
+				if err := writer.WriteMessage(buf.FromBytes(packed)); err != nil {
+					errors.LogInfoInner(ctx, err, "failed to write DNS response")
+				}
+				timer.Update()
+			}()
+		}
+	}
+
+	if err := task.Run(ctx, serve); err != nil {
+		return errors.New("connection ends").Base(err)
+	}
+	return nil
+}
+
+// packResponse packs resp to query, truncated to size. If size is 0, resp is
+// truncated to the UDP payload size of query.
+func packResponse(resp *mdns.Msg, query []byte, size int) ([]byte, error) {
+	if size == 0 {
+		size = mdns.MinMsgSize
+		req := new(mdns.Msg)
+		if err := req.Unpack(query); err == nil {
+			if opt := req.IsEdns0(); opt != nil && int(opt.UDPSize()) > size {
+				size = int(opt.UDPSize())
+			}
+		}
+	}
+	resp.Truncate(size)
+	return resp.Pack()
+}
+
+// answer returns the response to query.
+func (s *Server) answer(ctx context.Context, query []byte) (*mdns.Msg, error) {
+	req := new(mdns.Msg)
+	if err := req.Unpack(query); err != nil {
+		return nil, errors.New("failed to parse DNS query").Base(err)
+	}
+
+	resp := new(mdns.Msg)
+	resp.SetReply(req)
+	resp.RecursionAvailable = true
+	if opt := req.IsEdns0(); opt != nil {
+		resp.SetEdns0(opt.UDPSize(), false)
+	}
+
+	switch {
+	case req.Opcode != mdns.OpcodeQuery:
+		resp.Rcode = mdns.RcodeNotImplemented
+	case len(req.Question) != 1:
+		resp.Rcode = mdns.RcodeFormatError
+	default:
+		s.resolve(ctx, resp, req.Question[0])
+	}
+	return resp, nil
+}
+
+// resolve puts the answer to q into resp.
+func (s *Server) resolve(ctx context.Context, resp *mdns.Msg, q mdns.Question) {
+	var answer []mdns.RR
+	var err error
+	switch q.Qtype {
+	case mdns.TypeA, mdns.TypeAAAA:
+		var ips []net.IP
+		var ttl uint32
+		ips, ttl, err = s.client.LookupIP(q.Name, dns.IPOption{
+			IPv4Enable: q.Qtype == mdns.TypeA,
+			IPv6Enable: q.Qtype == mdns.TypeAAAA,
+			FakeEnable: true,
+		})
+		hdr := mdns.RR_Header{Name: q.Name, Rrtype: q.Qtype, Class: mdns.ClassINET, Ttl: ttl}
+		for _, ip := range ips {
+			if ip4 := ip.To4(); ip4 != nil && q.Qtype == mdns.TypeA {
+				answer = append(answer, &mdns.A{Hdr: hdr, A: ip4})
+			} else if ip4 == nil && q.Qtype == mdns.TypeAAAA {
+				answer = append(answer, &mdns.AAAA{Hdr: hdr, AAAA: ip.To16()})
+			}
+		}
+	default:
+		client, ok := s.client.(dns.RecordClient)
+		if !ok {
+			resp.Rcode = mdns.RcodeNotImplemented
+			return
+		}
+		var records []mdns.RR
+		var ttl uint32
+		records, ttl, err = client.LookupRecords(q.Name, q.Qtype)
+		// records may be cached, so they are copied before their TTLs are set
+		for _, rr := range records {
+			rr = mdns.Copy(rr)
+			rr.Header().Ttl = ttl
+			answer = append(answer, rr)
+		}
+	}
+
+	if err != nil && !go_errors.Is(err, dns.ErrEmptyResponse) {
+		if rcode := dns.RCodeFromError(err); rcode != 0 {
+			resp.Rcode = int(rcode)
+		} else {
+			errors.LogInfoInner(ctx, err, "failed to resolve ", mdns.Type(q.Qtype), " for ", q.Name)
+			resp.Rcode = mdns.RcodeServerFailure
+		}
+		return
+	}
+	resp.Answer = answer
+}
+
+// serveDoH serves conn as a DNS over HTTPS server, over HTTP/1.1 or HTTP/2.
+func (s *Server) serveDoH(ctx context.Context, conn stat.Connection) error {
+	var protocols http.Protocols
+	protocols.SetHTTP1(true)
+	protocols.SetUnencryptedHTTP2(true)
+	server := &http.Server{
+		Handler:           http.HandlerFunc(s.handleDoH),
+		Protocols:         &protocols,
+		ReadHeaderTimeout: s.policy().Timeouts.Handshake,
+		IdleTimeout:       s.policy().Timeouts.ConnectionIdle,
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			server.Close()
+		case <-done:
+		}
+	}()
+
+	err := server.Serve(newConnListener(conn))
+	if err == io.EOF || go_errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// handleDoH answers DNS over HTTPS requests, as in RFC 8484.
+func (s *Server) handleDoH(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != s.config.DohPath {
+		http.NotFound(w, r)
+		return
+	}
+
+	var query []byte
+	var err error
+	switch r.Method {
+	case http.MethodGet:
+		query, err = base64.RawURLEncoding.DecodeString(r.URL.Query().Get("dns"))
+	case http.MethodPost:
+		if r.Header.Get("Content-Type") != "application/dns-message" {
+			http.Error(w, "unsupported content type", http.StatusUnsupportedMediaType)
+			return
+		}
+		query, err = io.ReadAll(io.LimitReader(r.Body, mdns.MaxMsgSize))
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err != nil || len(query) == 0 {
+		http.Error(w, "invalid DNS query", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := s.answer(r.Context(), query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	packed, err := resp.Pack()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/dns-message")
+	if len(resp.Answer) > 0 {
+		ttl := resp.Answer[0].Header().Ttl
+		for _, rr := range resp.Answer {
+			ttl = min(ttl, rr.Header().Ttl)
+		}
+		w.Header().Set("Cache-Control", "max-age="+strconv.FormatUint(uint64(ttl), 10))
+	}
+	w.Write(packed)
+}
+
+// connListener is a net.Listener which accepts a single connection. Accept
+// fails once the connection is closed.
+type connListener struct {
+	conns  chan net.Conn
+	closed chan struct{}
+	once   sync.Once
+	addr   net.Addr
+}
+
+func newConnListener(conn net.Conn) *connListener {
+	l := &connListener{
+		conns:  make(chan net.Conn, 1),
+		closed: make(chan struct{}),
+		addr:   conn.LocalAddr(),
+	}
+	l.conns <- &listenerConn{Conn: conn, listener: l}
+	return l
+}
+
+func (l *connListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.closed:
+		return nil, io.EOF
+	}
+}
+
+func (l *connListener) Close() error {
+	l.once.Do(func() {
+		close(l.closed)
+	})
+	return nil
+}
+
+func (l *connListener) Addr() net.Addr {
+	return l.addr
+}
+
+// listenerConn is the connection of a connListener, which closes the listener
+// when closed.
+type listenerConn struct {
+	net.Conn
+	listener *connListener
+}
+
+func (c *listenerConn) Close() error {
+	err := c.Conn.Close()
+	c.listener.Close()
+	return err
+}
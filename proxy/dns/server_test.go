@@ -0,0 +1,206 @@
+package dns_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/GFW-knocker/Xray-core/app/dispatcher"
+	dnsapp "github.com/GFW-knocker/Xray-core/app/dns"
+	"github.com/GFW-knocker/Xray-core/app/policy"
+	"github.com/GFW-knocker/Xray-core/app/proxyman"
+	_ "github.com/GFW-knocker/Xray-core/app/proxyman/inbound"
+	_ "github.com/GFW-knocker/Xray-core/app/proxyman/outbound"
+	"github.com/GFW-knocker/Xray-core/common"
+	"github.com/GFW-knocker/Xray-core/common/net"
+	"github.com/GFW-knocker/Xray-core/common/serial"
+	"github.com/GFW-knocker/Xray-core/core"
+	dns_proxy "github.com/GFW-knocker/Xray-core/proxy/dns"
+	"github.com/GFW-knocker/Xray-core/proxy/freedom"
+	"github.com/GFW-knocker/Xray-core/testing/servers/tcp"
+	"github.com/GFW-knocker/Xray-core/testing/servers/udp"
+	"github.com/miekg/dns"
+)
+
+func TestDNSServer(t *testing.T) {
+	upstreamPort := udp.PickPort()
+	upstream := dns.Server{
+		Addr: "127.0.0.1:" + upstreamPort.String(),
+		Net:  "udp",
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+			ans := new(dns.Msg)
+			ans.SetReply(r)
+			switch q := r.Question[0]; {
+			case q.Name == "example.org." && q.Qtype == dns.TypeTXT:
+				rr, _ := dns.NewRR("example.org. 300 IN TXT \"hello\"")
+				ans.Answer = append(ans.Answer, rr)
+			case q.Name == "notexist.example.org.":
+				ans.Rcode = dns.RcodeNameError
+			}
+			w.WriteMsg(ans)
+		}),
+	}
+	defer upstream.Shutdown()
+	go upstream.ListenAndServe()
+	time.Sleep(time.Second)
+
+	serverPort := udp.PickPort()
+	dohPort := tcp.PickPort()
+	config := &core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dnsapp.Config{
+				NameServer: []*dnsapp.NameServer{
+					{
+						Address: &net.Endpoint{
+							Network: net.Network_UDP,
+							Address: net.NewIPOrDomain(net.LocalHostIP),
+							Port:    uint32(upstreamPort),
+						},
+					},
+				},
+				StaticHosts: []*dnsapp.Config_HostMapping{
+					{
+						Type:   dnsapp.DomainMatchingType_Full,
+						Domain: "example.com",
+						Ip:     [][]byte{{1, 2, 3, 4}},
+					},
+				},
+			}),
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&policy.Config{}),
+		},
+		Inbound: []*core.InboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&dns_proxy.ServerConfig{}),
+				ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+					PortList: &net.PortList{Range: []*net.PortRange{net.SinglePortRange(serverPort)}},
+					Listen:   net.NewIPOrDomain(net.LocalHostIP),
+				}),
+			},
+			{
+				ProxySettings: serial.ToTypedMessage(&dns_proxy.ServerConfig{
+					DohPath: "/dns-query",
+				}),
+				ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+					PortList: &net.PortList{Range: []*net.PortRange{net.SinglePortRange(dohPort)}},
+					Listen:   net.NewIPOrDomain(net.LocalHostIP),
+				}),
+			},
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	}
+
+	v, err := core.New(config)
+	common.Must(err)
+	common.Must(v.Start())
+	defer v.Close()
+
+	cases := []struct {
+		name   string
+		qtype  uint16
+		rcode  int
+		answer string
+	}{
+		{"example.com.", dns.TypeA, dns.RcodeSuccess, "1.2.3.4"},
+		{"example.org.", dns.TypeTXT, dns.RcodeSuccess, "\"hello\""},
+		{"notexist.example.org.", dns.TypeTXT, dns.RcodeNameError, ""},
+	}
+	check := func(t *testing.T, in *dns.Msg, rcode int, answer string) {
+		if in.Rcode != rcode {
+			t.Fatal("unexpected rcode: ", dns.RcodeToString[in.Rcode])
+		}
+		if answer == "" {
+			if len(in.Answer) != 0 {
+				t.Error("unexpected answer: ", in.Answer)
+			}
+			return
+		}
+		if len(in.Answer) != 1 {
+			t.Fatal("unexpected answer: ", in.Answer)
+		}
+		if s := in.Answer[0].String(); !bytes.HasSuffix([]byte(s), []byte("\t"+answer)) {
+			t.Error("unexpected answer: ", s)
+		}
+	}
+
+	for _, network := range []string{"udp", "tcp"} {
+		t.Run(network, func(t *testing.T) {
+			c := &dns.Client{Net: network, Timeout: time.Second * 5}
+			for _, tc := range cases {
+				m := new(dns.Msg)
+				m.SetQuestion(tc.name, tc.qtype)
+				in, _, err := c.Exchange(m, "127.0.0.1:"+serverPort.String())
+				common.Must(err)
+				check(t, in, tc.rcode, tc.answer)
+			}
+		})
+	}
+
+	t.Run("tcp/pipelined", func(t *testing.T) {
+		conn, err := dns.Dial("tcp", "127.0.0.1:"+serverPort.String())
+		common.Must(err)
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(time.Second * 5))
+		// more queries than are answered at the same time
+		const n = 200
+		for i := range n {
+			m := new(dns.Msg)
+			m.SetQuestion("example.com.", dns.TypeA)
+			m.Id = uint16(i)
+			common.Must(conn.WriteMsg(m))
+		}
+		answered := make(map[uint16]bool)
+		for range n {
+			in, err := conn.ReadMsg()
+			common.Must(err)
+			check(t, in, dns.RcodeSuccess, "1.2.3.4")
+			answered[in.Id] = true
+		}
+		if len(answered) != n {
+			t.Error("expect ", n, " answers but got ", len(answered))
+		}
+	})
+
+	var h2c http.Protocols
+	h2c.SetUnencryptedHTTP2(true)
+	clients := map[string]*http.Client{
+		"http1": {},
+		"h2c":   {Transport: &http.Transport{Protocols: &h2c}},
+	}
+	for name, client := range clients {
+		t.Run("doh/"+name, func(t *testing.T) {
+			for _, tc := range cases {
+				m := new(dns.Msg)
+				m.SetQuestion(tc.name, tc.qtype)
+				b, err := m.Pack()
+				common.Must(err)
+				resp, err := client.Post("http://127.0.0.1:"+dohPort.String()+"/dns-query", "application/dns-message", bytes.NewReader(b))
+				common.Must(err)
+				body, err := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				common.Must(err)
+				if resp.StatusCode != http.StatusOK {
+					t.Fatal("unexpected status: ", resp.Status)
+				}
+				in := new(dns.Msg)
+				common.Must(in.Unpack(body))
+				check(t, in, tc.rcode, tc.answer)
+			}
+
+			resp, err := client.Get("http://127.0.0.1:" + dohPort.String() + "/other")
+			common.Must(err)
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusNotFound {
+				t.Error("unexpected status of other paths: ", resp.Status)
+			}
+		})
+	}
+}
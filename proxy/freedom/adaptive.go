@@ -0,0 +1,245 @@
+package freedom
+
+import (
+	"container/list"
+	"context"
+	"io"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/GFW-knocker/Xray-core/common/errors"
+	"github.com/GFW-knocker/Xray-core/common/net"
+	"github.com/GFW-knocker/Xray-core/common/protocol/tls"
+	"github.com/GFW-knocker/Xray-core/features/stats"
+	"github.com/GFW-knocker/Xray-core/transport/internet"
+	"github.com/GFW-knocker/Xray-core/transport/internet/stat"
+)
+
+// maxAdaptiveEntries bounds the number of destinations remembered.
+const maxAdaptiveEntries = 10000
+
+type adaptiveEntry struct {
+	key string
+	// current is the profile in use, confirmed once it got a ServerHello back.
+	current     int
+	confirmed   bool
+	confirmedAt time.Time
+	// counters are the names of the stats counters of the entry.
+	counters map[string]bool
+}
+
+// AdaptiveFragmenter picks the fragment profile of every connection among
+// the configured ones, and learns from the outcome of the TLS handshakes
+// which one works for every destination.
+type AdaptiveFragmenter struct {
+	sync.Mutex
+	config     *AdaptiveFragment
+	strategies []internet.FragmentStrategy
+	stats      stats.Manager
+	entries    map[string]*list.Element // of *adaptiveEntry in lru
+	lru        *list.List               // most recently picked first
+}
+
+func NewAdaptiveFragmenter(config *AdaptiveFragment, sm stats.Manager) (*AdaptiveFragmenter, error) {
+	if len(config.Profiles) == 0 {
+		return nil, errors.New("no fragment profile")
+	}
+	a := &AdaptiveFragmenter{
+		config:  config,
+		stats:   sm,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+	for i, profile := range config.Profiles {
+		strategy, err := internet.NewFragmentStrategy(profile)
+		if err != nil {
+			return nil, errors.New("failed to create strategy of fragment profile ", i).Base(err)
+		}
+		a.strategies = append(a.strategies, strategy)
+	}
+	return a, nil
+}
+
+// Pick returns the index of the profile to use for key.
+func (a *AdaptiveFragmenter) Pick(key string) int {
+	a.Lock()
+	defer a.Unlock()
+	now := time.Now()
+	var entry *adaptiveEntry
+	if element, found := a.entries[key]; found {
+		a.lru.MoveToFront(element)
+		entry = element.Value.(*adaptiveEntry)
+	} else {
+		if a.lru.Len() >= maxAdaptiveEntries {
+			a.evict()
+		}
+		entry = &adaptiveEntry{key: key}
+		a.entries[key] = a.lru.PushFront(entry)
+	}
+	if entry.confirmed && a.config.ReprobeInterval > 0 && now.Sub(entry.confirmedAt) > time.Duration(a.config.ReprobeInterval)*time.Second {
+		entry.current = 0
+		entry.confirmed = false
+	}
+	return entry.current
+}
+
+// evict removes the least recently used entry, along with its counters.
+func (a *AdaptiveFragmenter) evict() {
+	oldest := a.lru.Remove(a.lru.Back()).(*adaptiveEntry)
+	for name := range oldest.counters {
+		a.stats.UnregisterCounter(name)
+	}
+	delete(a.entries, oldest.key)
+}
+
+// Report records whether a TLS handshake with the given profile succeeded.
+// A failure moves key on to the next profile.
+func (a *AdaptiveFragmenter) Report(tag, key string, profile int, success bool) {
+	a.Lock()
+	defer a.Unlock()
+	element, found := a.entries[key]
+	if !found {
+		return
+	}
+	entry := element.Value.(*adaptiveEntry)
+	if entry.current == profile {
+		if success {
+			if !entry.confirmed {
+				errors.LogInfo(context.Background(), "fragment profile ", profile, " works for ", key)
+			}
+			entry.confirmed = true
+			entry.confirmedAt = time.Now()
+		} else {
+			entry.current = (entry.current + 1) % len(a.strategies)
+			entry.confirmed = false
+		}
+	}
+
+	if !a.config.Stats || a.stats == nil {
+		return
+	}
+	prefix := "outbound>>>" + tag + ">>>fragment>>>" + key + ">>>"
+	outcome := "failure"
+	if success {
+		outcome = "success"
+	}
+	if c := a.counter(entry, prefix+"profile>>>"+strconv.Itoa(profile)+">>>"+outcome); c != nil {
+		c.Add(1)
+	}
+	winner := int64(-1)
+	if entry.confirmed {
+		winner = int64(entry.current)
+	}
+	if c := a.counter(entry, prefix+"winner"); c != nil {
+		c.Set(winner)
+	}
+}
+
+// counter returns the counter of name for entry, which is unregistered when
+// entry is evicted.
+func (a *AdaptiveFragmenter) counter(entry *adaptiveEntry, name string) stats.Counter {
+	c, err := stats.GetOrRegisterCounter(a.stats, name)
+	if err != nil {
+		return nil
+	}
+	if entry.counters == nil {
+		entry.counters = make(map[string]bool)
+	}
+	entry.counters[name] = true
+	return c
+}
+
+// WrapConn returns conn with its writes fragmented by the profile picked
+// for the destination, and its reads watched for the ServerHello.
+func (a *AdaptiveFragmenter) WrapConn(conn stat.Connection, dest, tag string, clientHello *internet.ClientHello) stat.Connection {
+	c := &adaptiveConn{
+		fragmenter:  a,
+		tag:         tag,
+		dest:        dest,
+		clientHello: clientHello,
+	}
+	if statConn, ok := conn.(*stat.CounterConnection); ok {
+		c.Conn = statConn.Connection
+		return &stat.CounterConnection{
+			Connection:   c,
+			ReadCounter:  statConn.ReadCounter,
+			WriteCounter: statConn.WriteCounter,
+		}
+	}
+	c.Conn = conn
+	return c
+}
+
+// adaptiveProbe is the profile a connection is trying.
+type adaptiveProbe struct {
+	key     string
+	profile int
+}
+
+type adaptiveConn struct {
+	net.Conn
+	fragmenter  *AdaptiveFragmenter
+	tag         string
+	dest        string
+	clientHello *internet.ClientHello
+
+	writer io.Writer
+	probe  atomic.Pointer[adaptiveProbe]
+	// header is the start of the response, until the ServerHello is seen.
+	header []byte
+	report sync.Once
+}
+
+func (c *adaptiveConn) Write(b []byte) (int, error) {
+	if c.writer == nil {
+		if _, ok := internet.IsClientHelloRecord(b); !ok {
+			// nothing to learn from
+			c.writer = c.Conn
+			return c.Conn.Write(b)
+		}
+		probe := &adaptiveProbe{key: c.dest}
+		if c.fragmenter.config.ByServerName {
+			if header, err := tls.SniffTLS(b); err == nil && header.Domain() != "" {
+				probe.key = header.Domain()
+			}
+		}
+		probe.profile = c.fragmenter.Pick(probe.key)
+		c.probe.Store(probe)
+		var w io.Writer = c.Conn
+		if fake := c.fragmenter.config.Profiles[probe.profile].Fake; fake != nil {
+			w = internet.NewFakeWriter(c.Conn, fake)
+		}
+		c.writer = internet.NewFragmentWriter(c.fragmenter.strategies[probe.profile], c.clientHello, w)
+	}
+	return c.writer.Write(b)
+}
+
+func (c *adaptiveConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if probe := c.probe.Load(); probe != nil && len(c.header) < 6 {
+		c.header = append(c.header, b[:min(n, 6-len(c.header))]...)
+		// a handshake record starting with a ServerHello
+		if len(c.header) == 6 {
+			c.done(probe, c.header[0] == 22 && c.header[5] == 2)
+		} else if err != nil {
+			c.done(probe, false)
+		}
+	}
+	return n, err
+}
+
+func (c *adaptiveConn) Close() error {
+	if probe := c.probe.Load(); probe != nil {
+		c.done(probe, false)
+	}
+	return c.Conn.Close()
+}
+
+// done reports the outcome of the handshake, only the first time.
+func (c *adaptiveConn) done(probe *adaptiveProbe, success bool) {
+	c.report.Do(func() {
+		c.fragmenter.Report(c.tag, probe.key, probe.profile, success)
+	})
+}
@@ -0,0 +1,93 @@
+package freedom_test
+
+import (
+	"context"
+	gotls "crypto/tls"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/GFW-knocker/Xray-core/app/stats"
+	"github.com/GFW-knocker/Xray-core/common"
+	"github.com/GFW-knocker/Xray-core/common/protocol/tls/cert"
+	. "github.com/GFW-knocker/Xray-core/proxy/freedom"
+	"github.com/GFW-knocker/Xray-core/transport/internet"
+)
+
+func TestAdaptiveFragmenter(t *testing.T) {
+	certificate, key := cert.MustGenerate(nil, cert.CommonName("www.example.com")).ToPEM()
+	keyPair, err := gotls.X509KeyPair(certificate, key)
+	common.Must(err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	common.Must(err)
+	defer listener.Close()
+	// the first connection is blocked after the client hello, the others
+	// complete their handshake
+	go func() {
+		for i := 0; ; i++ {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			if i == 0 {
+				io.ReadFull(conn, make([]byte, 5))
+				conn.Close()
+				continue
+			}
+			go func() {
+				server := gotls.Server(conn, &gotls.Config{Certificates: []gotls.Certificate{keyPair}})
+				server.Handshake()
+				server.Close()
+			}()
+		}
+	}()
+
+	sm, err := stats.NewManager(context.Background(), &stats.Config{})
+	common.Must(err)
+	fragmenter, err := NewAdaptiveFragmenter(&AdaptiveFragment{
+		Profiles: []*Fragment{
+			{Strategy: internet.FragmentStrategySNI},
+			{Strategy: internet.FragmentStrategyTLSRecord, LengthMin: 50, LengthMax: 100},
+		},
+		ByServerName: true,
+		Stats:        true,
+	}, sm)
+	common.Must(err)
+
+	for i, expected := range []bool{false, true, true} {
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		common.Must(err)
+		client := gotls.Client(fragmenter.WrapConn(conn, listener.Addr().String(), "direct", nil), &gotls.Config{
+			ServerName:         "www.example.com",
+			InsecureSkipVerify: true,
+		})
+		if err := client.Handshake(); (err == nil) != expected {
+			t.Error("unexpected handshake result of connection ", i, ": ", err)
+		}
+		client.Close()
+	}
+
+	if profile := fragmenter.Pick("www.example.com"); profile != 1 {
+		t.Error("expect profile 1 to be picked but got ", profile)
+	}
+	const prefix = "outbound>>>direct>>>fragment>>>www.example.com>>>"
+	for name, value := range map[string]int64{
+		"profile>>>0>>>failure": 1,
+		"profile>>>1>>>success": 2,
+		"winner":                1,
+	} {
+		if c := sm.GetCounter(prefix + name); c == nil || c.Value() != value {
+			t.Error("unexpected counter ", name)
+		}
+	}
+
+	// as many other destinations as are remembered evict the first one
+	for i := 0; i < 10000; i++ {
+		fragmenter.Pick(strconv.Itoa(i))
+	}
+	if c := sm.GetCounter(prefix + "winner"); c != nil {
+		t.Error("expect the counters of evicted destinations to be unregistered")
+	}
+}
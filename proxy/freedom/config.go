@@ -1,5 +1,18 @@
 package freedom
 
+import (
+	"github.com/GFW-knocker/Xray-core/common/noise"
+	"github.com/GFW-knocker/Xray-core/transport/internet"
+)
+
+// Noise is the noise of Config.Noises, which moved to common/noise so that
+// other outbounds and transports can send it too.
+type Noise = noise.Noise
+
+// Fragment is the fragment of Config.Fragment, which moved to
+// transport/internet so that sockopt can fragment connections too.
+type Fragment = internet.Fragment
+
 var strategy = [][]byte{
 	//              name        strategy,   prefer, fallback
 	{0, 0, 0}, //   AsIs        none,       /,      /
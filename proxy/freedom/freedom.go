@@ -1,19 +1,17 @@
 package freedom
 
 import (
-	"bytes"
 	"context"
 	"crypto/rand"
 	"io"
 	"math/big"
-	"regexp"
-	"time"
 
 	"github.com/GFW-knocker/Xray-core/common"
 	"github.com/GFW-knocker/Xray-core/common/buf"
 	"github.com/GFW-knocker/Xray-core/common/dice"
 	"github.com/GFW-knocker/Xray-core/common/errors"
 	"github.com/GFW-knocker/Xray-core/common/net"
+	"github.com/GFW-knocker/Xray-core/common/noise"
 	"github.com/GFW-knocker/Xray-core/common/platform"
 	"github.com/GFW-knocker/Xray-core/common/retry"
 	"github.com/GFW-knocker/Xray-core/common/session"
@@ -37,8 +35,8 @@ var useSplice bool
 func init() {
 	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
 		h := new(Handler)
-		if err := core.RequireFeatures(ctx, func(pm policy.Manager, d dns.Client) error {
-			return h.Init(config.(*Config), pm, d)
+		if err := core.RequireFeatures(ctx, func(pm policy.Manager, d dns.Client, sm stats.Manager) error {
+			return h.Init(config.(*Config), pm, d, sm)
 		}); err != nil {
 			return nil, err
 		}
@@ -54,17 +52,45 @@ func init() {
 
 // Handler handles Freedom connections.
 type Handler struct {
-	policyManager policy.Manager
-	dns           dns.Client
-	config        *Config
+	policyManager    policy.Manager
+	dns              dns.Client
+	config           *Config
+	fragmentStrategy internet.FragmentStrategy
+	fragmentProfiles map[string]internet.FragmentStrategy
+	adaptive         *AdaptiveFragmenter
 }
 
 // Init initializes the Handler with necessary parameters.
-func (h *Handler) Init(config *Config, pm policy.Manager, d dns.Client) error {
+func (h *Handler) Init(config *Config, pm policy.Manager, d dns.Client, sm stats.Manager) error {
 	h.config = config
 	h.policyManager = pm
 	h.dns = d
 
+	if config.Fragment != nil {
+		strategy, err := internet.NewFragmentStrategy(config.Fragment)
+		if err != nil {
+			return errors.New("failed to create fragment strategy").Base(err)
+		}
+		h.fragmentStrategy = strategy
+	}
+
+	h.fragmentProfiles = make(map[string]internet.FragmentStrategy, len(config.FragmentProfiles))
+	for name, fragment := range config.FragmentProfiles {
+		strategy, err := internet.NewFragmentStrategy(fragment)
+		if err != nil {
+			return errors.New("failed to create strategy of fragment profile ", name).Base(err)
+		}
+		h.fragmentProfiles[name] = strategy
+	}
+
+	if config.AdaptiveFragment != nil {
+		adaptive, err := NewAdaptiveFragmenter(config.AdaptiveFragment, sm)
+		if err != nil {
+			return errors.New("failed to create adaptive fragment").Base(err)
+		}
+		h.adaptive = adaptive
+	}
+
 	return nil
 }
 
@@ -189,31 +215,77 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, dialer inte
 		}
 	}, plcy.Timeouts.ConnectionIdle)
 
+	fragment, fragmentStrategy := h.config.Fragment, h.fragmentStrategy
+	if name := ob.FragmentProfile; name != "" {
+		if strategy, found := h.fragmentProfiles[name]; found {
+			fragment, fragmentStrategy = h.config.FragmentProfiles[name], strategy
+		} else {
+			errors.LogWarning(ctx, "fragment profile ", name, " not found")
+		}
+	}
+	noiseConfig := &noise.Config{Noises: h.config.Noises, KeepAlive: h.config.NoiseKeepAlive}
+	if name := ob.NoiseProfile; name != "" {
+		if profile, found := h.config.NoiseProfiles[name]; found {
+			noiseConfig = profile
+		} else {
+			errors.LogWarning(ctx, "noise profile ", name, " not found")
+		}
+	}
+
+	// a fragment profile picked by routing takes precedence over learning one
+	adaptive := h.adaptive != nil && ob.FragmentProfile == "" && destination.Network == net.Network_TCP && !isTLSConn(conn)
+	if adaptive {
+		conn = h.adaptive.WrapConn(conn, destination.NetAddr(), ob.Tag, h.config.ClientHello)
+	}
+
+	var quicFragmenter *QUICFragmenter
+	if destination.Network == net.Network_UDP && h.config.QuicFragment != nil && h.config.QuicFragment.Packets > 1 {
+		quicFragmenter = NewQUICFragmenter(h.config.QuicFragment)
+	}
+
 	requestDone := func() error {
 		defer timer.SetTimeout(plcy.Timeouts.DownlinkOnly)
 
 		var writer buf.Writer
 		if destination.Network == net.Network_TCP {
-			if h.config.Fragment != nil {
-				errors.LogDebug(ctx, "FRAGMENT", h.config.Fragment.PacketsFrom, h.config.Fragment.PacketsTo, h.config.Fragment.LengthMin, h.config.Fragment.LengthMax,
-					h.config.Fragment.IntervalMin, h.config.Fragment.IntervalMax)
-				writer = buf.NewWriter(&FragmentWriter{
-					fragment: h.config.Fragment,
-					writer:   conn,
-				})
+			// the client hello can only be reshaped when it passes through in plaintext
+			clientHello := h.config.ClientHello
+			if isTLSConn(conn) {
+				clientHello = nil
+			}
+			if adaptive {
+				// fragmented by the wrapped conn
+				writer = buf.NewWriter(conn)
+			} else if fragment != nil || clientHello != nil {
+				if fragment != nil {
+					errors.LogDebug(ctx, "FRAGMENT", fragment.Strategy, fragment.PacketsFrom, fragment.PacketsTo, fragment.LengthMin, fragment.LengthMax,
+						fragment.IntervalMin, fragment.IntervalMax)
+				}
+				var w io.Writer = conn
+				if fragment != nil && fragment.Fake != nil && !isTLSConn(conn) {
+					w = internet.NewFakeWriter(conn, fragment.Fake)
+				}
+				writer = buf.NewWriter(internet.NewFragmentWriter(fragmentStrategy, clientHello, w))
 			} else {
 				writer = buf.NewWriter(conn)
 			}
 		} else {
 			writer = NewPacketWriter(conn, h, ctx, UDPOverride, destination)
-			if h.config.Noises != nil {
-				errors.LogDebug(ctx, "NOISE", h.config.Noises)
+			if len(noiseConfig.Noises) > 0 {
+				errors.LogDebug(ctx, "NOISE", noiseConfig.Noises)
 				writer = &NoisePacketWriter{
-					Writer:         writer,
-					noises:         h.config.Noises,
-					noiseKeepAlive: h.config.NoiseKeepAlive,
-					firstWrite:     true,
-					UDPOverride:    UDPOverride,
+					Writer:      writer,
+					noise:       noiseConfig,
+					firstWrite:  true,
+					UDPOverride: UDPOverride,
+					port:        destination.Port,
+				}
+			}
+			if quicFragmenter != nil {
+				errors.LogDebug(ctx, "QUIC FRAGMENT", h.config.QuicFragment)
+				writer = &QUICFragmentWriter{
+					Writer:     writer,
+					fragmenter: quicFragmenter,
 				}
 			}
 		}
@@ -243,6 +315,12 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, dialer inte
 			reader = buf.NewReader(conn)
 		} else {
 			reader = NewPacketReader(conn, UDPOverride, destination)
+			if quicFragmenter != nil {
+				reader = &QUICFragmentReader{
+					Reader:     reader,
+					fragmenter: quicFragmenter,
+				}
+			}
 		}
 		if err := buf.Copy(reader, output, buf.UpdateActivity(timer)); err != nil {
 			return errors.New("failed to process response").Base(err)
@@ -456,12 +534,11 @@ func (w *PacketWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
 
 type NoisePacketWriter struct {
 	buf.Writer
-	noises         []*Noise
-	noiseKeepAlive uint32
-	firstWrite     bool
-	UDPOverride    net.Destination
-	stopChan       chan struct{} // Channel to stop the keepalive goroutine
-	ticker         *time.Ticker  // Ticker for periodic noise
+	noise       *noise.Config
+	firstWrite  bool
+	UDPOverride net.Destination
+	port        net.Port // destination port, for noise templates
+	sender      *noise.Sender
 }
 
 // MultiBuffer writer with Noise before first packet
@@ -473,82 +550,26 @@ func (w *NoisePacketWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
 			return w.Writer.WriteMultiBuffer(mb)
 		}
 
-		// Send initial noise
-		if err := w.sendNoise(); err != nil {
-			return err
-		}
-
-		// Start keepalive goroutine
-		if w.noiseKeepAlive > 0 {
-			w.stopChan = make(chan struct{})
-			w.ticker = time.NewTicker(time.Duration(w.noiseKeepAlive) * time.Second)
-			go w.keepNoiseAlive()
-		}
-
-	}
-	return w.Writer.WriteMultiBuffer(mb)
-}
-
-func (w *NoisePacketWriter) sendNoise() error {
-	var noise []byte
-	var err error
-	var count int64
-	var delay time.Duration
-
-	for _, n := range w.noises {
-		//User input string or base64 encoded string
-		if n.Packet != nil {
-			noise = n.Packet
-		} else {
-			//Random noise
-			noise, err = GenerateRandomBytes(randBetween(int64(n.LengthMin), int64(n.LengthMax)))
-		}
+		// Send initial noise, then keep repeating it if configured
+		sender, err := noise.NewSender(w.noise, w.port, func(b []byte) error {
+			return w.Writer.WriteMultiBuffer(buf.MultiBuffer{buf.FromBytes(b)})
+		})
 		if err != nil {
 			return err
 		}
-
-		count = randBetween(int64(n.CountMin), int64(n.CountMax))
-		if count < 1 {
-			count = 1
-		} else if count > 100 {
-			count = 100
-		}
-
-		if n.DelayMin != 0 || n.DelayMax != 0 {
-			delay = time.Duration(randBetween(int64(n.DelayMin), int64(n.DelayMax))) * time.Millisecond
-		} else {
-			delay = time.Duration(0)
-		}
-
-		for range count {
-			w.Writer.WriteMultiBuffer(buf.MultiBuffer{buf.FromBytes(noise)})
-			if delay > 0 {
-				time.Sleep(delay)
-			}
-		}
-
-	}
-	return nil
-}
-
-func (w *NoisePacketWriter) keepNoiseAlive() {
-	for {
-		select {
-		case <-w.ticker.C:
-			if err := w.sendNoise(); err != nil {
-				break
-			}
-		case <-w.stopChan:
-			w.ticker.Stop()
-			return
+		w.sender = sender
+		if err := w.sender.Send(); err != nil {
+			errors.LogInfoInner(context.Background(), err, "failed to send noise to ", w.port)
 		}
+		w.sender.KeepAlive()
 	}
+	return w.Writer.WriteMultiBuffer(mb)
 }
 
 // Close implements io.Closer
 func (w *NoisePacketWriter) Close() error {
-	if w.stopChan != nil {
-		close(w.stopChan)
+	if w.sender != nil {
+		w.sender.Close()
 	}
 	if closer, ok := w.Writer.(io.Closer); ok {
 		return closer.Close()
@@ -556,132 +577,6 @@ func (w *NoisePacketWriter) Close() error {
 	return nil
 }
 
-type FragmentWriter struct {
-	fragment *Fragment
-	writer   io.Writer
-	count    uint64
-}
-
-var re = regexp.MustCompile("(?i)(\r\nHost:.*\r\n)")
-
-func (f *FragmentWriter) Write(b []byte) (int, error) {
-	f.count++
-
-	if f.fragment.FakeHost {
-
-		if f.count < 500 {
-			if len(b) >= 5 {
-				if (b[0] == 'P' && b[1] == 'O' && b[2] == 'S' && b[3] == 'T' && b[4] == ' ') ||
-					(b[0] == 'G' && b[1] == 'E' && b[2] == 'T' && b[3] == ' ') {
-
-					firstMatch := re.FindSubmatch(b)
-					if len(firstMatch) > 1 {
-						var new_b []byte
-						old_h := firstMatch[1]
-						new_h := []byte("\r\n" + f.fragment.Host1Header + f.fragment.Host1Domain + string(old_h) + f.fragment.Host2Header + f.fragment.Host2Domain + "\r\n")
-						new_b = bytes.Replace(b, old_h, new_h, 1)
-						return f.writer.Write(new_b)
-					}
-				}
-			}
-		}
-		return f.writer.Write(b)
-	}
-
-	if f.fragment.PacketsFrom == 0 && f.fragment.PacketsTo == 1 {
-		if f.count != 1 || len(b) <= 5 || b[0] != 22 {
-			return f.writer.Write(b)
-		}
-		recordLen := 5 + ((int(b[3]) << 8) | int(b[4]))
-		if len(b) < recordLen { // maybe already fragmented somehow
-			return f.writer.Write(b)
-		}
-		data := b[5:recordLen]
-		buf := make([]byte, 1024)
-		queue := make([]byte, 2048)
-		n_queue := int(randBetween(int64(2), int64(4)))
-		L_queue := 0
-		c_queue := 0
-		for from := 0; ; {
-			to := from + int(randBetween(int64(f.fragment.LengthMin), int64(f.fragment.LengthMax)))
-			if to > len(data) {
-				to = len(data)
-			}
-			copy(buf[:3], b)
-			copy(buf[5:], data[from:to])
-			l := to - from
-			from = to
-			buf[3] = byte(l >> 8)
-			buf[4] = byte(l)
-
-			if c_queue < n_queue {
-				if l > 0 {
-					copy(queue[L_queue:], buf[:5+l])
-					L_queue = L_queue + 5 + l
-				}
-				c_queue = c_queue + 1
-			} else {
-				if l > 0 {
-					copy(queue[L_queue:], buf[:5+l])
-					L_queue = L_queue + 5 + l
-				}
-
-				if L_queue > 0 {
-					_, err := f.writer.Write(queue[:L_queue])
-					time.Sleep(time.Duration(randBetween(int64(f.fragment.IntervalMin), int64(f.fragment.IntervalMax))) * time.Millisecond)
-					if err != nil {
-						return 0, err
-					}
-				}
-
-				L_queue = 0
-				c_queue = 0
-
-			}
-
-			if from == len(data) {
-				if L_queue > 0 {
-					_, err := f.writer.Write(queue[:L_queue])
-					time.Sleep(time.Duration(randBetween(int64(f.fragment.IntervalMin), int64(f.fragment.IntervalMax))) * time.Millisecond)
-					L_queue = 0
-					c_queue = 0
-
-					if err != nil {
-						return 0, err
-					}
-				}
-				if len(b) > recordLen {
-					n, err := f.writer.Write(b[recordLen:])
-					if err != nil {
-						return recordLen + n, err
-					}
-				}
-				return len(b), nil
-			}
-		}
-
-	}
-
-	if f.fragment.PacketsFrom != 0 && (f.count < f.fragment.PacketsFrom || f.count > f.fragment.PacketsTo) {
-		return f.writer.Write(b)
-	}
-	for from := 0; ; {
-		to := from + int(randBetween(int64(f.fragment.LengthMin), int64(f.fragment.LengthMax)))
-		if to > len(b) {
-			to = len(b)
-		}
-		n, err := f.writer.Write(b[from:to])
-		from += n
-		time.Sleep(time.Duration(randBetween(int64(f.fragment.IntervalMin), int64(f.fragment.IntervalMax))) * time.Millisecond)
-		if err != nil {
-			return from, err
-		}
-		if from >= len(b) {
-			return from, nil
-		}
-	}
-}
-
 func randBetween(left int64, right int64) int64 {
 	if left == right {
 		return left
@@ -689,14 +584,3 @@ func randBetween(left int64, right int64) int64 {
 	bigInt, _ := rand.Int(rand.Reader, big.NewInt(right-left+1))
 	return left + bigInt.Int64()
 }
-
-func GenerateRandomBytes(n int64) ([]byte, error) {
-	b := make([]byte, n)
-	_, err := rand.Read(b)
-	// Note that err == nil only if we read len(b) bytes.
-	if err != nil {
-		return nil, err
-	}
-
-	return b, nil
-}
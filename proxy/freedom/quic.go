@@ -0,0 +1,331 @@
+package freedom
+
+import (
+	"bytes"
+	"crypto/rand"
+	"sync"
+
+	"github.com/GFW-knocker/Xray-core/common/buf"
+	"github.com/GFW-knocker/Xray-core/common/dice"
+	"github.com/GFW-knocker/Xray-core/common/protocol/quic"
+)
+
+// quicFlow is the state of one QUIC connection whose Initial packets are
+// being fragmented, keyed by the source connection ID of the client.
+type quicFlow struct {
+	destConnID []byte
+	clientKeys *quic.InitialKeys
+	serverKeys *quic.InitialKeys
+	// largest is the largest packet number sent by the client, before shifting.
+	largest uint64
+	sent    bool
+	// shift is how many extra packet numbers the split ClientHello took.
+	shift uint64
+}
+
+// QUICFragmenter splits the CRYPTO data of the first Initial packet of QUIC
+// connections over several Initial packets. Since this takes extra packet
+// numbers, the packet numbers of later client Initial packets are shifted
+// accordingly, and the ACK frames in server Initial packets are shifted back
+// so that the client never sees acknowledgements for packets it didn't send.
+type QUICFragmenter struct {
+	sync.Mutex
+	config *QuicFragment
+	flows  map[string]*quicFlow
+}
+
+func NewQUICFragmenter(config *QuicFragment) *QUICFragmenter {
+	return &QUICFragmenter{
+		config: config,
+		flows:  make(map[string]*quicFlow),
+	}
+}
+
+// flow returns the flow of the client Initial packet with given header,
+// updating its keys if the destination connection ID changed after a Retry.
+func (f *QUICFragmenter) flow(packet []byte, version uint32, destConnID, srcConnID []byte) (*quicFlow, error) {
+	flow, found := f.flows[string(srcConnID)]
+	if found && (bytes.Equal(flow.destConnID, destConnID) || flow.tryOpen(packet)) {
+		return flow, nil
+	}
+	clientKeys, err := quic.NewInitialKeys(version, destConnID, true)
+	if err != nil {
+		return nil, err
+	}
+	serverKeys, err := quic.NewInitialKeys(version, destConnID, false)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		flow = &quicFlow{}
+		f.flows[string(srcConnID)] = flow
+	}
+	flow.destConnID = append([]byte(nil), destConnID...)
+	flow.clientKeys = clientKeys
+	flow.serverKeys = serverKeys
+	return flow, nil
+}
+
+func (flow *quicFlow) tryOpen(packet []byte) bool {
+	_, _, err := flow.clientKeys.Open(packet)
+	return err == nil
+}
+
+// Outgoing rewrites a datagram sent by the client. It returns the datagrams
+// to send instead, or nil if b should be sent as is.
+func (f *QUICFragmenter) Outgoing(b []byte) [][]byte {
+	version, destConnID, srcConnID, err := quic.InitialHeader(b)
+	if err != nil {
+		return nil
+	}
+	f.Lock()
+	defer f.Unlock()
+	flow, err := f.flow(b, version, destConnID, srcConnID)
+	if err != nil {
+		return nil
+	}
+	p, rest, err := flow.clientKeys.Open(b)
+	if err != nil {
+		return nil
+	}
+	pn := p.PacketNumber
+	if flow.sent {
+		pn = quic.DecodePacketNumber(flow.largest, p.PacketNumber, p.PacketNumberLen)
+	}
+	if !flow.sent || pn > flow.largest {
+		flow.largest = pn
+	}
+	first := !flow.sent && pn == 0
+	flow.sent = true
+
+	if first && f.config.Packets > 1 {
+		frames, err := quic.ParseFrames(p.Payload)
+		if err != nil {
+			return nil
+		}
+		packets := splitCryptoFrames(frames, int(f.config.Packets))
+		if len(packets) > 1 {
+			flow.shift = uint64(len(packets) - 1)
+			return f.fragment(flow, p, packets, rest)
+		}
+	}
+	if flow.shift == 0 {
+		return nil
+	}
+	p.PacketNumber = pn + flow.shift
+	p.PacketNumberLen = 4
+	return [][]byte{append(flow.clientKeys.Seal(nil, p), rest...)}
+}
+
+// fragment seals the payloads into consecutive Initial packets, each in its
+// own padded datagram, and mixes in the decoys.
+func (f *QUICFragmenter) fragment(flow *quicFlow, p *quic.InitialPacket, payloads [][]byte, rest []byte) [][]byte {
+	datagrams := make([][]byte, 0, len(payloads)+int(f.config.Decoys))
+	for i, payload := range payloads {
+		packet := *p
+		packet.PacketNumber = uint64(i)
+		packet.PacketNumberLen = 4
+		packet.Payload = payload
+		var tail []byte
+		if i == len(payloads)-1 {
+			tail = rest
+		}
+		datagrams = append(datagrams, append(sealPadded(flow.clientKeys, &packet, len(tail)), tail...))
+	}
+	for range f.config.Decoys {
+		datagrams = append(datagrams, decoyInitial(p))
+	}
+	if f.config.Reorder {
+		for i := len(datagrams) - 1; i > 0; i-- {
+			j := dice.Roll(i + 1)
+			datagrams[i], datagrams[j] = datagrams[j], datagrams[i]
+		}
+	}
+	return datagrams
+}
+
+// sealPadded seals p with PADDING frames appended, so that its datagram
+// reaches the minimum size clients must send Initial packets in.
+func sealPadded(keys *quic.InitialKeys, p *quic.InitialPacket, extra int) []byte {
+	if size := keys.SealedSize(p) + extra; size < quic.MinInitialDatagramSize {
+		payload := make([]byte, len(p.Payload), len(p.Payload)+quic.MinInitialDatagramSize-size)
+		copy(payload, p.Payload)
+		p.Payload = append(payload, make([]byte, quic.MinInitialDatagramSize-size)...)
+	}
+	return keys.Seal(nil, p)
+}
+
+// decoyInitial returns an Initial packet with the header of p, protected with
+// keys of a random connection ID. The server fails to decrypt and drops it.
+func decoyInitial(p *quic.InitialPacket) []byte {
+	connID := make([]byte, 8)
+	rand.Read(connID)
+	keys, err := quic.NewInitialKeys(p.Version, connID, true)
+	if err != nil {
+		return nil
+	}
+	payload := make([]byte, randBetween(200, 1000))
+	rand.Read(payload)
+	decoy := *p
+	decoy.PacketNumber = uint64(randBetween(0, 1<<16))
+	decoy.PacketNumberLen = 4
+	decoy.Payload = payload
+	return sealPadded(keys, &decoy, 0)
+}
+
+// splitCryptoFrames spreads the CRYPTO data of frames evenly over n payloads.
+// Other frames except PADDING go into the first payload.
+func splitCryptoFrames(frames []*quic.Frame, n int) [][]byte {
+	total := 0
+	for _, frame := range frames {
+		if frame.Type == quic.FrameCrypto {
+			total += len(frame.Data)
+		}
+	}
+	if total < n {
+		return nil
+	}
+	size := (total + n - 1) / n
+	payloads := make([][]byte, 1, n)
+	for _, frame := range frames {
+		if frame.Type != quic.FrameCrypto && frame.Type != quic.FramePadding {
+			payloads[0] = quic.AppendFrame(payloads[0], frame)
+		}
+	}
+	room := size
+	for _, frame := range frames {
+		if frame.Type != quic.FrameCrypto {
+			continue
+		}
+		offset, data := frame.Offset, frame.Data
+		for len(data) > 0 {
+			if room == 0 {
+				payloads = append(payloads, nil)
+				room = size
+			}
+			l := min(room, len(data))
+			last := len(payloads) - 1
+			payloads[last] = quic.AppendFrame(payloads[last], &quic.Frame{Type: quic.FrameCrypto, Offset: offset, Data: data[:l]})
+			offset += uint64(l)
+			data = data[l:]
+			room -= l
+		}
+	}
+	return payloads
+}
+
+// Incoming rewrites a datagram received from the server in place of b. It
+// returns nil if b should be delivered as is.
+func (f *QUICFragmenter) Incoming(b []byte) []byte {
+	_, destConnID, _, err := quic.InitialHeader(b)
+	if err != nil {
+		return nil
+	}
+	f.Lock()
+	defer f.Unlock()
+	flow, found := f.flows[string(destConnID)]
+	if !found || flow.shift == 0 {
+		return nil
+	}
+	p, rest, err := flow.serverKeys.Open(b)
+	if err != nil {
+		return nil
+	}
+	frames, err := quic.ParseFrames(p.Payload)
+	if err != nil {
+		return nil
+	}
+	payload := make([]byte, 0, len(p.Payload))
+	for _, frame := range frames {
+		if frame.Type == quic.FrameAck || frame.Type == quic.FrameAckECN {
+			frame.AckRanges = shiftAckRanges(frame.AckRanges, flow.shift)
+			if len(frame.AckRanges) == 0 {
+				continue
+			}
+		}
+		payload = quic.AppendFrame(payload, frame)
+	}
+	p.Payload = payload
+	return append(flow.serverKeys.Seal(nil, p), rest...)
+}
+
+// shiftAckRanges maps the packet numbers acknowledged by the server back to
+// the ones the client sent. The client packet 0 is only acknowledged when all
+// the packets it was split into are.
+func shiftAckRanges(ranges []quic.AckRange, shift uint64) []quic.AckRange {
+	shifted := make([]quic.AckRange, 0, len(ranges))
+	for _, r := range ranges {
+		if r.Largest < shift {
+			continue
+		}
+		s := quic.AckRange{Largest: r.Largest - shift}
+		switch {
+		case r.Smallest == 0:
+			s.Smallest = 0
+		case r.Smallest <= shift:
+			if s.Largest == 0 {
+				continue
+			}
+			s.Smallest = 1
+		default:
+			s.Smallest = r.Smallest - shift
+		}
+		if last := len(shifted) - 1; last >= 0 && s.Largest+1 >= shifted[last].Smallest {
+			shifted[last].Smallest = min(shifted[last].Smallest, s.Smallest)
+			continue
+		}
+		shifted = append(shifted, s)
+	}
+	return shifted
+}
+
+// QUICFragmentWriter fragments the QUIC Initial packets written through it.
+type QUICFragmentWriter struct {
+	buf.Writer
+	fragmenter *QUICFragmenter
+}
+
+func (w *QUICFragmentWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	for i, b := range mb {
+		datagrams := w.fragmenter.Outgoing(b.Bytes())
+		if datagrams == nil {
+			continue
+		}
+		fragments := make(buf.MultiBuffer, 0, len(datagrams))
+		for _, datagram := range datagrams {
+			fragment := buf.FromBytes(datagram)
+			if b.UDP != nil {
+				dest := *b.UDP
+				fragment.UDP = &dest
+			}
+			fragments = append(fragments, fragment)
+		}
+		if err := w.Writer.WriteMultiBuffer(append(mb[:i:i], fragments...)); err != nil {
+			buf.ReleaseMulti(mb[i:])
+			return err
+		}
+		b.Release()
+		return w.WriteMultiBuffer(mb[i+1:])
+	}
+	return w.Writer.WriteMultiBuffer(mb)
+}
+
+// QUICFragmentReader undoes the packet number shift in the ACK frames of
+// QUIC Initial packets read through it.
+type QUICFragmentReader struct {
+	buf.Reader
+	fragmenter *QUICFragmenter
+}
+
+func (r *QUICFragmentReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	mb, err := r.Reader.ReadMultiBuffer()
+	for i, b := range mb {
+		if datagram := r.fragmenter.Incoming(b.Bytes()); datagram != nil {
+			rewritten := buf.FromBytes(datagram)
+			rewritten.UDP = b.UDP
+			b.Release()
+			mb[i] = rewritten
+		}
+	}
+	return mb, err
+}
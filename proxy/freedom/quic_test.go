@@ -0,0 +1,135 @@
+package freedom_test
+
+import (
+	"context"
+	gotls "crypto/tls"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/GFW-knocker/Xray-core/common"
+	"github.com/GFW-knocker/Xray-core/common/protocol/tls/cert"
+	. "github.com/GFW-knocker/Xray-core/proxy/freedom"
+	"github.com/quic-go/quic-go"
+)
+
+// quicRelay forwards datagrams between a single client and the server,
+// passing them through the fragmenter.
+func quicRelay(t *testing.T, fragmenter *QUICFragmenter, server net.Addr) (net.Addr, *atomic.Int32) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	common.Must(err)
+	upstream, err := net.DialUDP("udp", nil, server.(*net.UDPAddr))
+	common.Must(err)
+	t.Cleanup(func() {
+		conn.Close()
+		upstream.Close()
+	})
+
+	initials := new(atomic.Int32)
+	client := make(chan net.Addr, 1)
+	go func() {
+		b := make([]byte, 2048)
+		for first := true; ; first = false {
+			n, addr, err := conn.ReadFrom(b)
+			if err != nil {
+				return
+			}
+			if first {
+				client <- addr
+			}
+			datagrams := fragmenter.Outgoing(b[:n])
+			if datagrams == nil {
+				datagrams = [][]byte{b[:n]}
+			}
+			for _, datagram := range datagrams {
+				if datagram[0]&0xf0 == 0xc0 {
+					if len(datagram) < 1200 {
+						t.Error("initial datagram of ", len(datagram), " bytes is too short")
+					}
+					initials.Add(1)
+				}
+				upstream.Write(datagram)
+			}
+		}
+	}()
+	go func() {
+		b := make([]byte, 2048)
+		addr := <-client
+		for {
+			n, err := upstream.Read(b)
+			if err != nil {
+				return
+			}
+			datagram := fragmenter.Incoming(b[:n])
+			if datagram == nil {
+				datagram = b[:n]
+			}
+			conn.WriteTo(datagram, addr)
+		}
+	}()
+	return conn.LocalAddr(), initials
+}
+
+func TestQUICFragmenter(t *testing.T) {
+	certificate, key := cert.MustGenerate(nil, cert.CommonName("www.example.com")).ToPEM()
+	keyPair, err := gotls.X509KeyPair(certificate, key)
+	common.Must(err)
+	listener, err := quic.ListenAddr("127.0.0.1:0", &gotls.Config{
+		Certificates: []gotls.Certificate{keyPair},
+		NextProtos:   []string{"h3"},
+	}, nil)
+	common.Must(err)
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept(context.Background())
+		if err != nil {
+			return
+		}
+		stream, err := conn.AcceptStream(context.Background())
+		if err != nil {
+			return
+		}
+		io.Copy(stream, stream)
+		stream.Close()
+	}()
+
+	fragmenter := NewQUICFragmenter(&QuicFragment{Packets: 3, Reorder: true, Decoys: 2})
+	relay, initials := quicRelay(t, fragmenter, listener.Addr())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := quic.DialAddr(ctx, relay.String(), &gotls.Config{
+		InsecureSkipVerify: true,
+		ServerName:         "www.example.com",
+		NextProtos:         []string{"h3"},
+	}, nil)
+	if err != nil {
+		t.Fatal("handshake failed: ", err)
+	}
+	defer conn.CloseWithError(0, "")
+
+	stream, err := conn.OpenStreamSync(ctx)
+	common.Must(err)
+	common.Must2(stream.Write([]byte("hello")))
+	common.Must(stream.Close())
+	echo, err := io.ReadAll(stream)
+	common.Must(err)
+	if string(echo) != "hello" {
+		t.Error("unexpected echo ", string(echo))
+	}
+	if n := initials.Load(); n < 5 {
+		t.Error("expect the client Initial to be fragmented, but only ", n, " Initial datagrams were sent")
+	}
+}
+
+func TestQUICFragmenterIgnoresOtherPackets(t *testing.T) {
+	fragmenter := NewQUICFragmenter(&QuicFragment{Packets: 3})
+	for _, b := range [][]byte{{}, {0x40, 1, 2, 3}, make([]byte, 1200)} {
+		if fragmenter.Outgoing(b) != nil || fragmenter.Incoming(b) != nil {
+			t.Error("non initial packet is rewritten")
+		}
+	}
+}
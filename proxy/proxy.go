@@ -547,6 +547,10 @@ func UnwrapRawConn(conn net.Conn) (net.Conn, stats.Counter, stats.Counter) {
 			conn = pc.Raw()
 			// 8192 > 4096, there is no need to process pc's bufReader
 		}
+		if fc, ok := conn.(*internet.FragmentConn); ok {
+			// fragmentation only matters for the handshake, which is over by now
+			conn = fc.Conn
+		}
 		if uc, ok := conn.(*internet.UnixConnWrapper); ok {
 			conn = uc.UnixConn
 		}
@@ -2,7 +2,7 @@ package wireguard
 
 import (
 	"context"
-	"errors"
+	go_errors "errors"
 	"io"
 	"net"
 	"net/netip"
@@ -11,7 +11,9 @@ import (
 
 	"github.com/GFW-knocker/wireguard/conn"
 
+	"github.com/GFW-knocker/Xray-core/common/errors"
 	xnet "github.com/GFW-knocker/Xray-core/common/net"
+	"github.com/GFW-knocker/Xray-core/common/noise"
 	"github.com/GFW-knocker/Xray-core/features/dns"
 	"github.com/GFW-knocker/Xray-core/transport/internet"
 )
@@ -129,17 +131,11 @@ func (bind *netBind) Close() error {
 type netBindClient struct {
 	netBind
 
-	ctx              context.Context
-	dialer           internet.Dialer
-	reserved         []byte
-	Wnoise           string
-	Wheader          []byte
-	WnoisecountFrom  int
-	WnoisecountTo    int
-	WnoisedelayFrom  int
-	WnoisedelayTo    int
-	WpayloadsizeFrom int
-	WpayloadsizeTo   int
+	ctx         context.Context
+	dialer      internet.Dialer
+	reserved    []byte
+	noise       *noise.Config
+	legacyNoise *LegacyNoise
 }
 
 func (bind *netBindClient) connectTo(endpoint *netEndpoint) error {
@@ -147,9 +143,23 @@ func (bind *netBindClient) connectTo(endpoint *netEndpoint) error {
 	if err != nil {
 		return err
 	}
+	if bind.noise != nil {
+		endpoint.noise, err = noise.NewSender(bind.noise, endpoint.dst.Port, func(b []byte) error {
+			_, err := c.Write(b)
+			return err
+		})
+		if err != nil {
+			c.Close()
+			return err
+		}
+		endpoint.noise.KeepAlive()
+	}
 	endpoint.conn = c
 
 	go func(readQueue <-chan *netReadInfo, endpoint *netEndpoint) {
+		if endpoint.noise != nil {
+			defer endpoint.noise.Close()
+		}
 		for {
 			v, ok := <-readQueue
 			if !ok {
@@ -167,7 +177,7 @@ func (bind *netBindClient) connectTo(endpoint *netEndpoint) error {
 			v.endpoint = endpoint
 			v.err = err
 			v.waiter.Done()
-			if err != nil && errors.Is(err, io.EOF) {
+			if err != nil && go_errors.Is(err, io.EOF) {
 				endpoint.conn = nil
 				return
 			}
@@ -179,7 +189,12 @@ func (bind *netBindClient) connectTo(endpoint *netEndpoint) error {
 
 // --------- GFW knocker -----------------------
 func (bind *netBindClient) Get_extra_data() (string, []byte, int, int, int, int, int, int) {
-	return bind.Wnoise, bind.Wheader, bind.WnoisecountFrom, bind.WnoisecountTo, bind.WnoisedelayFrom, bind.WnoisedelayTo, bind.WpayloadsizeFrom, bind.WpayloadsizeTo
+	n := bind.legacyNoise
+	if n == nil {
+		// the noise of bind.noise, if any, is sent by Send instead
+		return "", nil, 0, 0, 0, 0, 0, 0
+	}
+	return n.Type, n.Header, int(n.CountMin), int(n.CountMax), int(n.DelayMin), int(n.DelayMax), int(n.PayloadSizeMin), int(n.PayloadSizeMax)
 }
 
 func (bind *netBindServer) Get_extra_data() (string, []byte, int, int, int, int, int, int) {
@@ -247,6 +262,12 @@ func (bind *netBindClient) Send(buff [][]byte, endpoint conn.Endpoint) error {
 		}
 	}
 
+	if nend.noise != nil && len(buff) > 0 && isHandshakeInitiation(buff[0]) {
+		if err := nend.noise.Send(); err != nil {
+			errors.LogInfoInner(bind.ctx, err, "failed to send noise to ", nend.dst)
+		}
+	}
+
 	for _, buff := range buff {
 		if len(buff) > 3 && len(bind.reserved) == 3 {
 			copy(buff[1:], bind.reserved)
@@ -284,8 +305,15 @@ func (bind *netBindServer) Send(buff [][]byte, endpoint conn.Endpoint) error {
 }
 
 type netEndpoint struct {
-	dst  xnet.Destination
-	conn net.Conn
+	dst   xnet.Destination
+	conn  net.Conn
+	noise *noise.Sender
+}
+
+// isHandshakeInitiation reports whether b is a WireGuard handshake
+// initiation message.
+func isHandshakeInitiation(b []byte) bool {
+	return len(b) == 148 && b[0] == 1
 }
 
 func (netEndpoint) ClearSrc() {}
@@ -22,10 +22,8 @@ package wireguard
 
 import (
 	"context"
-	"encoding/hex"
 	"fmt"
 	"net/netip"
-	"strconv"
 	"strings"
 	"sync"
 
@@ -115,110 +113,6 @@ func (h *Handler) processWireGuard(ctx context.Context, dialer internet.Dialer)
 		h.bind = nil
 	}
 
-	// ------ GFW-knocker --------------------
-	var tmp []string
-	var tmperr1 error
-	var tmperr2 error
-	var v1 uint64
-	var v2 uint64
-
-	// noise header []byte
-	var Wheader []byte = nil
-	Wnoise := h.conf.Wnoise
-	if (Wnoise != "") && (Wnoise != "none") && (Wnoise != "quic") && (Wnoise != "random") {
-		if len(Wnoise)%2 != 0 {
-			Wnoise = Wnoise + "0"
-		}
-
-		if len(Wnoise) > 100 {
-			Wnoise = Wnoise[:100]
-		}
-
-		decodedBytes, err := hex.DecodeString(Wnoise)
-		if err == nil {
-			Wheader = decodedBytes
-		}
-	}
-
-	// noise packet count
-	var WnoisecountFrom int = 1
-	var WnoisecountTo int = 2
-	tmp = strings.Split(h.conf.Wnoisecount, "-")
-	if len(tmp) == 2 {
-		v1, tmperr1 = strconv.ParseUint(tmp[0], 10, 64)
-		v2, tmperr2 = strconv.ParseUint(tmp[1], 10, 64)
-		if (tmperr1 == nil) && (tmperr2 == nil) {
-			WnoisecountFrom = int(v1)
-			WnoisecountTo = int(v2)
-		}
-	} else if len(tmp) == 1 {
-		v1, tmperr1 = strconv.ParseUint(tmp[0], 10, 64)
-		if tmperr1 == nil {
-			WnoisecountFrom = int(v1)
-			WnoisecountTo = int(v1)
-		}
-	}
-
-	// delay between packets
-	var WnoisedelayFrom int = 5
-	var WnoisedelayTo int = 10
-	tmp = strings.Split(h.conf.Wnoisedelay, "-")
-	if len(tmp) == 2 {
-		v1, tmperr1 = strconv.ParseUint(tmp[0], 10, 64)
-		v2, tmperr2 = strconv.ParseUint(tmp[1], 10, 64)
-		if (tmperr1 == nil) && (tmperr2 == nil) {
-			WnoisedelayFrom = int(v1)
-			WnoisedelayTo = int(v2)
-		}
-	} else if len(tmp) == 1 {
-		v1, tmperr1 = strconv.ParseUint(tmp[0], 10, 64)
-		if tmperr1 == nil {
-			WnoisedelayFrom = int(v1)
-			WnoisedelayTo = int(v1)
-		}
-	}
-
-	// noise payload size in byte
-	var WpayloadsizeFrom int = 5
-	var WpayloadsizeTo int = 10
-	tmp = strings.Split(h.conf.Wpayloadsize, "-")
-	if len(tmp) == 2 {
-		v1, tmperr1 = strconv.ParseUint(tmp[0], 10, 64)
-		v2, tmperr2 = strconv.ParseUint(tmp[1], 10, 64)
-		if (tmperr1 == nil) && (tmperr2 == nil) {
-			WpayloadsizeFrom = int(v1)
-			WpayloadsizeTo = int(v2)
-		}
-	} else if len(tmp) == 1 {
-		v1, tmperr1 = strconv.ParseUint(tmp[0], 10, 64)
-		if tmperr1 == nil {
-			WpayloadsizeFrom = int(v1)
-			WpayloadsizeTo = int(v1)
-		}
-	}
-
-	// limit noise params to reasonable value
-	if WnoisecountFrom > 50 {
-		WnoisecountFrom = 50
-	}
-	if WnoisecountTo > 50 {
-		WnoisecountTo = 50
-	}
-	if WnoisedelayFrom > 100 {
-		WnoisedelayFrom = 100
-	}
-	if WnoisedelayTo > 100 {
-		WnoisedelayTo = 100
-	}
-	if WpayloadsizeFrom > 100 {
-		WpayloadsizeFrom = 100
-	}
-	if WpayloadsizeTo > 100 {
-		WpayloadsizeTo = 100
-	}
-
-	// ------ GFW-knocker --------------------
-
 	// bind := conn.NewStdNetBind() // TODO: conn.Bind wrapper for dialer
 	h.bind = &netBindClient{
 		netBind: netBind{
@@ -229,17 +123,11 @@ func (h *Handler) processWireGuard(ctx context.Context, dialer internet.Dialer)
 			},
 			workers: int(h.conf.NumWorkers),
 		},
-		ctx:              ctx,
-		dialer:           dialer,
-		reserved:         h.conf.Reserved,
-		Wnoise:           h.conf.Wnoise,
-		Wheader:          Wheader,
-		WnoisecountFrom:  WnoisecountFrom,
-		WnoisecountTo:    WnoisecountTo,
-		WnoisedelayFrom:  WnoisedelayFrom,
-		WnoisedelayTo:    WnoisedelayTo,
-		WpayloadsizeFrom: WpayloadsizeFrom,
-		WpayloadsizeTo:   WpayloadsizeTo,
+		ctx:         ctx,
+		dialer:      dialer,
+		reserved:    h.conf.Reserved,
+		noise:       h.conf.Noise,
+		legacyNoise: h.conf.LegacyNoise,
 	}
 	defer func() {
 		if err != nil {
@@ -2,6 +2,7 @@ package udp
 
 import (
 	"fmt"
+	"sync/atomic"
 
 	"github.com/GFW-knocker/Xray-core/common/net"
 )
@@ -9,7 +10,7 @@ import (
 type Server struct {
 	Port         net.Port
 	MsgProcessor func(msg []byte) []byte
-	accepting    bool
+	accepting    atomic.Bool
 	conn         *net.UDPConn
 }
 
@@ -26,14 +27,14 @@ func (server *Server) Start() (net.Destination, error) {
 	fmt.Println("UDP server started on port ", server.Port)
 
 	server.conn = conn
+	server.accepting.Store(true)
 	go server.handleConnection(conn)
 	localAddr := conn.LocalAddr().(*net.UDPAddr)
 	return net.UDPDestination(net.IPAddress(localAddr.IP), net.Port(localAddr.Port)), nil
 }
 
 func (server *Server) handleConnection(conn *net.UDPConn) {
-	server.accepting = true
-	for server.accepting {
+	for server.accepting.Load() {
 		buffer := make([]byte, 2*1024)
 		nBytes, addr, err := conn.ReadFromUDP(buffer)
 		if err != nil {
@@ -49,6 +50,6 @@ func (server *Server) handleConnection(conn *net.UDPConn) {
 }
 
 func (server *Server) Close() error {
-	server.accepting = false
+	server.accepting.Store(false)
 	return server.conn.Close()
 }
@@ -3,7 +3,6 @@ package internet
 import (
 	"context"
 	"fmt"
-	gonet "net"
 	"strings"
 
 	"github.com/GFW-knocker/Xray-core/common"
@@ -13,10 +12,12 @@ import (
 	"github.com/GFW-knocker/Xray-core/common/net/cnc"
 	"github.com/GFW-knocker/Xray-core/common/session"
 	"github.com/GFW-knocker/Xray-core/features/dns"
+	"github.com/GFW-knocker/Xray-core/features/dns/localdns"
 	"github.com/GFW-knocker/Xray-core/features/outbound"
 	"github.com/GFW-knocker/Xray-core/transport"
 	"github.com/GFW-knocker/Xray-core/transport/internet/stat"
 	"github.com/GFW-knocker/Xray-core/transport/pipe"
+	mdns "github.com/miekg/dns"
 )
 
 // Dialer is the interface for dialing outbound connections.
@@ -186,33 +187,38 @@ func checkAddressPortStrategy(ctx context.Context, dest net.Destination, sockopt
 
 	if OverrideBy == "srv" {
 		errors.LogDebug(ctx, "query SRV record for "+dest.Address.String())
-		parts := strings.SplitN(dest.Address.String(), ".", 3)
-		if len(parts) != 3 {
-			return nil, errors.New("invalid address format", dest.Address.String())
-		}
-		_, srvRecords, err := gonet.DefaultResolver.LookupSRV(context.Background(), parts[0][1:], parts[1][1:], parts[2])
+		records, _, err := LookupRecords(dest.Address.String(), mdns.TypeSRV)
 		if err != nil {
 			return nil, errors.New("failed to lookup SRV record").Base(err)
 		}
-		errors.LogDebug(ctx, "SRV record: "+fmt.Sprintf("addr=%s, port=%d, priority=%d, weight=%d", srvRecords[0].Target, srvRecords[0].Port, srvRecords[0].Priority, srvRecords[0].Weight))
+		srv := pickSRV(records)
+		if srv == nil {
+			return nil, errors.New("failed to lookup SRV record").Base(dns.ErrEmptyResponse)
+		}
+		errors.LogDebug(ctx, "SRV record: "+fmt.Sprintf("addr=%s, port=%d, priority=%d, weight=%d", srv.Target, srv.Port, srv.Priority, srv.Weight))
 		if OverridePort {
-			newDest.Port = net.Port(srvRecords[0].Port)
+			newDest.Port = net.Port(srv.Port)
 		}
 		if OverrideAddress {
-			newDest.Address = net.ParseAddress(srvRecords[0].Target)
+			newDest.Address = net.ParseAddress(srv.Target)
 		}
 		return &newDest, nil
 	}
 	if OverrideBy == "txt" {
 		errors.LogDebug(ctx, "query TXT record for "+dest.Address.String())
-		txtRecords, err := gonet.DefaultResolver.LookupTXT(ctx, dest.Address.String())
+		records, _, err := LookupRecords(dest.Address.String(), mdns.TypeTXT)
 		if err != nil {
-			errors.LogError(ctx, "failed to lookup SRV record: "+err.Error())
-			return nil, errors.New("failed to lookup SRV record").Base(err)
+			errors.LogError(ctx, "failed to lookup TXT record: "+err.Error())
+			return nil, errors.New("failed to lookup TXT record").Base(err)
 		}
-		for _, txtRecord := range txtRecords {
+		for _, record := range records {
+			txt, ok := record.(*mdns.TXT)
+			if !ok {
+				continue
+			}
+			txtRecord := strings.Join(txt.Txt, "")
 			errors.LogDebug(ctx, "TXT record: "+txtRecord)
-			addr_s, port_s, _ := net.SplitHostPort(string(txtRecord))
+			addr_s, port_s, _ := net.SplitHostPort(txtRecord)
 			addr := net.ParseAddress(addr_s)
 			port, err := net.PortFromString(port_s)
 			if err != nil {
@@ -231,6 +237,50 @@ func checkAddressPortStrategy(ctx context.Context, dest net.Destination, sockopt
 	return nil, nil
 }
 
+// pickSRV picks a record of the least priority by weight (RFC 2782).
+func pickSRV(records []mdns.RR) *mdns.SRV {
+	var candidates []*mdns.SRV
+	totalWeight := 0
+	for _, record := range records {
+		srv, ok := record.(*mdns.SRV)
+		if !ok {
+			continue
+		}
+		if len(candidates) > 0 && srv.Priority > candidates[0].Priority {
+			continue
+		}
+		if len(candidates) > 0 && srv.Priority < candidates[0].Priority {
+			candidates = candidates[:0]
+			totalWeight = 0
+		}
+		candidates = append(candidates, srv)
+		totalWeight += int(srv.Weight)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	if totalWeight == 0 {
+		return candidates[dice.Roll(len(candidates))]
+	}
+	n := dice.Roll(totalWeight)
+	for _, srv := range candidates {
+		n -= int(srv.Weight)
+		if n < 0 {
+			return srv
+		}
+	}
+	return candidates[len(candidates)-1]
+}
+
+// LookupRecords looks up the records of qtype for domain with the DNS client,
+// or the system resolver if the client does not look up records.
+func LookupRecords(domain string, qtype uint16) ([]mdns.RR, uint32, error) {
+	if client, ok := dnsClient.(dns.RecordClient); ok {
+		return client.LookupRecords(domain, qtype)
+	}
+	return localdns.New().LookupRecords(domain, qtype)
+}
+
 // DialSystem calls system dialer to create a network connection.
 func DialSystem(ctx context.Context, dest net.Destination, sockopt *SocketConfig) (net.Conn, error) {
 	var src net.Address
@@ -259,7 +309,11 @@ func DialSystem(ctx context.Context, dest net.Destination, sockopt *SocketConfig
 			dest.Address = net.IPAddress(ips[dice.Roll(len(ips))])
 			errors.LogInfo(ctx, "replace destination with "+dest.String())
 		} else {
-			return TcpRaceDial(ctx, src, ips, dest.Port, sockopt)
+			conn, err := TcpRaceDial(ctx, src, ips, dest.Port, sockopt)
+			if err != nil {
+				return nil, err
+			}
+			return fragmentConn(conn, dest, sockopt)
 		}
 	}
 
@@ -274,7 +328,11 @@ func DialSystem(ctx context.Context, dest net.Destination, sockopt *SocketConfig
 		return redirect(ctx, dest, sockopt.DialerProxy, h), nil
 	}
 
-	return effectiveSystemDialer.Dial(ctx, src, dest, sockopt)
+	conn, err := effectiveSystemDialer.Dial(ctx, src, dest, sockopt)
+	if err != nil {
+		return nil, err
+	}
+	return fragmentConn(conn, dest, sockopt)
 }
 
 func InitSystemDialer(dc dns.Client, om outbound.Manager) {
@@ -2,13 +2,16 @@ package internet_test
 
 import (
 	"context"
+	"strconv"
 	"testing"
 
 	"github.com/GFW-knocker/Xray-core/common"
 	"github.com/GFW-knocker/Xray-core/common/net"
+	"github.com/GFW-knocker/Xray-core/features/dns"
 	"github.com/GFW-knocker/Xray-core/testing/servers/tcp"
 	. "github.com/GFW-knocker/Xray-core/transport/internet"
 	"github.com/google/go-cmp/cmp"
+	mdns "github.com/miekg/dns"
 )
 
 func TestDialWithLocalAddr(t *testing.T) {
@@ -24,3 +27,98 @@ func TestDialWithLocalAddr(t *testing.T) {
 	}
 	conn.Close()
 }
+
+// recordClient answers record lookups from records.
+type recordClient struct {
+	records map[uint16][]mdns.RR
+}
+
+func (*recordClient) Type() interface{} {
+	return dns.ClientType()
+}
+
+func (*recordClient) Start() error {
+	return nil
+}
+
+func (*recordClient) Close() error {
+	return nil
+}
+
+func (*recordClient) LookupIP(domain string, option dns.IPOption) ([]net.IP, uint32, error) {
+	return nil, 0, dns.ErrEmptyResponse
+}
+
+func (c *recordClient) LookupRecords(domain string, qtype uint16) ([]mdns.RR, uint32, error) {
+	return c.records[qtype], 600, nil
+}
+
+// listenPorts listens on n local TCP ports which accept and close connections.
+func listenPorts(t *testing.T, n int) []uint16 {
+	ports := make([]uint16, n)
+	for i := range ports {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		common.Must(err)
+		t.Cleanup(func() { listener.Close() })
+		go func() {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				conn.Close()
+			}
+		}()
+		ports[i] = uint16(listener.Addr().(*net.TCPAddr).Port)
+	}
+	return ports
+}
+
+func TestDialSRV(t *testing.T) {
+	ports := listenPorts(t, 3)
+	srv := func(priority, weight, port uint16) mdns.RR {
+		return &mdns.SRV{Priority: priority, Weight: weight, Port: port, Target: "127.0.0.1"}
+	}
+	InitSystemDialer(&recordClient{records: map[uint16][]mdns.RR{
+		mdns.TypeSRV: {srv(10, 100, ports[0]), srv(5, 3, ports[1]), srv(5, 1, ports[2])},
+	}}, nil)
+	defer InitSystemDialer(nil, nil)
+
+	counts := make(map[uint16]int)
+	for range 400 {
+		conn, err := DialSystem(context.Background(), net.TCPDestination(net.DomainAddress("example.com"), 80), &SocketConfig{
+			AddressPortStrategy: AddressPortStrategy_SrvPortAndAddress,
+		})
+		common.Must(err)
+		counts[uint16(conn.RemoteAddr().(*net.TCPAddr).Port)]++
+		conn.Close()
+	}
+	if counts[ports[0]] != 0 {
+		t.Error("record of higher priority is picked ", counts[ports[0]], " times")
+	}
+	// weights 3:1 of 400 picks
+	if counts[ports[1]] < 240 || counts[ports[1]] > 360 {
+		t.Error("unexpected picks by weight: ", counts[ports[1]], " and ", counts[ports[2]])
+	}
+}
+
+func TestDialTXT(t *testing.T) {
+	ports := listenPorts(t, 1)
+	InitSystemDialer(&recordClient{records: map[uint16][]mdns.RR{
+		mdns.TypeTXT: {
+			&mdns.TXT{Txt: []string{"v=spf1 -all"}},
+			// long TXT records are split into strings of up to 255 bytes
+			&mdns.TXT{Txt: []string{"127.0.0.1:", strconv.Itoa(int(ports[0]))}},
+		},
+	}}, nil)
+	defer InitSystemDialer(nil, nil)
+
+	conn, err := DialSystem(context.Background(), net.TCPDestination(net.DomainAddress("example.com"), 80), &SocketConfig{
+		AddressPortStrategy: AddressPortStrategy_TxtPortAndAddress,
+	})
+	common.Must(err)
+	defer conn.Close()
+	if r := cmp.Diff(conn.RemoteAddr().String(), "127.0.0.1:"+strconv.Itoa(int(ports[0]))); r != "" {
+		t.Error(r)
+	}
+}
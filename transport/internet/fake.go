@@ -0,0 +1,104 @@
+package internet
+
+import (
+	"context"
+	gotls "crypto/tls"
+	"encoding/binary"
+	"io"
+	gonet "net"
+	"sync"
+	"syscall"
+
+	"github.com/GFW-knocker/Xray-core/common/errors"
+	"github.com/GFW-knocker/Xray-core/common/net"
+	"github.com/GFW-knocker/Xray-core/features/stats"
+	"github.com/GFW-knocker/Xray-core/transport/internet/stat"
+)
+
+// FakeWriter sends the first write on a connection with a low TTL when it
+// starts a TLS handshake record, so that its packets expire before reaching
+// the server and are only delivered when the kernel retransmits them. It is
+// meant to sit below a FragmentWriter, where the first write is the first
+// fragment of the client hello.
+//
+// Connections without a socket of their own, such as those through another
+// outbound, are written to as is.
+type FakeWriter struct {
+	conn    net.Conn
+	fake    *FragmentFake
+	written bool
+}
+
+// fakeUnsupported warns once of connections which can't be faked.
+var fakeUnsupported sync.Once
+
+func NewFakeWriter(conn net.Conn, fake *FragmentFake) *FakeWriter {
+	return &FakeWriter{
+		conn: conn,
+		fake: fake,
+	}
+}
+
+func (w *FakeWriter) Write(b []byte) (int, error) {
+	if w.written || len(b) == 0 || b[0] != 22 {
+		w.written = true
+		return w.conn.Write(b)
+	}
+	w.written = true
+
+	// the TTL is set on the socket itself, below the stats wrapper
+	conn := w.conn
+	var counter stats.Counter
+	if statConn, ok := conn.(*stat.CounterConnection); ok {
+		conn = statConn.Connection
+		counter = statConn.WriteCounter
+	}
+	if _, ok := conn.(syscall.Conn); !ok {
+		fakeUnsupported.Do(func() {
+			errors.LogWarning(context.Background(), "fragment fake is skipped on connections without a socket")
+		})
+		return w.conn.Write(b)
+	}
+	var n int
+	var err error
+	if w.fake.ServerName != "" {
+		hello, herr := fakeClientHello(w.fake.ServerName)
+		if herr != nil {
+			return 0, errors.New("failed to generate fake client hello").Base(herr)
+		}
+		n, err = WriteFake(conn, hello, b, int(w.fake.Ttl))
+	} else {
+		n, err = WriteWithTTL(conn, b, int(w.fake.Ttl))
+	}
+	if counter != nil {
+		counter.Add(int64(n))
+	}
+	if err != nil {
+		return n, errors.New("failed to write with TTL ", w.fake.Ttl).Base(err)
+	}
+	return n, nil
+}
+
+// fakeClientHello returns the first TLS record a Go TLS client sends to
+// serverName.
+func fakeClientHello(serverName string) ([]byte, error) {
+	client, server := gonet.Pipe()
+	defer server.Close()
+	go func() {
+		gotls.Client(client, &gotls.Config{
+			ServerName: serverName,
+			NextProtos: []string{"h2", "http/1.1"},
+		}).Handshake()
+		client.Close()
+	}()
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(server, header); err != nil {
+		return nil, err
+	}
+	hello := make([]byte, 5+int(binary.BigEndian.Uint16(header[3:])))
+	copy(hello, header)
+	if _, err := io.ReadFull(server, hello[5:]); err != nil {
+		return nil, err
+	}
+	return hello, nil
+}
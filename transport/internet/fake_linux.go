@@ -0,0 +1,168 @@
+//go:build linux
+// +build linux
+
+package internet
+
+import (
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/GFW-knocker/Xray-core/common/errors"
+	"golang.org/x/sys/unix"
+)
+
+// maxFakeSize is the most that fits in a pipe with the default capacity.
+const maxFakeSize = 16 * 4096
+
+func rawConn(conn net.Conn) (syscall.RawConn, error) {
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return nil, errors.New("connection doesn't expose its file descriptor")
+	}
+	return sc.SyscallConn()
+}
+
+// withTTL runs f with the TTL of conn lowered to ttl, restoring it afterwards.
+func withTTL(rc syscall.RawConn, ttl int, f func() error) error {
+	var restore func(fd uintptr) error
+	var err error
+	if cerr := rc.Control(func(fd uintptr) {
+		restore, err = setTTL(fd, ttl)
+	}); cerr != nil {
+		return cerr
+	}
+	if err != nil {
+		return errors.New("failed to set TTL").Base(err)
+	}
+	ferr := f()
+	if cerr := rc.Control(func(fd uintptr) {
+		err = restore(fd)
+	}); cerr != nil {
+		return cerr
+	}
+	if err != nil {
+		return errors.New("failed to restore TTL").Base(err)
+	}
+	return ferr
+}
+
+// WriteWithTTL writes b to conn in packets with the given TTL. When they
+// expire on the way, the kernel retransmits b later with the restored TTL, so
+// the peer receives it after what is written next.
+func WriteWithTTL(conn net.Conn, b []byte, ttl int) (int, error) {
+	rc, err := rawConn(conn)
+	if err != nil {
+		return 0, err
+	}
+	var n int
+	err = withTTL(rc, ttl, func() error {
+		var err error
+		n, err = conn.Write(b)
+		if err != nil {
+			return err
+		}
+		return waitSent(rc)
+	})
+	return n, err
+}
+
+// WriteFake sends fake in packets with the given TTL, at the position of b in
+// the stream, then replaces the sent data with b. Packets that expire on the
+// way are retransmitted by the kernel with b as their content, so only
+// middleboxes before the TTL runs out see fake. fake is truncated or padded
+// with zeros to the length of b.
+func WriteFake(conn net.Conn, fake, b []byte, ttl int) (int, error) {
+	if len(b) == 0 {
+		return 0, nil
+	}
+	if len(b) > maxFakeSize {
+		return 0, errors.New("data of ", len(b), " bytes is too large to be faked")
+	}
+	rc, err := rawConn(conn)
+	if err != nil {
+		return 0, err
+	}
+	// The socket must reference the pages of region, instead of copying them,
+	// for the retransmitted packets to pick up the new content.
+	region, err := unix.Mmap(-1, 0, len(b), unix.PROT_READ|unix.PROT_WRITE, unix.MAP_SHARED|unix.MAP_ANONYMOUS)
+	if err != nil {
+		return 0, errors.New("failed to map memory").Base(err)
+	}
+	defer unix.Munmap(region)
+	copy(region, fake)
+
+	var pipe [2]int
+	if err := unix.Pipe2(pipe[:], unix.O_CLOEXEC); err != nil {
+		return 0, errors.New("failed to create pipe").Base(err)
+	}
+	defer unix.Close(pipe[0])
+	defer unix.Close(pipe[1])
+
+	err = withTTL(rc, ttl, func() error {
+		for spliced := 0; spliced < len(b); {
+			iov := unix.Iovec{Base: &region[spliced]}
+			iov.SetLen(len(b) - spliced)
+			n, err := unix.Vmsplice(pipe[1], []unix.Iovec{iov}, unix.SPLICE_F_GIFT)
+			if err != nil {
+				return errors.New("failed to splice into pipe").Base(err)
+			}
+			for n > 0 {
+				var written int
+				var serr error
+				if err := rc.Write(func(fd uintptr) bool {
+					var w int64
+					w, serr = splice(pipe[0], int(fd), n)
+					written = int(w)
+					return serr != unix.EAGAIN
+				}); err != nil {
+					return err
+				}
+				if serr != nil {
+					return errors.New("failed to splice into socket").Base(serr)
+				}
+				n -= written
+				spliced += written
+			}
+		}
+		if err := waitSent(rc); err != nil {
+			return err
+		}
+		copy(region, b)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func splice(rfd, wfd, n int) (int64, error) {
+	w, err := unix.Splice(rfd, nil, wfd, nil, n, unix.SPLICE_F_NONBLOCK)
+	return int64(w), err
+}
+
+// waitSent waits until all data written to the socket has left it at least
+// once, so that changing the TTL doesn't affect it anymore.
+func waitSent(rc syscall.RawConn) error {
+	deadline := time.Now().Add(time.Second)
+	for {
+		var info *unix.TCPInfo
+		var err error
+		if cerr := rc.Control(func(fd uintptr) {
+			info, err = unix.GetsockoptTCPInfo(int(fd), unix.IPPROTO_TCP, unix.TCP_INFO)
+		}); cerr != nil {
+			return cerr
+		}
+		if err != nil {
+			return errors.New("failed to get TCP info").Base(err)
+		}
+		if info.Notsent_bytes == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.New("timeout waiting for data to be sent")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
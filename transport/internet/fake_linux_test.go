@@ -0,0 +1,178 @@
+package internet_test
+
+import (
+	"bytes"
+	"io"
+	gonet "net"
+	"syscall"
+	"testing"
+
+	"github.com/GFW-knocker/Xray-core/common"
+	"github.com/GFW-knocker/Xray-core/common/net"
+	"github.com/GFW-knocker/Xray-core/common/protocol/tls"
+	"github.com/GFW-knocker/Xray-core/common/serial"
+	"github.com/GFW-knocker/Xray-core/testing/servers/tcp"
+	. "github.com/GFW-knocker/Xray-core/transport/internet"
+)
+
+func getTTL(t *testing.T, conn net.Conn) int {
+	rawConn, err := conn.(syscall.Conn).SyscallConn()
+	common.Must(err)
+	var ttl int
+	common.Must(rawConn.Control(func(fd uintptr) {
+		ttl, err = syscall.GetsockoptInt(int(fd), syscall.IPPROTO_IP, syscall.IP_TTL)
+	}))
+	common.Must(err)
+	return ttl
+}
+
+func TestWriteFake(t *testing.T) {
+	tcpServer := tcp.Server{
+		MsgProcessor: func(b []byte) []byte {
+			return b
+		},
+	}
+	dest, err := tcpServer.Start()
+	common.Must(err)
+	defer tcpServer.Close()
+
+	conn, err := net.Dial("tcp", dest.NetAddr())
+	common.Must(err)
+	defer conn.Close()
+	ttl := getTTL(t, conn)
+
+	// packets don't expire on loopback, the peer reads either data depending
+	// on whether the shared pages were overwritten yet
+	common.Must2(WriteFake(conn, []byte("fake hello!"), []byte("hello"), 1))
+	if getTTL(t, conn) != ttl {
+		t.Error("TTL is not restored")
+	}
+	common.Must2(WriteWithTTL(conn, []byte(" real"), 1))
+	if getTTL(t, conn) != ttl {
+		t.Error("TTL is not restored")
+	}
+	common.Must2(conn.Write([]byte(" world")))
+
+	b := make([]byte, 16)
+	common.Must2(io.ReadFull(conn, b))
+	if s := string(b); s != "hello real world" && s != "fake  real world" {
+		t.Error("unexpected data ", string(b))
+	}
+}
+
+func TestWriteWithTTLDualStack(t *testing.T) {
+	listener, err := net.Listen("tcp", "[::]:0")
+	if err != nil {
+		t.Skip("no IPv6: ", err)
+	}
+	defer listener.Close()
+
+	port := net.Port(listener.Addr().(*net.TCPAddr).Port)
+	client, err := net.Dial("tcp", net.TCPDestination(net.LocalHostIP, port).NetAddr())
+	common.Must(err)
+	defer client.Close()
+	conn, err := listener.Accept()
+	common.Must(err)
+	defer conn.Close()
+
+	// the accepted socket is an IPv6 one with an IPv4 mapped peer, and sends
+	// with the IPv4 TTL
+	getHops := func() int {
+		rawConn, err := conn.(syscall.Conn).SyscallConn()
+		common.Must(err)
+		var hops int
+		common.Must(rawConn.Control(func(fd uintptr) {
+			hops, err = syscall.GetsockoptInt(int(fd), syscall.IPPROTO_IPV6, syscall.IPV6_UNICAST_HOPS)
+		}))
+		common.Must(err)
+		return hops
+	}
+	ttl, hops := getTTL(t, conn), getHops()
+	common.Must2(WriteWithTTL(conn, []byte("hello"), 1))
+	if getTTL(t, conn) != ttl || getHops() != hops {
+		t.Error("TTL is not restored")
+	}
+	common.Must2(io.ReadFull(client, make([]byte, 5)))
+}
+
+func TestFakeWriter(t *testing.T) {
+	const domain = "www.example.com"
+	hello := clientHello(t, domain)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	common.Must(err)
+	defer listener.Close()
+
+	for _, fake := range []*FragmentFake{{Ttl: 1}, {Ttl: 1, ServerName: "www.google.com"}} {
+		received := make(chan []byte, 1)
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			b, _ := io.ReadAll(conn)
+			received <- b
+		}()
+
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		common.Must(err)
+		strategy, err := NewFragmentStrategy(&Fragment{
+			Strategy:         FragmentStrategySNI,
+			StrategySettings: serial.ToTypedMessage(&SNIFragmentConfig{}),
+		})
+		common.Must(err)
+		writer := NewFragmentWriter(strategy, nil, NewFakeWriter(conn, fake))
+		common.Must2(writer.Write(hello))
+		common.Must2(writer.Write([]byte("data")))
+		conn.Close()
+
+		// packets don't expire on loopback, so the server may receive the
+		// fake data instead of the first fragment, but never anything else
+		b := <-received
+		if len(b) != len(hello)+4 {
+			t.Fatal("expect ", len(hello)+4, " bytes but got ", len(b))
+		}
+		start, end, err := tls.FindServerName(hello)
+		common.Must(err)
+		cut := start + (end-start)/2
+		if fake.ServerName == "" && !bytes.Equal(b[:cut], hello[:cut]) {
+			t.Error("first fragment is corrupted")
+		}
+		if !bytes.Equal(b[cut:], append(hello[cut:], "data"...)) {
+			t.Error("data after the first fragment is corrupted")
+		}
+	}
+}
+
+func TestFakeWriterSkipsOtherData(t *testing.T) {
+	client, server := gonet.Pipe()
+	defer server.Close()
+	go func() {
+		// net.Pipe has no socket to set the TTL of, so this would fail if faked
+		NewFakeWriter(client, &FragmentFake{Ttl: 1}).Write([]byte("GET / HTTP/1.1\r\n"))
+		client.Close()
+	}()
+	b, err := io.ReadAll(server)
+	common.Must(err)
+	if string(b) != "GET / HTTP/1.1\r\n" {
+		t.Error("unexpected data ", string(b))
+	}
+}
+
+func TestFakeWriterWithoutSocket(t *testing.T) {
+	const domain = "www.example.com"
+	hello := clientHello(t, domain)
+
+	client, server := gonet.Pipe()
+	defer server.Close()
+	go func() {
+		NewFakeWriter(client, &FragmentFake{Ttl: 1, ServerName: "www.google.com"}).Write(hello)
+		client.Close()
+	}()
+	b, err := io.ReadAll(server)
+	common.Must(err)
+	if !bytes.Equal(b, hello) {
+		t.Error("client hello is not written as is")
+	}
+}
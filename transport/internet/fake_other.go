@@ -0,0 +1,20 @@
+//go:build !linux
+// +build !linux
+
+package internet
+
+import (
+	"net"
+
+	"github.com/GFW-knocker/Xray-core/common/errors"
+)
+
+// WriteWithTTL is only supported on Linux.
+func WriteWithTTL(conn net.Conn, b []byte, ttl int) (int, error) {
+	return 0, errors.New("writing with TTL is not supported on this platform")
+}
+
+// WriteFake is only supported on Linux.
+func WriteFake(conn net.Conn, fake, b []byte, ttl int) (int, error) {
+	return 0, errors.New("writing fake data is not supported on this platform")
+}
@@ -0,0 +1,291 @@
+package internet
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"math/big"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+	"weak"
+
+	"github.com/GFW-knocker/Xray-core/common/errors"
+	"github.com/GFW-knocker/Xray-core/common/net"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	FragmentStrategyFakeHost   = "fakehost"
+	FragmentStrategyTLSRecord  = "tlsrecord"
+	FragmentStrategyTCPSegment = "tcpsegment"
+	FragmentStrategySNI        = "sni"
+	FragmentStrategyMixed      = "mixed"
+)
+
+// FragmentStrategy decides how the bytes written through a FragmentWriter
+// are rewritten and split before they reach the connection.
+type FragmentStrategy interface {
+	// Write writes b to w. count is the number of writes so far, starting from 1.
+	Write(w io.Writer, b []byte, count uint64) (int, error)
+}
+
+// FragmentStrategyCreator creates a FragmentStrategy. settings is the
+// strategy specific config and may be nil.
+type FragmentStrategyCreator func(fragment *Fragment, settings proto.Message) (FragmentStrategy, error)
+
+var fragmentStrategyCache = make(map[string]FragmentStrategyCreator)
+
+// RegisterFragmentStrategy registers a FragmentStrategy with given name.
+func RegisterFragmentStrategy(name string, creator FragmentStrategyCreator) error {
+	name = strings.ToLower(name)
+	if _, found := fragmentStrategyCache[name]; found {
+		return errors.New(name, " fragment strategy already registered").AtError()
+	}
+	fragmentStrategyCache[name] = creator
+	return nil
+}
+
+// NewFragmentStrategy creates the FragmentStrategy selected by the given config.
+func NewFragmentStrategy(fragment *Fragment) (FragmentStrategy, error) {
+	name := strings.ToLower(fragment.Strategy)
+	if name == "" {
+		switch {
+		case fragment.FakeHost:
+			name = FragmentStrategyFakeHost
+		case fragment.PacketsFrom == 0 && fragment.PacketsTo == 1:
+			name = FragmentStrategyTLSRecord
+		default:
+			name = FragmentStrategyTCPSegment
+		}
+	}
+	creator, found := fragmentStrategyCache[name]
+	if !found {
+		return nil, errors.New("unknown fragment strategy: ", name)
+	}
+	var settings proto.Message
+	if fragment.StrategySettings != nil {
+		var err error
+		settings, err = fragment.StrategySettings.GetInstance()
+		if err != nil {
+			return nil, errors.New("failed to load settings of fragment strategy ", name).Base(err)
+		}
+	}
+	return creator(fragment, settings)
+}
+
+// FragmentWriter rewrites and splits what is written through it with a
+// FragmentStrategy.
+type FragmentWriter struct {
+	strategy    FragmentStrategy
+	clientHello *ClientHello
+	writer      io.Writer
+	count       uint64
+}
+
+// NewFragmentWriter creates a FragmentWriter. Both strategy and clientHello
+// may be nil.
+func NewFragmentWriter(strategy FragmentStrategy, clientHello *ClientHello, writer io.Writer) *FragmentWriter {
+	return &FragmentWriter{
+		strategy:    strategy,
+		clientHello: clientHello,
+		writer:      writer,
+	}
+}
+
+func (f *FragmentWriter) Write(b []byte) (int, error) {
+	f.count++
+	n := len(b)
+	if f.count == 1 && f.clientHello != nil {
+		b = reencodeClientHello(f.clientHello, b)
+	}
+	var err error
+	if f.strategy != nil {
+		_, err = f.strategy.Write(f.writer, b, f.count)
+	} else {
+		_, err = f.writer.Write(b)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// FragmentConn is a dialed connection whose writes are fragmented. Reads, and
+// the underlying connection used for splicing, are left untouched.
+type FragmentConn struct {
+	net.Conn
+	writer io.Writer
+}
+
+func (c *FragmentConn) Write(b []byte) (int, error) {
+	return c.writer.Write(b)
+}
+
+// socketFragmenter fragments the connections dialed with Fragment in their
+// sockopt.
+type socketFragmenter struct {
+	fragment *Fragment
+	strategy FragmentStrategy
+}
+
+func (f *socketFragmenter) writer(conn net.Conn) io.Writer {
+	var w io.Writer = conn
+	if f.fragment.Fake != nil {
+		w = NewFakeWriter(conn, f.fragment.Fake)
+	}
+	return NewFragmentWriter(f.strategy, nil, w)
+}
+
+// fragmenters caches the socketFragmenter built for each SocketConfig, so
+// that its strategy is built once rather than on every dial. Entries are
+// dropped once their SocketConfig is collected.
+var fragmenters sync.Map // weak.Pointer[SocketConfig] -> *socketFragmenter
+
+// getFragmenter returns the socketFragmenter for the fragment settings of sockopt.
+func getFragmenter(sockopt *SocketConfig) (*socketFragmenter, error) {
+	key := weak.Make(sockopt)
+	if fragmenter, ok := fragmenters.Load(key); ok {
+		return fragmenter.(*socketFragmenter), nil
+	}
+	strategy, err := NewFragmentStrategy(sockopt.Fragment)
+	if err != nil {
+		return nil, errors.New("invalid fragment settings").Base(err)
+	}
+	fragmenter := &socketFragmenter{
+		fragment: sockopt.Fragment,
+		strategy: strategy,
+	}
+	if cached, loaded := fragmenters.LoadOrStore(key, fragmenter); loaded {
+		return cached.(*socketFragmenter), nil
+	}
+	runtime.AddCleanup(sockopt, func(key weak.Pointer[SocketConfig]) {
+		fragmenters.Delete(key)
+	}, key)
+	return fragmenter, nil
+}
+
+// fragmentConn wraps conn for the fragment settings of sockopt, if any.
+func fragmentConn(conn net.Conn, dest net.Destination, sockopt *SocketConfig) (net.Conn, error) {
+	if sockopt == nil || sockopt.Fragment == nil || dest.Network != net.Network_TCP {
+		return conn, nil
+	}
+	fragmenter, err := getFragmenter(sockopt)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &FragmentConn{
+		Conn:   conn,
+		writer: fragmenter.writer(conn),
+	}, nil
+}
+
+// reencodeClientHello splits the client hello at the start of b into TLS
+// records of random size. b is returned as is if it doesn't start with one.
+func reencodeClientHello(config *ClientHello, b []byte) []byte {
+	recordLen, ok := IsClientHelloRecord(b)
+	if !ok || config.RecordMax == 0 {
+		return b
+	}
+	records := splitRecord(b, recordLen, randomCuts(5, recordLen, max(config.RecordMin, 1), config.RecordMax))
+	return append(bytes.Join(records, nil), b[recordLen:]...)
+}
+
+// sleepInterval waits for a random interval within the configured range.
+func sleepInterval(fragment *Fragment) {
+	time.Sleep(time.Duration(randBetween(int64(fragment.IntervalMin), int64(fragment.IntervalMax))) * time.Millisecond)
+}
+
+// randLength returns a random fragment length within the configured range.
+func randLength(fragment *Fragment) int {
+	return int(randBetween(int64(fragment.LengthMin), int64(fragment.LengthMax)))
+}
+
+// IsClientHelloRecord reports whether b starts with a complete TLS handshake record.
+func IsClientHelloRecord(b []byte) (recordLen int, ok bool) {
+	if len(b) <= 5 || b[0] != 22 {
+		return 0, false
+	}
+	recordLen = 5 + ((int(b[3]) << 8) | int(b[4]))
+	if len(b) < recordLen { // maybe already fragmented somehow
+		return 0, false
+	}
+	return recordLen, true
+}
+
+// splitRecord re-frames the TLS record b[:recordLen] into several records,
+// cutting its payload at the given offsets relative to the record start.
+func splitRecord(b []byte, recordLen int, cuts []int) [][]byte {
+	records := make([][]byte, 0, len(cuts)+1)
+	from := 5
+	for i := 0; i <= len(cuts); i++ {
+		to := recordLen
+		if i < len(cuts) {
+			to = cuts[i]
+		}
+		if to <= from || to > recordLen {
+			continue
+		}
+		record := make([]byte, 5+to-from)
+		copy(record[:3], b)
+		record[3] = byte((to - from) >> 8)
+		record[4] = byte(to - from)
+		copy(record[5:], b[from:to])
+		records = append(records, record)
+		from = to
+	}
+	return records
+}
+
+// randomCuts returns cut points between from and to spaced by random lengths
+// within [min, max].
+func randomCuts(from, to int, min, max uint64) []int {
+	var cuts []int
+	for {
+		from += int(randBetween(int64(min), int64(max)))
+		if from >= to {
+			return cuts
+		}
+		cuts = append(cuts, from)
+	}
+}
+
+// writeSegments writes b in TCP segments of random length.
+func writeSegments(w io.Writer, fragment *Fragment, b []byte) (int, error) {
+	for from := 0; ; {
+		to := from + randLength(fragment)
+		if to > len(b) {
+			to = len(b)
+		}
+		n, err := w.Write(b[from:to])
+		from += n
+		sleepInterval(fragment)
+		if err != nil {
+			return from, err
+		}
+		if from >= len(b) {
+			return from, nil
+		}
+	}
+}
+
+// writeChunks writes every chunk in its own write call, pausing in between.
+func writeChunks(w io.Writer, fragment *Fragment, chunks [][]byte) error {
+	for _, chunk := range chunks {
+		if _, err := w.Write(chunk); err != nil {
+			return err
+		}
+		sleepInterval(fragment)
+	}
+	return nil
+}
+
+func randBetween(left int64, right int64) int64 {
+	if left == right {
+		return left
+	}
+	bigInt, _ := rand.Int(rand.Reader, big.NewInt(right-left+1))
+	return left + bigInt.Int64()
+}
@@ -0,0 +1,235 @@
+package internet
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"sort"
+
+	"github.com/GFW-knocker/Xray-core/common"
+	"github.com/GFW-knocker/Xray-core/common/errors"
+	"github.com/GFW-knocker/Xray-core/common/protocol/tls"
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	common.Must(RegisterFragmentStrategy(FragmentStrategyFakeHost, func(fragment *Fragment, _ proto.Message) (FragmentStrategy, error) {
+		return &FakeHostStrategy{fragment: fragment}, nil
+	}))
+	common.Must(RegisterFragmentStrategy(FragmentStrategyTCPSegment, func(fragment *Fragment, _ proto.Message) (FragmentStrategy, error) {
+		return &TCPSegmentStrategy{fragment: fragment}, nil
+	}))
+	common.Must(RegisterFragmentStrategy(FragmentStrategyTLSRecord, func(fragment *Fragment, settings proto.Message) (FragmentStrategy, error) {
+		config := &TLSRecordFragmentConfig{BatchMin: 3, BatchMax: 5}
+		if settings != nil {
+			s, ok := settings.(*TLSRecordFragmentConfig)
+			if !ok {
+				return nil, errors.New("not a TLSRecordFragmentConfig").AtError()
+			}
+			config = s
+		}
+		return &TLSRecordStrategy{fragment: fragment, config: config}, nil
+	}))
+	common.Must(RegisterFragmentStrategy(FragmentStrategySNI, func(fragment *Fragment, settings proto.Message) (FragmentStrategy, error) {
+		config := &SNIFragmentConfig{}
+		if settings != nil {
+			s, ok := settings.(*SNIFragmentConfig)
+			if !ok {
+				return nil, errors.New("not a SNIFragmentConfig").AtError()
+			}
+			config = s
+		}
+		return &SNIStrategy{fragment: fragment, config: config}, nil
+	}))
+	common.Must(RegisterFragmentStrategy(FragmentStrategyMixed, func(fragment *Fragment, _ proto.Message) (FragmentStrategy, error) {
+		return &MixedStrategy{fragment: fragment}, nil
+	}))
+}
+
+var re = regexp.MustCompile("(?i)(\r\nHost:.*\r\n)")
+
+// FakeHostStrategy surrounds the Host header of plain HTTP requests with
+// fake ones, without splitting anything.
+type FakeHostStrategy struct {
+	fragment *Fragment
+}
+
+func (s *FakeHostStrategy) Write(w io.Writer, b []byte, count uint64) (int, error) {
+	if count < 500 {
+		if len(b) >= 5 {
+			if (b[0] == 'P' && b[1] == 'O' && b[2] == 'S' && b[3] == 'T' && b[4] == ' ') ||
+				(b[0] == 'G' && b[1] == 'E' && b[2] == 'T' && b[3] == ' ') {
+
+				firstMatch := re.FindSubmatch(b)
+				if len(firstMatch) > 1 {
+					var new_b []byte
+					old_h := firstMatch[1]
+					new_h := []byte("\r\n" + s.fragment.Host1Header + s.fragment.Host1Domain + string(old_h) + s.fragment.Host2Header + s.fragment.Host2Domain + "\r\n")
+					new_b = bytes.Replace(b, old_h, new_h, 1)
+					return w.Write(new_b)
+				}
+			}
+		}
+	}
+	return w.Write(b)
+}
+
+// TCPSegmentStrategy chops the writes within the packets range into TCP
+// segments of random length.
+type TCPSegmentStrategy struct {
+	fragment *Fragment
+}
+
+func (s *TCPSegmentStrategy) Write(w io.Writer, b []byte, count uint64) (int, error) {
+	if s.fragment.PacketsFrom != 0 && (count < s.fragment.PacketsFrom || count > s.fragment.PacketsTo) {
+		return w.Write(b)
+	}
+	return writeSegments(w, s.fragment, b)
+}
+
+// TLSRecordStrategy splits the first TLS handshake record into several
+// smaller records, and sends a random number of them per TCP write.
+type TLSRecordStrategy struct {
+	fragment *Fragment
+	config   *TLSRecordFragmentConfig
+}
+
+func (s *TLSRecordStrategy) Write(w io.Writer, b []byte, count uint64) (int, error) {
+	recordLen, ok := IsClientHelloRecord(b)
+	if count != 1 || !ok {
+		return w.Write(b)
+	}
+	records := splitRecord(b, recordLen, randomCuts(5, recordLen, s.fragment.LengthMin, s.fragment.LengthMax))
+	batch := int(randBetween(int64(s.config.BatchMin), int64(s.config.BatchMax)))
+	if batch < 1 {
+		batch = 1
+	}
+	chunks := make([][]byte, 0, len(records)/batch+1)
+	for len(records) > 0 {
+		n := min(batch, len(records))
+		chunks = append(chunks, bytes.Join(records[:n], nil))
+		records = records[n:]
+	}
+	if err := writeChunks(w, s.fragment, chunks); err != nil {
+		return 0, err
+	}
+	return writeRest(w, b, recordLen)
+}
+
+// SNIStrategy cuts the first TLS handshake record at fixed points inside the
+// server name, and optionally right before the ALPN and key_share extensions,
+// so that no single segment carries the whole of them.
+type SNIStrategy struct {
+	fragment *Fragment
+	config   *SNIFragmentConfig
+}
+
+func (s *SNIStrategy) Write(w io.Writer, b []byte, count uint64) (int, error) {
+	recordLen, ok := IsClientHelloRecord(b)
+	if count != 1 || !ok {
+		return w.Write(b)
+	}
+	cuts := s.cuts(b)
+	if len(cuts) == 0 {
+		return w.Write(b)
+	}
+	var chunks [][]byte
+	if s.config.TlsRecord {
+		chunks = splitRecord(b, recordLen, cuts)
+	} else {
+		from := 0
+		for _, cut := range append(cuts, recordLen) {
+			chunks = append(chunks, b[from:cut])
+			from = cut
+		}
+	}
+	if err := writeChunks(w, s.fragment, chunks); err != nil {
+		return 0, err
+	}
+	return writeRest(w, b, recordLen)
+}
+
+// cuts returns the sorted and distinct offsets at which the client hello in
+// b should be cut.
+func (s *SNIStrategy) cuts(b []byte) []int {
+	var cuts []int
+	if start, end, err := tls.FindServerName(b); err == nil {
+		n := int(s.config.ServerNameCuts)
+		if n < 1 {
+			n = 1
+		}
+		for i := 1; i <= n; i++ {
+			if cut := start + i*(end-start)/(n+1); cut > start {
+				cuts = append(cuts, cut)
+			}
+		}
+	}
+	if s.config.SplitAlpn || s.config.SplitKeyShare {
+		extensions, _ := tls.FindExtensions(b)
+		for _, e := range extensions {
+			if (s.config.SplitAlpn && e.Type == tls.ExtensionALPN) ||
+				(s.config.SplitKeyShare && e.Type == tls.ExtensionKeyShare) {
+				cuts = append(cuts, e.Start)
+			}
+		}
+	}
+	sort.Ints(cuts)
+	distinct := cuts[:0]
+	for _, cut := range cuts {
+		if cut > 5 && (len(distinct) == 0 || cut > distinct[len(distinct)-1]) {
+			distinct = append(distinct, cut)
+		}
+	}
+	return distinct
+}
+
+// MixedStrategy splits the first TLS handshake record into records of
+// random length, one of them ending inside the server name, and then sends
+// every record in TCP segments of random length. Other writes are handled
+// like TCPSegmentStrategy does.
+type MixedStrategy struct {
+	fragment *Fragment
+}
+
+func (s *MixedStrategy) Write(w io.Writer, b []byte, count uint64) (int, error) {
+	recordLen, ok := IsClientHelloRecord(b)
+	if count != 1 || !ok {
+		if s.fragment.PacketsFrom != 0 && (count < s.fragment.PacketsFrom || count > s.fragment.PacketsTo) {
+			return w.Write(b)
+		}
+		return writeSegments(w, s.fragment, b)
+	}
+	cuts := randomCuts(5, recordLen, s.fragment.LengthMin, s.fragment.LengthMax)
+	if cut, ok := serverNameCut(b); ok {
+		cuts = append(cuts, cut)
+		sort.Ints(cuts)
+	}
+	for _, record := range splitRecord(b, recordLen, cuts) {
+		if _, err := writeSegments(w, s.fragment, record); err != nil {
+			return 0, err
+		}
+	}
+	return writeRest(w, b, recordLen)
+}
+
+// serverNameCut returns an offset that falls in the middle of the server
+// name of the client hello in b.
+func serverNameCut(b []byte) (int, bool) {
+	start, end, err := tls.FindServerName(b)
+	if err != nil || end-start < 2 {
+		return 0, false
+	}
+	return start + (end-start)/2, true
+}
+
+// writeRest writes what follows the first record of b and reports the
+// whole of b as written.
+func writeRest(w io.Writer, b []byte, recordLen int) (int, error) {
+	if len(b) > recordLen {
+		n, err := w.Write(b[recordLen:])
+		if err != nil {
+			return recordLen + n, err
+		}
+	}
+	return len(b), nil
+}
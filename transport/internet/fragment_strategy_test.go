@@ -0,0 +1,309 @@
+package internet_test
+
+import (
+	"bytes"
+	gotls "crypto/tls"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/GFW-knocker/Xray-core/common"
+	"github.com/GFW-knocker/Xray-core/common/protocol/tls"
+	"github.com/GFW-knocker/Xray-core/common/protocol/tls/cert"
+	"github.com/GFW-knocker/Xray-core/common/serial"
+	. "github.com/GFW-knocker/Xray-core/transport/internet"
+)
+
+type recordWriter struct {
+	writes [][]byte
+}
+
+func (w *recordWriter) Write(b []byte) (int, error) {
+	w.writes = append(w.writes, append([]byte(nil), b...))
+	return len(b), nil
+}
+
+// clientHello returns the first TLS record sent by a Go TLS client.
+func clientHello(t *testing.T, serverName string) []byte {
+	client, server := net.Pipe()
+	defer server.Close()
+	go func() {
+		gotls.Client(client, &gotls.Config{
+			ServerName: serverName,
+			NextProtos: []string{"h2", "http/1.1"},
+		}).Handshake()
+		client.Close()
+	}()
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(server, header); err != nil {
+		t.Fatal(err)
+	}
+	body := make([]byte, int(header[3])<<8|int(header[4]))
+	if _, err := io.ReadFull(server, body); err != nil {
+		t.Fatal(err)
+	}
+	return append(header, body...)
+}
+
+// recordPayload concatenates the payloads of all TLS records in b.
+func recordPayload(t *testing.T, b []byte) []byte {
+	var payload []byte
+	for len(b) > 0 {
+		if len(b) < 5 || b[0] != 22 {
+			t.Fatal("invalid TLS record")
+		}
+		l := int(b[3])<<8 | int(b[4])
+		if len(b) < 5+l {
+			t.Fatal("truncated TLS record")
+		}
+		payload = append(payload, b[5:5+l]...)
+		b = b[5+l:]
+	}
+	return payload
+}
+
+func TestNewFragmentStrategy(t *testing.T) {
+	cases := []struct {
+		fragment *Fragment
+		strategy FragmentStrategy
+	}{
+		{
+			fragment: &Fragment{PacketsFrom: 0, PacketsTo: 1, LengthMin: 1, LengthMax: 1},
+			strategy: &TLSRecordStrategy{},
+		},
+		{
+			fragment: &Fragment{PacketsFrom: 1, PacketsTo: 1, FakeHost: true},
+			strategy: &FakeHostStrategy{},
+		},
+		{
+			fragment: &Fragment{PacketsFrom: 1, PacketsTo: 3, LengthMin: 1, LengthMax: 1},
+			strategy: &TCPSegmentStrategy{},
+		},
+		{
+			fragment: &Fragment{Strategy: "SNI", StrategySettings: serial.ToTypedMessage(&SNIFragmentConfig{TlsRecord: true})},
+			strategy: &SNIStrategy{},
+		},
+		{
+			fragment: &Fragment{Strategy: "mixed"},
+			strategy: &MixedStrategy{},
+		},
+	}
+	for _, c := range cases {
+		strategy, err := NewFragmentStrategy(c.fragment)
+		common.Must(err)
+		if got, want := typeName(strategy), typeName(c.strategy); got != want {
+			t.Error("expect strategy ", want, " but got ", got)
+		}
+	}
+
+	if _, err := NewFragmentStrategy(&Fragment{Strategy: "unknown"}); err == nil {
+		t.Error("expect error for unknown strategy")
+	}
+	if _, err := NewFragmentStrategy(&Fragment{Strategy: "sni", StrategySettings: serial.ToTypedMessage(&TLSRecordFragmentConfig{})}); err == nil {
+		t.Error("expect error for mismatched settings")
+	}
+}
+
+func typeName(s FragmentStrategy) string {
+	switch s.(type) {
+	case *FakeHostStrategy:
+		return "fakehost"
+	case *TCPSegmentStrategy:
+		return "tcpsegment"
+	case *TLSRecordStrategy:
+		return "tlsrecord"
+	case *SNIStrategy:
+		return "sni"
+	case *MixedStrategy:
+		return "mixed"
+	}
+	return "other"
+}
+
+func TestSNIFragmentStrategy(t *testing.T) {
+	const domain = "www.example.com"
+	hello := clientHello(t, domain)
+
+	for _, tlsRecord := range []bool{false, true} {
+		strategy, err := NewFragmentStrategy(&Fragment{
+			Strategy:         FragmentStrategySNI,
+			StrategySettings: serial.ToTypedMessage(&SNIFragmentConfig{TlsRecord: tlsRecord}),
+		})
+		common.Must(err)
+
+		w := &recordWriter{}
+		n, err := strategy.Write(w, hello, 1)
+		common.Must(err)
+		if n != len(hello) {
+			t.Error("expect ", len(hello), " bytes written but got ", n)
+		}
+		if len(w.writes) != 2 {
+			t.Fatal("expect 2 writes but got ", len(w.writes))
+		}
+		for _, b := range w.writes {
+			if bytes.Contains(b, []byte(domain)) {
+				t.Error("server name is not split")
+			}
+		}
+		joined := bytes.Join(w.writes, nil)
+		if tlsRecord {
+			if !bytes.Equal(recordPayload(t, joined), hello[5:]) {
+				t.Error("client hello is corrupted")
+			}
+		} else if !bytes.Equal(joined, hello) {
+			t.Error("client hello is corrupted")
+		}
+	}
+}
+
+func TestTLSRecordFragmentStrategy(t *testing.T) {
+	hello := clientHello(t, "www.example.com")
+	strategy, err := NewFragmentStrategy(&Fragment{
+		PacketsFrom: 0,
+		PacketsTo:   1,
+		LengthMin:   20,
+		LengthMax:   40,
+		StrategySettings: serial.ToTypedMessage(&TLSRecordFragmentConfig{
+			BatchMin: 1,
+			BatchMax: 1,
+		}),
+	})
+	common.Must(err)
+
+	w := &recordWriter{}
+	common.Must2(strategy.Write(w, append(hello, 1, 2, 3), 1))
+	if len(w.writes) < (len(hello)-5)/40 {
+		t.Error("client hello is not split into records, got ", len(w.writes), " writes")
+	}
+	joined := bytes.Join(w.writes[:len(w.writes)-1], nil)
+	if !bytes.Equal(recordPayload(t, joined), hello[5:]) {
+		t.Error("client hello is corrupted")
+	}
+	if !bytes.Equal(w.writes[len(w.writes)-1], []byte{1, 2, 3}) {
+		t.Error("data after client hello is corrupted")
+	}
+}
+
+func TestMixedFragmentStrategy(t *testing.T) {
+	const domain = "www.example.com"
+	hello := clientHello(t, domain)
+	strategy, err := NewFragmentStrategy(&Fragment{
+		Strategy:  FragmentStrategyMixed,
+		LengthMin: 100,
+		LengthMax: 200,
+	})
+	common.Must(err)
+
+	w := &recordWriter{}
+	common.Must2(strategy.Write(w, hello, 1))
+	for _, b := range w.writes {
+		if bytes.Contains(b, []byte(domain)) {
+			t.Error("server name is not split")
+		}
+	}
+	if !bytes.Equal(recordPayload(t, bytes.Join(w.writes, nil)), hello[5:]) {
+		t.Error("client hello is corrupted")
+	}
+}
+
+func TestSNIFragmentStrategyExtensions(t *testing.T) {
+	const domain = "www.example.com"
+	hello := clientHello(t, domain)
+	strategy, err := NewFragmentStrategy(&Fragment{
+		Strategy: FragmentStrategySNI,
+		StrategySettings: serial.ToTypedMessage(&SNIFragmentConfig{
+			ServerNameCuts: 3,
+			SplitAlpn:      true,
+			SplitKeyShare:  true,
+		}),
+	})
+	common.Must(err)
+
+	w := &recordWriter{}
+	common.Must2(strategy.Write(w, hello, 1))
+	if len(w.writes) != 6 {
+		t.Fatal("expect 6 writes but got ", len(w.writes))
+	}
+	if !bytes.Equal(bytes.Join(w.writes, nil), hello) {
+		t.Error("client hello is corrupted")
+	}
+
+	extensions, err := tls.FindExtensions(hello)
+	common.Must(err)
+	starts := make(map[int]uint16)
+	for _, e := range extensions {
+		starts[e.Start] = e.Type
+	}
+	found := make(map[uint16]bool)
+	offset := 0
+	for _, b := range w.writes {
+		found[starts[offset]] = true
+		offset += len(b)
+	}
+	if !found[tls.ExtensionALPN] || !found[tls.ExtensionKeyShare] {
+		t.Error("client hello is not cut before ALPN and key_share")
+	}
+}
+
+// writerConn is a connection whose writes go through another writer.
+type writerConn struct {
+	net.Conn
+	writer io.Writer
+}
+
+func (c *writerConn) Write(b []byte) (int, error) {
+	return c.writer.Write(b)
+}
+
+func TestClientHelloRecords(t *testing.T) {
+	certificate, key := cert.MustGenerate(nil, cert.CommonName("www.example.com")).ToPEM()
+	keyPair, err := gotls.X509KeyPair(certificate, key)
+	common.Must(err)
+
+	for _, fragment := range []*Fragment{nil, {PacketsFrom: 1, PacketsTo: 1, LengthMin: 100, LengthMax: 100}} {
+		var strategy FragmentStrategy
+		if fragment != nil {
+			strategy, err = NewFragmentStrategy(fragment)
+			common.Must(err)
+		}
+		client, server := net.Pipe()
+		sent := &bytes.Buffer{}
+		conn := &writerConn{
+			Conn:   client,
+			writer: NewFragmentWriter(strategy, &ClientHello{RecordMin: 30, RecordMax: 60}, io.MultiWriter(sent, client)),
+		}
+
+		done := make(chan error, 1)
+		go func() {
+			done <- gotls.Server(server, &gotls.Config{Certificates: []gotls.Certificate{keyPair}}).Handshake()
+		}()
+		if err := gotls.Client(conn, &gotls.Config{InsecureSkipVerify: true}).Handshake(); err != nil {
+			t.Fatal("client handshake failed: ", err)
+		}
+		if err := <-done; err != nil {
+			t.Fatal("server handshake failed: ", err)
+		}
+		client.Close()
+		server.Close()
+
+		// re-parse the records carrying the client hello
+		b := sent.Bytes()
+		var payload []byte
+		records := 0
+		for len(payload) < 4 || len(payload) < 4+(int(payload[1])<<16|int(payload[2])<<8|int(payload[3])) {
+			if len(b) < 5 || b[0] != 22 {
+				t.Fatal("invalid TLS record")
+			}
+			l := int(b[3])<<8 | int(b[4])
+			if l > 60 {
+				t.Error("record of ", l, " bytes is larger than allowed")
+			}
+			payload = append(payload, b[5:5+l]...)
+			b = b[5+l:]
+			records++
+		}
+		if payload[0] != 1 || records < 2 {
+			t.Error("client hello is not re-encoded into records")
+		}
+	}
+}
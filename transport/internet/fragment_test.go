@@ -0,0 +1,100 @@
+package internet_test
+
+import (
+	"context"
+	gotls "crypto/tls"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/GFW-knocker/Xray-core/common"
+	"github.com/GFW-knocker/Xray-core/common/net"
+	"github.com/GFW-knocker/Xray-core/common/protocol/tls/cert"
+	"github.com/GFW-knocker/Xray-core/common/serial"
+	"github.com/GFW-knocker/Xray-core/infra/conf"
+	"github.com/GFW-knocker/Xray-core/testing/servers/tcp"
+	. "github.com/GFW-knocker/Xray-core/transport/internet"
+)
+
+func TestDialSystemFragment(t *testing.T) {
+	server := &tcp.Server{
+		MsgProcessor: func(msg []byte) []byte { return msg },
+	}
+	dest, err := server.Start()
+	common.Must(err)
+	defer server.Close()
+
+	var config conf.SocketConfig
+	common.Must(json.Unmarshal([]byte(`{
+		"fragment": {
+			"packets": "1-1",
+			"length": "2-3",
+			"interval": "0"
+		}
+	}`), &config))
+	sockopt, err := config.Build()
+	common.Must(err)
+
+	payload := []byte("GET / HTTP/1.1\r\nHost: www.example.com\r\n\r\n")
+	for range 2 {
+		conn, err := DialSystem(context.Background(), net.TCPDestination(net.LocalHostIP, dest.Port), sockopt)
+		common.Must(err)
+		if _, ok := conn.(*FragmentConn); !ok {
+			t.Fatal("connection is not fragmented")
+		}
+		common.Must2(conn.Write(payload))
+		b := make([]byte, len(payload))
+		common.Must2(io.ReadFull(conn, b))
+		if string(b) != string(payload) {
+			t.Error("unexpected echo ", string(b))
+		}
+		conn.Close()
+	}
+
+	udp, err := DialSystem(context.Background(), net.UDPDestination(net.LocalHostIP, dest.Port), sockopt)
+	common.Must(err)
+	if _, ok := udp.(*FragmentConn); ok {
+		t.Error("UDP connection is fragmented")
+	}
+	udp.Close()
+}
+
+func TestDialSystemFragmentTLS(t *testing.T) {
+	certificate, key := cert.MustGenerate(nil, cert.CommonName("www.example.com")).ToPEM()
+	keyPair, err := gotls.X509KeyPair(certificate, key)
+	common.Must(err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	common.Must(err)
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		server := gotls.Server(conn, &gotls.Config{Certificates: []gotls.Certificate{keyPair}})
+		io.Copy(server, server)
+		server.Close()
+	}()
+
+	sockopt := &SocketConfig{
+		Fragment: &Fragment{
+			Strategy:         FragmentStrategySNI,
+			StrategySettings: serial.ToTypedMessage(&SNIFragmentConfig{TlsRecord: true}),
+		},
+	}
+	port := net.Port(listener.Addr().(*net.TCPAddr).Port)
+	conn, err := DialSystem(context.Background(), net.TCPDestination(net.LocalHostIP, port), sockopt)
+	common.Must(err)
+	client := gotls.Client(conn, &gotls.Config{
+		ServerName:         "www.example.com",
+		InsecureSkipVerify: true,
+	})
+	defer client.Close()
+	common.Must2(client.Write([]byte("hello")))
+	b := make([]byte, 5)
+	common.Must2(io.ReadFull(client, b))
+	if string(b) != "hello" {
+		t.Error("unexpected echo ", string(b))
+	}
+}
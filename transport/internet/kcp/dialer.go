@@ -10,6 +10,7 @@ import (
 	"github.com/GFW-knocker/Xray-core/common/dice"
 	"github.com/GFW-knocker/Xray-core/common/errors"
 	"github.com/GFW-knocker/Xray-core/common/net"
+	"github.com/GFW-knocker/Xray-core/common/noise"
 	"github.com/GFW-knocker/Xray-core/transport/internet"
 	"github.com/GFW-knocker/Xray-core/transport/internet/stat"
 	"github.com/GFW-knocker/Xray-core/transport/internet/tls"
@@ -74,12 +75,29 @@ func DialKCP(ctx context.Context, dest net.Destination, streamSettings *internet
 		Writer:   rawConn,
 	}
 
+	var closer io.Closer = rawConn
+	if kcpSettings.Noise != nil {
+		sender, err := noise.NewSender(kcpSettings.Noise, dest.Port, func(b []byte) error {
+			_, err := rawConn.Write(b)
+			return err
+		})
+		if err == nil {
+			err = sender.Send()
+		}
+		if err != nil {
+			rawConn.Close()
+			return nil, errors.New("failed to send noise").Base(err)
+		}
+		sender.KeepAlive()
+		closer = common.ChainedClosable{sender, rawConn}
+	}
+
 	conv := uint16(atomic.AddUint32(&globalConv, 1))
 	session := NewConnection(ConnMetadata{
 		LocalAddr:    rawConn.LocalAddr(),
 		RemoteAddr:   rawConn.RemoteAddr(),
 		Conversation: conv,
-	}, writer, rawConn, kcpSettings)
+	}, writer, closer, kcpSettings)
 
 	go fetchInput(ctx, rawConn, reader, session)
 
@@ -8,6 +8,7 @@ import (
 	"github.com/GFW-knocker/Xray-core/common"
 	"github.com/GFW-knocker/Xray-core/common/errors"
 	"github.com/GFW-knocker/Xray-core/common/net"
+	"github.com/GFW-knocker/Xray-core/common/noise"
 	"github.com/GFW-knocker/Xray-core/common/task"
 	"github.com/GFW-knocker/Xray-core/transport/internet"
 	"github.com/GFW-knocker/Xray-core/transport/internet/stat"
@@ -20,6 +21,7 @@ import (
 type connectionContext struct {
 	rawConn *sysConn
 	conn    quic.Conn
+	noise   *noise.Sender
 }
 
 var errConnectionClosed = errors.New("connection closed")
@@ -73,6 +75,9 @@ func removeInactiveConnections(conns []*connectionContext) []*connectionContext
 		if err := s.rawConn.Close(); err != nil {
 			errors.LogInfoInner(context.Background(), err, "failed to close raw connection")
 		}
+		if s.noise != nil {
+			s.noise.Close()
+		}
 	}
 
 	if len(activeConnections) < len(conns) {
@@ -165,6 +170,25 @@ func (s *clientConnections) openConnection(ctx context.Context, destAddr net.Add
 		rawConn.Close()
 		return nil, err
 	}
+	var sender *noise.Sender
+	if config.Noise != nil {
+		// noise goes out as is, without the header and security of sysConn
+		sender, err = noise.NewSender(config.Noise, dest.Port, func(b []byte) error {
+			if udpConn.RemoteAddr() != nil {
+				_, err := udpConn.Write(b)
+				return err
+			}
+			_, err := udpConn.WriteTo(b, destAddr)
+			return err
+		})
+		if err == nil {
+			err = sender.Send()
+		}
+		if err != nil {
+			sysConn.Close()
+			return nil, errors.New("failed to send noise").Base(err)
+		}
+	}
 	tr := quic.Transport{
 		ConnectionIDLength: 12,
 		Conn:               sysConn,
@@ -174,10 +198,14 @@ func (s *clientConnections) openConnection(ctx context.Context, destAddr net.Add
 		sysConn.Close()
 		return nil, err
 	}
+	if sender != nil {
+		sender.KeepAlive()
+	}
 
 	context := &connectionContext{
 		conn:    *conn,
 		rawConn: sysConn,
+		noise:   sender,
 	}
 	s.conns[dest] = append(conns, context)
 	return context.openStream(destAddr)
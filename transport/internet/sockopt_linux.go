@@ -271,3 +271,45 @@ func setReusePort(fd uintptr) error {
 	}
 	return nil
 }
+
+// setTTL sets the TTL, and the hop limit on IPv6 sockets, of the packets sent
+// on fd. It returns a function which restores the previous values.
+func setTTL(fd uintptr, ttl int) (func(fd uintptr) error, error) {
+	domain, err := unix.GetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_DOMAIN)
+	if err != nil {
+		return nil, err
+	}
+	oldTTL, err := unix.GetsockoptInt(int(fd), unix.IPPROTO_IP, unix.IP_TTL)
+	if domain != unix.AF_INET6 {
+		if err != nil {
+			return nil, err
+		}
+		restore := func(fd uintptr) error {
+			return unix.SetsockoptInt(int(fd), unix.IPPROTO_IP, unix.IP_TTL, oldTTL)
+		}
+		return restore, unix.SetsockoptInt(int(fd), unix.IPPROTO_IP, unix.IP_TTL, ttl)
+	}
+
+	// dual stack sockets use the IPv4 TTL for IPv4 peers, so both are set
+	// where the socket has an IPv4 TTL
+	dualStack := err == nil && unix.SetsockoptInt(int(fd), unix.IPPROTO_IP, unix.IP_TTL, ttl) == nil
+	oldHops, err := unix.GetsockoptInt(int(fd), unix.IPPROTO_IPV6, unix.IPV6_UNICAST_HOPS)
+	if err == nil {
+		err = unix.SetsockoptInt(int(fd), unix.IPPROTO_IPV6, unix.IPV6_UNICAST_HOPS, ttl)
+	}
+	restore := func(fd uintptr) error {
+		if dualStack {
+			if err := unix.SetsockoptInt(int(fd), unix.IPPROTO_IP, unix.IP_TTL, oldTTL); err != nil {
+				return err
+			}
+		}
+		return unix.SetsockoptInt(int(fd), unix.IPPROTO_IPV6, unix.IPV6_UNICAST_HOPS, oldHops)
+	}
+	if err != nil {
+		if dualStack {
+			unix.SetsockoptInt(int(fd), unix.IPPROTO_IP, unix.IP_TTL, oldTTL)
+		}
+		return nil, err
+	}
+	return restore, nil
+}
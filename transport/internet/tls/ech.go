@@ -8,6 +8,7 @@ import (
 	"crypto/tls"
 	"encoding/base64"
 	"encoding/binary"
+	go_errors "errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -32,6 +33,10 @@ import (
 	"golang.org/x/crypto/cryptobyte"
 )
 
+// BuiltinECHDNSServer is the ECH DNS server which queries ECH configs with the
+// DNS of the instance, following its nameservers and rules.
+const BuiltinECHDNSServer = "xray://dns"
+
 func ApplyECH(c *Config, config *tls.Config) error {
 	var ECHConfig []byte
 	var err error
@@ -195,6 +200,9 @@ func QueryRecord(domain string, server string, forceQuery string, sockopt *inter
 // dnsQuery is the real func for sending type65 query for given domain to given DNS server.
 // return ECH config, TTL and error
 func dnsQuery(server string, domain string, sockopt *internet.SocketConfig) ([]byte, uint32, error) {
+	if server == BuiltinECHDNSServer {
+		return builtinDNSQuery(domain)
+	}
 	m := new(dns.Msg)
 	var dnsResolve []byte
 	m.SetQuestion(dns.Fqdn(domain), dns.TypeHTTPS)
@@ -312,14 +320,27 @@ func dnsQuery(server string, domain string, sockopt *internet.SocketConfig) ([]b
 	if err != nil {
 		return nil, 0, errors.New("failed to unpack dns response for ECH: ", err)
 	}
-	if len(respMsg.Answer) > 0 {
-		for _, answer := range respMsg.Answer {
-			if https, ok := answer.(*dns.HTTPS); ok && https.Hdr.Name == dns.Fqdn(domain) {
-				for _, v := range https.Value {
-					if echConfig, ok := v.(*dns.SVCBECHConfig); ok {
-						errors.LogDebug(context.Background(), "Get ECH config:", echConfig.String(), " TTL:", respMsg.Answer[0].Header().Ttl)
-						return echConfig.ECH, answer.Header().Ttl, nil
-					}
+	return echConfigFromAnswers(domain, respMsg.Answer)
+}
+
+// builtinDNSQuery queries the HTTPS records of domain with the DNS of the instance.
+func builtinDNSQuery(domain string) ([]byte, uint32, error) {
+	answers, _, err := internet.LookupRecords(domain, dns.TypeHTTPS)
+	if err != nil && !go_errors.Is(err, dns2.ErrEmptyResponse) {
+		return nil, 0, err
+	}
+	return echConfigFromAnswers(domain, answers)
+}
+
+// echConfigFromAnswers returns the ECH config in the HTTPS records of domain,
+// with its TTL.
+func echConfigFromAnswers(domain string, answers []dns.RR) ([]byte, uint32, error) {
+	for _, answer := range answers {
+		if https, ok := answer.(*dns.HTTPS); ok && https.Hdr.Name == dns.Fqdn(domain) {
+			for _, v := range https.Value {
+				if echConfig, ok := v.(*dns.SVCBECHConfig); ok {
+					errors.LogDebug(context.Background(), "Get ECH config:", echConfig.String(), " TTL:", answer.Header().Ttl)
+					return echConfig.ECH, answer.Header().Ttl, nil
 				}
 			}
 		}
@@ -1,6 +1,7 @@
 package tls
 
 import (
+	"bytes"
 	"io"
 	"net/http"
 	"strings"
@@ -8,6 +9,11 @@ import (
 	"testing"
 
 	"github.com/GFW-knocker/Xray-core/common"
+	"github.com/GFW-knocker/Xray-core/common/errors"
+	"github.com/GFW-knocker/Xray-core/common/net"
+	dns2 "github.com/GFW-knocker/Xray-core/features/dns"
+	"github.com/GFW-knocker/Xray-core/transport/internet"
+	"github.com/miekg/dns"
 )
 
 func TestECHDial(t *testing.T) {
@@ -77,3 +83,64 @@ func TestECHDialFail(t *testing.T) {
 		t.Error("unexpected nil error in ECH config record")
 	}
 }
+
+// recordClient answers record lookups from records, or err.
+type recordClient struct {
+	records map[uint16][]dns.RR
+	err     error
+}
+
+func (*recordClient) Type() interface{} {
+	return dns2.ClientType()
+}
+
+func (*recordClient) Start() error {
+	return nil
+}
+
+func (*recordClient) Close() error {
+	return nil
+}
+
+func (*recordClient) LookupIP(domain string, option dns2.IPOption) ([]net.IP, uint32, error) {
+	return nil, 0, dns2.ErrEmptyResponse
+}
+
+func (c *recordClient) LookupRecords(domain string, qtype uint16) ([]dns.RR, uint32, error) {
+	return c.records[qtype], 300, c.err
+}
+
+func TestBuiltinECHDNSQuery(t *testing.T) {
+	https := func(name string, ech []byte) dns.RR {
+		return &dns.HTTPS{SVCB: dns.SVCB{
+			Hdr:      dns.RR_Header{Name: name, Rrtype: dns.TypeHTTPS, Class: dns.ClassINET, Ttl: 300},
+			Priority: 1,
+			Target:   ".",
+			Value:    []dns.SVCBKeyValue{&dns.SVCBECHConfig{ECH: ech}},
+		}}
+	}
+	client := &recordClient{records: map[uint16][]dns.RR{
+		dns.TypeHTTPS: {https("other.com.", []byte{4, 5, 6}), https("example.com.", []byte{1, 2, 3})},
+	}}
+	internet.InitSystemDialer(client, nil)
+	defer internet.InitSystemDialer(nil, nil)
+
+	config, ttl, err := dnsQuery(BuiltinECHDNSServer, "example.com", nil)
+	common.Must(err)
+	if !bytes.Equal(config, []byte{1, 2, 3}) || ttl != 300 {
+		t.Error("unexpected ECH config ", config, " with TTL ", ttl)
+	}
+
+	// no HTTPS record is no ECH config, not an error
+	client.records, client.err = nil, dns2.ErrEmptyResponse
+	config, ttl, err = dnsQuery(BuiltinECHDNSServer, "example.com", nil)
+	common.Must(err)
+	if config != nil || ttl != dns2.DefaultTTL {
+		t.Error("unexpected ECH config ", config, " with TTL ", ttl)
+	}
+
+	client.err = errors.New("server failure")
+	if _, _, err := dnsQuery(BuiltinECHDNSServer, "example.com", nil); err == nil {
+		t.Error("expect error of failed lookup")
+	}
+}